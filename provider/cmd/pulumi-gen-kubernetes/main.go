@@ -169,7 +169,7 @@ func writeNodeJSClient(pkg *schema.Package, outdir, templateDir string) {
 
 	templateResources := gen.TemplateResources{}
 	for _, resource := range resources {
-		if resource.Package == "" {
+		if resource.Package == "" || resource.Token == gen.PatchResourceToken {
 			continue
 		}
 		tr := gen.TemplateResource{
@@ -218,6 +218,9 @@ func writePythonClient(pkg *schema.Package, outdir string, templateDir string) {
 
 	templateResources := gen.TemplateResources{}
 	for _, resource := range resources {
+		if resource.Token == gen.PatchResourceToken {
+			continue
+		}
 		r := gen.TemplateResource{
 			Name:    resource.Name,
 			Package: resource.Package,
@@ -253,6 +256,9 @@ func writeDotnetClient(pkg *schema.Package, outdir, templateDir string) {
 
 	templateResources := gen.TemplateResources{}
 	for _, resource := range resources {
+		if resource.Token == gen.PatchResourceToken {
+			continue
+		}
 		r := gen.TemplateResource{
 			Name:    resource.Name,
 			Package: resource.Package,
@@ -309,6 +315,9 @@ func writeGoClient(pkg *schema.Package, outdir string, templateDir string) {
 
 	templateResources := gen.GoTemplateResources{}
 	for _, resource := range resources {
+		if resource.Token == gen.PatchResourceToken {
+			continue
+		}
 		r := gen.TemplateResource{
 			Alias:   resource.Alias,
 			Name:    resource.Name,
@@ -370,6 +379,9 @@ func mustRenderGoTemplate(path string, resources interface{}) []byte {
 func genK8sResourceTypes(pkg *schema.Package) {
 	groupVersions, kinds := codegen.NewStringSet(), codegen.NewStringSet()
 	for _, resource := range pkg.Resources {
+		if resource.Token == gen.PatchResourceToken {
+			continue
+		}
 		parts := strings.Split(resource.Token, ":")
 		contract.Assert(len(parts) == 3)
 
@@ -0,0 +1,137 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package await
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// DefaultFieldManager is the field manager used for server-side apply if the `fieldManager` provider
+// config is unset.
+const DefaultFieldManager = "pulumi-kubernetes"
+
+// ServerSideApply applies the object with server-side apply, which creates the object if it does not
+// exist. Unlike a client-side patch, only the fields set in the object are owned by the field manager,
+// so fields set by other controllers are left as they are. If other field managers own some of the
+// applied fields, the apply fails with a `FieldManagerConflictError`, unless the object has the
+// `pulumi.com/patchForce` annotation.
+func ServerSideApply(
+	client dynamic.ResourceInterface, obj *unstructured.Unstructured, fieldManager string, dryRun bool,
+) (*unstructured.Unstructured, error) {
+	forceOption := fmt.Sprintf("set the %q annotation to %q", metadata.AnnotationPatchForce, metadata.AnnotationTrue)
+	return serverSideApply(client, obj, fieldManager, metadata.PatchForce(obj), forceOption, dryRun)
+}
+
+// ServerSideApplyPatch applies the fields of a Patch resource with server-side apply. Unlike
+// `ServerSideApply`, the apply takes ownership of conflicting fields if `force` is set, rather than if
+// the object has the `pulumi.com/patchForce` annotation, since the annotations of the object are
+// applied to an object that belongs to someone else.
+func ServerSideApplyPatch(
+	client dynamic.ResourceInterface, obj *unstructured.Unstructured, fieldManager string, force, dryRun bool,
+) (*unstructured.Unstructured, error) {
+	return serverSideApply(client, obj, fieldManager, force, "set `force` on the Patch", dryRun)
+}
+
+// serverSideApply applies the object with server-side apply. `forceOption` tells the user how to take
+// ownership of conflicting fields.
+func serverSideApply(
+	client dynamic.ResourceInterface, obj *unstructured.Unstructured, fieldManager string, force bool,
+	forceOption string, dryRun bool,
+) (*unstructured.Unstructured, error) {
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	if fieldManager == "" {
+		fieldManager = DefaultFieldManager
+	}
+	options := metav1.PatchOptions{FieldManager: fieldManager, Force: &force}
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+
+	// JSON is valid YAML, so the object can be sent as an apply patch as-is.
+	applied, err := client.Patch(context.TODO(), obj.GetName(), types.ApplyPatchType, data, options)
+	if conflicts := fieldManagerConflicts(err); len(conflicts) > 0 {
+		return nil, &FieldManagerConflictError{
+			object: obj, fieldManager: fieldManager, conflicts: conflicts, forceOption: forceOption}
+	}
+	return applied, err
+}
+
+// serverSideCreate creates the object with server-side apply. An apply silently takes over an object that
+// already exists, so, like a client-side create, it fails with an AlreadyExists error if there is one,
+// unless the object has the `pulumi.com/patchForce` annotation to adopt the existing object.
+func serverSideCreate(
+	client dynamic.ResourceInterface, obj *unstructured.Unstructured, fieldManager string, dryRun bool,
+) (*unstructured.Unstructured, error) {
+	if !metadata.PatchForce(obj) {
+		_, err := client.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+		switch {
+		case err == nil:
+			gvk := obj.GroupVersionKind()
+			return nil, errors.NewAlreadyExists(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, obj.GetName())
+		case !errors.IsNotFound(err):
+			return nil, err
+		}
+	}
+
+	return ServerSideApply(client, obj, fieldManager, dryRun)
+}
+
+// fieldManagerConflicts returns the conflicts reported by a failed server-side apply, if any.
+func fieldManagerConflicts(err error) []string {
+	se, isStatusError := err.(*errors.StatusError)
+	if !isStatusError || !errors.IsConflict(err) || se.ErrStatus.Details == nil {
+		return nil
+	}
+
+	var conflicts []string
+	for _, cause := range se.ErrStatus.Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict {
+			conflicts = append(conflicts, cause.Message)
+		}
+	}
+	return conflicts
+}
+
+// FieldManagerConflictError occurs when a server-side apply sets fields that are owned by other
+// field managers.
+type FieldManagerConflictError struct {
+	object       *unstructured.Unstructured
+	fieldManager string
+	conflicts    []string
+	forceOption  string
+}
+
+var _ error = (*FieldManagerConflictError)(nil)
+
+func (fe *FieldManagerConflictError) Error() string {
+	return fmt.Sprintf(
+		"Server-side apply of %q with field manager %q failed because other field managers own some of its "+
+			"fields:\n  * %s\nRemove these fields from the resource, or %s to take ownership of them",
+		fe.object.GetName(), fe.fieldManager, strings.Join(fe.conflicts, "\n  * "), fe.forceOption)
+}
@@ -0,0 +1,191 @@
+// nolint: goconst
+package await
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+func Test_fieldManagerConflicts(t *testing.T) {
+	conflict := errors.NewApplyConflict([]metav1.StatusCause{
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kube-controller-manager": .spec.replicas`,
+			Field:   ".spec.replicas",
+		},
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kubectl-edit" using apps/v1: .metadata.labels.app`,
+			Field:   ".metadata.labels.app",
+		},
+	}, "Apply failed with 2 conflicts")
+
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{name: "No error", err: nil},
+		{name: "Not found", err: errors.NewNotFound(schema.GroupResource{Resource: "deployments"}, "foo")},
+		{name: "Conflict without causes", err: errors.NewConflict(
+			schema.GroupResource{Resource: "deployments"}, "foo", nil)},
+		{name: "Field manager conflicts", err: conflict, want: []string{
+			`conflict with "kube-controller-manager": .spec.replicas`,
+			`conflict with "kubectl-edit" using apps/v1: .metadata.labels.app`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fieldManagerConflicts(tt.err))
+		})
+	}
+}
+
+func Test_FieldManagerConflictError(t *testing.T) {
+	conflicting := `Server-side apply of "foo" with field manager "pulumi-kubernetes" failed because other ` +
+		`field managers own some of its fields:
+  * conflict with "kube-controller-manager": .spec.replicas
+Remove these fields from the resource, or `
+
+	annotated := deploymentInput("default", "foo")
+	annotated.SetAnnotations(map[string]string{metadata.AnnotationPatchForce: metadata.AnnotationTrue})
+
+	tests := []struct {
+		name    string
+		apply   func(client dynamic.ResourceInterface) (*unstructured.Unstructured, error)
+		wantErr string
+	}{
+		{name: "Conflict", apply: func(client dynamic.ResourceInterface) (*unstructured.Unstructured, error) {
+			return ServerSideApply(client, deploymentInput("default", "foo"), DefaultFieldManager, false)
+		}, wantErr: conflicting + `set the "pulumi.com/patchForce" annotation to "true" to take ownership of them`},
+		{name: "Forced by annotation", apply: func(client dynamic.ResourceInterface) (*unstructured.Unstructured, error) {
+			return ServerSideApply(client, annotated, DefaultFieldManager, false)
+		}},
+		{name: "Patch conflict", apply: func(client dynamic.ResourceInterface) (*unstructured.Unstructured, error) {
+			return ServerSideApplyPatch(client, annotated, DefaultFieldManager, false, false)
+		}, wantErr: conflicting + "set `force` on the Patch to take ownership of them"},
+		{name: "Forced Patch", apply: func(client dynamic.ResourceInterface) (*unstructured.Unstructured, error) {
+			return ServerSideApplyPatch(client, deploymentInput("default", "foo"), DefaultFieldManager, true, false)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.apply(&conflictResourceInterface{})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_serverSideCreate(t *testing.T) {
+	existing := deploymentInput("default", "foo")
+
+	adopting := deploymentInput("default", "foo")
+	adopting.SetAnnotations(map[string]string{metadata.AnnotationPatchForce: metadata.AnnotationTrue})
+
+	tests := []struct {
+		name        string
+		obj         *unstructured.Unstructured
+		live        *unstructured.Unstructured
+		wantApplied bool
+		wantErr     string
+	}{
+		{name: "Object does not exist", obj: deploymentInput("default", "foo"), wantApplied: true},
+		{name: "Object already exists", obj: deploymentInput("default", "foo"), live: existing,
+			wantErr: `Deployment.apps "foo" already exists`},
+		{name: "Adopt existing object", obj: adopting, live: existing, wantApplied: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &applyResourceInterface{live: tt.live}
+			_, err := serverSideCreate(client, tt.obj, DefaultFieldManager, false)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.True(t, errors.IsAlreadyExists(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantApplied, client.applied)
+		})
+	}
+}
+
+func Test_patchForUpdate_ServerSideApply(t *testing.T) {
+	live := deploymentInput("default", "foo")
+	_ = unstructured.SetNestedField(live.Object, int64(5), "spec", "replicas")
+
+	inputs := deploymentInput("default", "foo")
+	inputs.SetAnnotations(map[string]string{metadata.AnnotationIgnoreFields: "metadata.labels"})
+
+	// Ignored fields are left out of the applied object, rather than set to their live values.
+	client := &applyResourceInterface{live: live}
+	c := ProviderConfig{ServerSideApply: true, FieldManager: DefaultFieldManager}
+	applied, err := patchForUpdate(c, client, nil, inputs, live, []metadata.FieldPath{{"spec", "replicas"}}, false)
+	assert.NoError(t, err)
+	assert.True(t, client.applied)
+	_, hasReplicas, _ := unstructured.NestedFieldNoCopy(applied.Object, "spec", "replicas")
+	assert.False(t, hasReplicas)
+	assert.Empty(t, applied.GetLabels())
+
+	// The inputs are left unchanged.
+	assert.Equal(t, map[string]string{"app": "foo"}, inputs.GetLabels())
+	replicas, _, _ := unstructured.NestedFieldNoCopy(inputs.Object, "spec", "replicas")
+	assert.Equal(t, int64(1), replicas)
+}
+
+// applyResourceInterface serves an object, if it exists, and records server-side applies.
+type applyResourceInterface struct {
+	mockResourceInterface
+	live    *unstructured.Unstructured
+	applied bool
+}
+
+func (a *applyResourceInterface) Get(
+	ctx context.Context, name string, options metav1.GetOptions, subresources ...string,
+) (*unstructured.Unstructured, error) {
+	if a.live == nil {
+		return nil, errors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, name)
+	}
+	return a.live.DeepCopy(), nil
+}
+
+func (a *applyResourceInterface) Patch(
+	ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions,
+	subresources ...string,
+) (*unstructured.Unstructured, error) {
+	a.applied = pt == types.ApplyPatchType
+	obj := &unstructured.Unstructured{}
+	return obj, obj.UnmarshalJSON(data)
+}
+
+// conflictResourceInterface fails server-side applies with a field manager conflict, unless they are forced.
+type conflictResourceInterface struct {
+	mockResourceInterface
+}
+
+func (c *conflictResourceInterface) Patch(
+	ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions,
+	subresources ...string,
+) (*unstructured.Unstructured, error) {
+	if options.Force == nil || !*options.Force {
+		return nil, errors.NewApplyConflict([]metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kube-controller-manager": .spec.replicas`,
+			Field:   ".spec.replicas",
+		}}, "Apply failed with 1 conflict")
+	}
+	obj := &unstructured.Unstructured{}
+	return obj, obj.UnmarshalJSON(data)
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/cluster"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/openapi"
@@ -58,8 +59,33 @@ type ProviderConfig struct {
 	InitialAPIVersion string
 
 	ClientSet   *clients.DynamicClientSet
+	LogClient   *clients.LogClient
 	DedupLogger *logging.DedupLogger
 	Resources   k8sopenapi.Resources
+
+	// Watches shares watches between the awaiters of all resources managed by the provider.
+	Watches *clients.WatchMultiplexer
+
+	// RecordingDirectory, if set, is the directory to which the watch events received by the
+	// awaiters are recorded. See `recordAwaitEventsToDirectory`.
+	RecordingDirectory string
+
+	// StreamEvents, if true, shows the Warning Events of awaited resources as status messages. See
+	// `streamEvents`.
+	StreamEvents bool
+
+	// DefaultTimeouts, if set, overrides the default timeouts of the awaiters. See the `defaultTimeouts` provider
+	// config.
+	DefaultTimeouts *metadata.DefaultTimeouts
+
+	// ServerSideApply, if true, creates and updates resources with server-side apply, using
+	// `FieldManager` as the field manager. See `ServerSideApply`.
+	ServerSideApply bool
+	FieldManager    string
+
+	// IgnoreFields holds the fields that are managed by someone else, by GVK or kind. Updates preserve the live
+	// values of these fields, and of the fields of the `pulumi.com/ignoreFields` annotation. See `ignoreFields`.
+	IgnoreFields metadata.IgnoreFieldsRules
 }
 
 type CreateConfig struct {
@@ -77,10 +103,18 @@ type ReadConfig struct {
 
 type UpdateConfig struct {
 	ProviderConfig
+	// Previous are the inputs of the last update. Like `Inputs`, they carry the last-applied-configuration
+	// annotation for client-side patches, so that a rollback that re-applies them keeps the annotation.
 	Previous *unstructured.Unstructured
 	Inputs   *unstructured.Unstructured
 	Timeout  float64
 	DryRun   bool
+	// RollbackOnFailure is the provider-level default for re-applying `Previous` if the update fails
+	// to become ready. It can be overridden with the `pulumi.com/rollbackOnFailure` annotation.
+	RollbackOnFailure bool
+	// PreserveFields are fields whose live values are preserved in addition to the ignored fields, e.g.,
+	// `spec.replicas` of a workload that is scaled by a HorizontalPodAutoscaler.
+	PreserveFields []metadata.FieldPath
 }
 
 type DeleteConfig struct {
@@ -88,6 +122,9 @@ type DeleteConfig struct {
 	Inputs  *unstructured.Unstructured
 	Name    string
 	Timeout float64
+	// DeleteOptions are the provider-level defaults for the delete request. They can be overridden with the
+	// `pulumi.com/deletePropagationPolicy` and `pulumi.com/deleteGracePeriodSeconds` annotations.
+	DeleteOptions metadata.DeleteOptions
 }
 
 type ResourceID struct {
@@ -155,7 +192,11 @@ func Creation(c CreateConfig) (*unstructured.Unstructured, error) {
 				}
 			}
 
-			outputs, err = client.Create(context.TODO(), c.Inputs, options)
+			if c.ServerSideApply {
+				outputs, err = serverSideCreate(client, c.Inputs, c.FieldManager, c.DryRun)
+			} else {
+				outputs, err = client.Create(context.TODO(), c.Inputs, options)
+			}
 			if err != nil {
 				_ = c.Host.LogStatus(c.Context, diag.Info, c.URN, fmt.Sprintf(
 					"Retry #%d; creation failed: %v", i, err))
@@ -182,21 +223,30 @@ func Creation(c CreateConfig) (*unstructured.Unstructured, error) {
 	// only if we don't have an entry for the resource type; in the event that we do, but the await
 	// logic is blank, simply do nothing instead of logging.
 	id := fmt.Sprintf("%s/%s", c.Inputs.GetAPIVersion(), c.Inputs.GetKind())
-	if awaiter, exists := awaiters[id]; exists {
+	if awaiter, exists := awaiterFor(id, c.Inputs); exists {
 		if metadata.SkipAwaitLogic(c.Inputs) {
 			logger.V(1).Infof("Skipping await logic for %v", c.Inputs.GetName())
 		} else {
 			if awaiter.awaitCreation != nil {
+				recorder, saveRecording := c.startRecording(c.Inputs, "create")
+				defer saveRecording()
 				conf := createAwaitConfig{
 					host:              c.Host,
 					ctx:               c.Context,
 					urn:               c.URN,
 					initialAPIVersion: c.InitialAPIVersion,
 					clientSet:         c.ClientSet,
+					logClient:         c.LogClient,
 					currentInputs:     c.Inputs,
 					currentOutputs:    outputs,
 					logger:            c.DedupLogger,
 					timeout:           c.Timeout,
+					defaultTimeouts:   c.DefaultTimeouts,
+					watches:           c.Watches,
+					recorder:          recorder,
+				}
+				if c.StreamEvents {
+					defer streamEvents(conf)()
 				}
 				waitErr := awaiter.awaitCreation(conf)
 				if waitErr != nil {
@@ -219,6 +269,17 @@ func Creation(c CreateConfig) (*unstructured.Unstructured, error) {
 	return live, nil
 }
 
+// awaiterFor returns the await logic for the resource type identified by `id`. The
+// `pulumi.com/waitFor` annotation, if present on `inputs`, takes precedence over the built-in await
+// logic for the type, and also applies to types with no built-in await logic.
+func awaiterFor(id string, inputs *unstructured.Unstructured) (awaitSpec, bool) {
+	if metadata.HasWaitFor(inputs) {
+		return waitForAwaitSpec, true
+	}
+	awaiter, exists := awaiters[id]
+	return awaiter, exists
+}
+
 // Read checks a resource, returning the object if it was created and initialized successfully.
 func Read(c ReadConfig) (*unstructured.Unstructured, error) {
 	client, err := c.ClientSet.ResourceClient(c.Inputs.GroupVersionKind(), c.Inputs.GetNamespace())
@@ -237,20 +298,25 @@ func Read(c ReadConfig) (*unstructured.Unstructured, error) {
 	}
 
 	id := fmt.Sprintf("%s/%s", outputs.GetAPIVersion(), outputs.GetKind())
-	if awaiter, exists := awaiters[id]; exists {
+	if awaiter, exists := awaiterFor(id, c.Inputs); exists {
 		if metadata.SkipAwaitLogic(c.Inputs) {
 			logger.V(1).Infof("Skipping await logic for %v", c.Inputs.GetName())
 		} else {
 			if awaiter.awaitRead != nil {
+				recorder, saveRecording := c.startRecording(c.Inputs, "read")
+				defer saveRecording()
 				conf := createAwaitConfig{
 					host:              c.Host,
 					ctx:               c.Context,
 					urn:               c.URN,
 					initialAPIVersion: c.InitialAPIVersion,
 					clientSet:         c.ClientSet,
+					logClient:         c.LogClient,
 					currentInputs:     c.Inputs,
 					currentOutputs:    outputs,
 					logger:            c.DedupLogger,
+					watches:           c.Watches,
+					recorder:          recorder,
 				}
 				waitErr := awaiter.awaitRead(conf)
 				if waitErr != nil {
@@ -325,7 +391,8 @@ func Update(c UpdateConfig) (*unstructured.Unstructured, error) {
 	// - [ ] Cause `Update` to default to the three-way JSON merge patch strategy. (This will require
 	//       plumbing, because it expects nominal types representing the API schema, but the
 	//       discovery client is completely dynamic.)
-	// - [ ] Support server-side apply, when it comes out.
+	// - [x] Support server-side apply, with the `enableServerSideApply` provider config. The server
+	//       merges the inputs into the live object, and tracks which fields each manager owns.
 	//
 
 	client, err := c.ClientSet.ResourceClient(c.Previous.GroupVersionKind(), c.Previous.GetNamespace())
@@ -340,21 +407,11 @@ func Update(c UpdateConfig) (*unstructured.Unstructured, error) {
 		return nil, err
 	}
 
-	// Create merge patch (prefer strategic merge patch, fall back to JSON merge patch).
-	patch, patchType, _, err := openapi.PatchForResourceUpdate(c.Resources, c.Previous, c.Inputs, liveOldObj)
-	if err != nil {
-		return nil, err
-	}
-
-	var options metav1.PatchOptions
-	if c.DryRun {
-		options.DryRun = []string{metav1.DryRunAll}
-	}
-
 	// Issue patch request.
 	// NOTE: We can use the same client because if the `kind` changes, this will cause
 	// a replace (i.e., destroy and create).
-	currentOutputs, err := client.Patch(context.TODO(), c.Inputs.GetName(), patchType, patch, options)
+	currentOutputs, err := patchForUpdate(
+		c.ProviderConfig, client, c.Previous, c.Inputs, liveOldObj, c.PreserveFields, c.DryRun)
 	if err != nil {
 		return nil, err
 	}
@@ -366,11 +423,13 @@ func Update(c UpdateConfig) (*unstructured.Unstructured, error) {
 	// if we don't have an entry for the resource type; in the event that we do, but the await logic
 	// is blank, simply do nothing instead of logging.
 	id := fmt.Sprintf("%s/%s", c.Inputs.GetAPIVersion(), c.Inputs.GetKind())
-	if awaiter, exists := awaiters[id]; exists {
+	if awaiter, exists := awaiterFor(id, c.Inputs); exists {
 		if metadata.SkipAwaitLogic(c.Inputs) {
 			logger.V(1).Infof("Skipping await logic for %v", c.Inputs.GetName())
 		} else {
 			if awaiter.awaitUpdate != nil {
+				recorder, saveRecording := c.startRecording(c.Inputs, "update")
+				defer saveRecording()
 				conf := updateAwaitConfig{
 					createAwaitConfig: createAwaitConfig{
 						host:              c.Host,
@@ -378,16 +437,26 @@ func Update(c UpdateConfig) (*unstructured.Unstructured, error) {
 						urn:               c.URN,
 						initialAPIVersion: c.InitialAPIVersion,
 						clientSet:         c.ClientSet,
+						logClient:         c.LogClient,
 						currentInputs:     c.Inputs,
 						currentOutputs:    currentOutputs,
 						logger:            c.DedupLogger,
 						timeout:           c.Timeout,
+						defaultTimeouts:   c.DefaultTimeouts,
+						watches:           c.Watches,
+						recorder:          recorder,
 					},
 					lastInputs:  c.Previous,
 					lastOutputs: liveOldObj,
 				}
+				if c.StreamEvents {
+					defer streamEvents(conf.createAwaitConfig)()
+				}
 				waitErr := awaiter.awaitUpdate(conf)
 				if waitErr != nil {
+					if shouldRollback(c, waitErr) {
+						return nil, rollback(c, client, awaiter, waitErr)
+					}
 					return nil, waitErr
 				}
 			}
@@ -410,6 +479,122 @@ func Update(c UpdateConfig) (*unstructured.Unstructured, error) {
 	return live, nil
 }
 
+// shouldRollback returns true if a failed update should be rolled back by re-applying the previous
+// inputs. Currently only Deployment rollouts are rolled back, and only if the user has opted in.
+func shouldRollback(c UpdateConfig, updateErr error) bool {
+	if c.Inputs.GetKind() != string(kinds.Deployment) || !metadata.RollbackOnFailure(c.Inputs, c.RollbackOnFailure) {
+		return false
+	}
+
+	// Only roll back updates that were applied but failed to become ready. Cancellations and errors
+	// talking to the API server are returned as-is.
+	switch updateErr.(type) {
+	case *timeoutError, *initializationError:
+		return true
+	default:
+		return false
+	}
+}
+
+// patchForUpdate patches the live object from the last applied inputs to the desired inputs. With
+// server-side apply, the desired inputs are applied as-is; otherwise, a three-way merge patch is
+// computed on the client (preferring a strategic merge patch, and falling back to a JSON merge patch).
+// Ignored fields, and the fields in `preserve`, are left out of the applied object with server-side
+// apply, and keep their live values otherwise.
+func patchForUpdate(
+	c ProviderConfig, client dynamic.ResourceInterface, lastInputs, inputs, live *unstructured.Unstructured,
+	preserve []metadata.FieldPath, dryRun bool,
+) (*unstructured.Unstructured, error) {
+	ignored, err := metadata.IgnoredFields(inputs, c.IgnoreFields)
+	if err != nil {
+		return nil, err
+	}
+	if ignored = append(ignored, preserve...); len(ignored) > 0 {
+		inputs = inputs.DeepCopy()
+		if c.ServerSideApply {
+			metadata.RemoveIgnoredFields(inputs, live, ignored)
+		} else {
+			metadata.PreserveIgnoredFields(inputs, live, ignored)
+		}
+	}
+
+	if c.ServerSideApply {
+		return ServerSideApply(client, inputs, c.FieldManager, dryRun)
+	}
+
+	patch, patchType, _, err := openapi.PatchForResourceUpdate(c.Resources, lastInputs, inputs, live)
+	if err != nil {
+		return nil, err
+	}
+
+	var options metav1.PatchOptions
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	return client.Patch(context.TODO(), inputs.GetName(), patchType, patch, options)
+}
+
+// rollback re-applies the previous inputs of an object whose update failed to become ready, and waits
+// for the rollback to complete. If the previous inputs could be re-applied, it returns a
+// `rollbackError`, so that the provider checkpoints the previous inputs and the next update retries
+// the change. Otherwise, it returns the original update error.
+func rollback(
+	c UpdateConfig, client dynamic.ResourceInterface, awaiter awaitSpec, updateErr error,
+) error {
+	var subErrors []string
+	if aggregate, isAggregate := updateErr.(AggregatedError); isAggregate {
+		subErrors = aggregate.SubErrors()
+	}
+
+	c.DedupLogger.LogMessage(logging.WarningMessage(fmt.Sprintf(
+		"Update of %q failed; rolling back to its previous configuration", c.Inputs.GetName())))
+
+	liveObj, err := client.Get(context.TODO(), c.Inputs.GetName(), metav1.GetOptions{})
+	if err != nil {
+		logger.V(3).Infof("Failed to get live object to roll back %q: %v", c.Inputs.GetName(), err)
+		return updateErr
+	}
+
+	// Compute the patch in reverse: the failed inputs are the "last applied" configuration, and the
+	// previous inputs are the desired configuration.
+	rolledBack, err := patchForUpdate(c.ProviderConfig, client, c.Inputs, c.Previous, liveObj, c.PreserveFields, false)
+	if err != nil {
+		logger.V(3).Infof("Failed to roll back %q: %v", c.Inputs.GetName(), err)
+		return updateErr
+	}
+
+	recorder, saveRecording := c.startRecording(c.Previous, "rollback")
+	defer saveRecording()
+	conf := updateAwaitConfig{
+		createAwaitConfig: createAwaitConfig{
+			host:              c.Host,
+			ctx:               c.Context,
+			urn:               c.URN,
+			initialAPIVersion: c.InitialAPIVersion,
+			clientSet:         c.ClientSet,
+			logClient:         c.LogClient,
+			currentInputs:     c.Previous,
+			currentOutputs:    rolledBack,
+			logger:            c.DedupLogger,
+			timeout:           c.Timeout,
+			defaultTimeouts:   c.DefaultTimeouts,
+			watches:           c.Watches,
+			recorder:          recorder,
+		},
+		lastInputs:  c.Inputs,
+		lastOutputs: liveObj,
+	}
+	if err := awaiter.awaitUpdate(conf); err != nil {
+		return &rollbackError{subErrors: subErrors, object: rolledBack, rollbackErr: err}
+	}
+
+	live, err := client.Get(context.TODO(), c.Previous.GetName(), metav1.GetOptions{})
+	if err != nil {
+		live = rolledBack
+	}
+	return &rollbackError{subErrors: subErrors, object: live}
+}
+
 // Deletion (as the usage, `await.Deletion`, implies) will block until one of the following is true:
 // (1) the Kubernetes resource is reported to be deleted; (2) the initialization timeout has
 // occurred; or (3) an error has occurred while the resource was being deleted.
@@ -438,104 +623,88 @@ func Deletion(c DeleteConfig) error {
 		return nilIfGVKDeleted(err)
 	}
 
-	timeout := metadata.TimeoutDuration(c.Timeout, c.Inputs, 300)
-	timeoutSeconds := int64(timeout.Seconds())
-	listOpts := metav1.ListOptions{
-		FieldSelector:  fields.OneTermEqualSelector("metadata.name", c.Name).String(),
-		TimeoutSeconds: &timeoutSeconds,
+	recorder, saveRecording := c.startRecording(c.Inputs, "delete")
+	defer saveRecording()
+	config := deleteAwaitConfig{
+		createAwaitConfig: createAwaitConfig{
+			host:              c.Host,
+			ctx:               c.Context,
+			urn:               c.URN,
+			initialAPIVersion: c.InitialAPIVersion,
+			clientSet:         c.ClientSet,
+			logClient:         c.LogClient,
+			currentInputs:     c.Inputs,
+			logger:            c.DedupLogger,
+			timeout:           c.Timeout,
+			defaultTimeouts:   c.DefaultTimeouts,
+			watches:           c.Watches,
+			recorder:          recorder,
+		},
+		clientForResource: client,
 	}
 
-	// Set up a watcher for the selected resource.
-	watcher, err := client.Watch(context.TODO(), listOpts)
+	id := fmt.Sprintf("%s/%s", c.Inputs.GetAPIVersion(), c.Inputs.GetKind())
+	awaiter, exists := awaiters[id]
+	hasDeletionAwaiter := exists && awaiter.awaitDeletion != nil
+	skipAwait := hasDeletionAwaiter && metadata.SkipAwaitLogic(c.Inputs)
+
+	// Set up a watcher for the selected resource, which reports the finalizers that block its deletion.
+	var watcher watch.Interface
+	if !skipAwait {
+		watcher, err = watchForDeletion(config, c.Name)
+		if err != nil {
+			return nilIfGVKDeleted(err)
+		}
+		defer watcher.Stop()
+	}
+
+	deleteOpts, err := metadata.GetDeleteOptions(c.Inputs, c.DeleteOptions)
 	if err != nil {
-		return nilIfGVKDeleted(err)
+		return err
 	}
 
-	err = deleteResource(c.Name, client, cluster.TryGetServerVersion(c.ClientSet.DiscoveryClientCached))
+	err = deleteResource(c.Name, client, cluster.TryGetServerVersion(c.ClientSet.DiscoveryClientCached), deleteOpts)
 	if err != nil {
 		return nilIfGVKDeleted(err)
 	}
 
-	// Wait until delete resolves as success or error. Note that the conditional is set up to log only
-	// if we don't have an entry for the resource type; in the event that we do, but the await logic
-	// is blank, simply do nothing instead of logging.
+	// Wait until delete resolves as success or error. Resource types with specialized deletion logic
+	// use that, while pending finalizers are reported and stripped as for any other type; all other
+	// types use the generic deletion awaiter.
 	var waitErr error
-	id := fmt.Sprintf("%s/%s", c.Inputs.GetAPIVersion(), c.Inputs.GetKind())
-	if awaiter, exists := awaiters[id]; exists && awaiter.awaitDeletion != nil {
-		if metadata.SkipAwaitLogic(c.Inputs) {
-			logger.V(1).Infof("Skipping await logic for %v", c.Inputs.GetName())
-		} else {
-			waitErr = awaiter.awaitDeletion(deleteAwaitConfig{
-				createAwaitConfig: createAwaitConfig{
-					host:              c.Host,
-					ctx:               c.Context,
-					urn:               c.URN,
-					initialAPIVersion: c.InitialAPIVersion,
-					clientSet:         c.ClientSet,
-					currentInputs:     c.Inputs,
-					logger:            c.DedupLogger,
-					timeout:           c.Timeout,
-				},
-				clientForResource: client,
-			})
-		}
+	if skipAwait {
+		logger.V(1).Infof("Skipping await logic for %v", c.Inputs.GetName())
+	} else if hasDeletionAwaiter {
+		waitErr = awaitDeletionWithFinalizers(config, watcher, awaiter.awaitDeletion)
 	} else {
-		for {
-			select {
-			case event, ok := <-watcher.ResultChan():
-				if !ok {
-					deleted, obj := checkIfResourceDeleted(c.Name, client)
-					if deleted {
-						_ = clearStatus(c.Context, c.Host, c.URN)
-						return nil
-					}
-
-					return &timeoutError{
-						object: obj,
-						subErrors: []string{
-							fmt.Sprintf("Timed out waiting for deletion of %s %q", id, c.Name),
-						},
-					}
-				}
-
-				switch event.Type {
-				case watch.Deleted:
-					_ = clearStatus(c.Context, c.Host, c.URN)
-					return nil
-				case watch.Error:
-					deleted, obj := checkIfResourceDeleted(c.Name, client)
-					if deleted {
-						_ = clearStatus(c.Context, c.Host, c.URN)
-						return nil
-					}
-					return &initializationError{
-						object:    obj,
-						subErrors: []string{errors.FromObject(event.Object).Error()},
-					}
-				}
-			case <-c.Context.Done(): // Handle user cancellation during watch for deletion.
-				watcher.Stop()
-				logger.V(3).Infof("Received error deleting object %q: %#v", id, err)
-				deleted, obj := checkIfResourceDeleted(c.Name, client)
-				if deleted {
-					_ = clearStatus(c.Context, c.Host, c.URN)
-					return nil
-				}
-
-				return &cancellationError{
-					object: obj,
-				}
-			}
-		}
+		waitErr = makeGenericDeletionAwaiter(config).Await(watcher)
 	}
 
 	return waitErr
 }
 
-func deleteResource(name string, client dynamic.ResourceInterface, version cluster.ServerVersion) error {
+// watchForDeletion opens a watch on the named object. Unlike the watches of other awaiters, it is not shared through
+// the watch multiplexer: it selects the object by name on the server, so that deleting one object does not list and
+// watch every object of its kind.
+func watchForDeletion(c deleteAwaitConfig, name string) (watch.Interface, error) {
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 300)
+	timeoutSeconds := int64(timeout.Seconds())
+	return c.recorder.watch(c.clientForResource.Watch(context.TODO(), metav1.ListOptions{
+		FieldSelector:  fields.OneTermEqualSelector("metadata.name", name).String(),
+		TimeoutSeconds: &timeoutSeconds,
+	}))
+}
+
+// deleteResource issues the delete request for the named resource. The propagation policy and grace period from
+// opts take precedence over the version-specific defaults.
+func deleteResource(
+	name string, client dynamic.ResourceInterface, version cluster.ServerVersion, opts metadata.DeleteOptions,
+) error {
 	// Manually set delete propagation for Kubernetes versions < 1.6 to avoid bugs.
-	deleteOpts := metav1.DeleteOptions{}
-	if version.Compare(cluster.ServerVersion{Major: 1, Minor: 6}) < 0 {
+	deleteOpts := metav1.DeleteOptions{GracePeriodSeconds: opts.GracePeriodSeconds}
+	if opts.PropagationPolicy != nil {
+		deleteOpts.PropagationPolicy = opts.PropagationPolicy
+	} else if version.Compare(cluster.ServerVersion{Major: 1, Minor: 6}) < 0 {
 		// 1.5.x option.
 		boolFalse := false
 		// nolint
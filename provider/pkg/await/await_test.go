@@ -2,16 +2,22 @@ package await
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/watcher"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/kube-openapi/pkg/util/proto"
 )
 
 func Test_Watcher_Interface_Cancel(t *testing.T) {
@@ -84,3 +90,151 @@ func (mri *mockResourceInterface) Patch(
 ) (*unstructured.Unstructured, error) {
 	panic("Patch not implemented")
 }
+
+// staticResourceInterface serves a fixed object.
+type staticResourceInterface struct {
+	mockResourceInterface
+	obj *unstructured.Unstructured
+}
+
+func (s *staticResourceInterface) Get(
+	ctx context.Context, name string, options metav1.GetOptions, subresources ...string,
+) (*unstructured.Unstructured, error) {
+	return s.obj.DeepCopy(), nil
+}
+
+func Test_shouldRollback(t *testing.T) {
+	deployment := func(annotation string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("apps/v1")
+		obj.SetKind("Deployment")
+		obj.SetName("foo")
+		if annotation != "" {
+			obj.SetAnnotations(map[string]string{metadata.AnnotationRollbackOnFailure: annotation})
+		}
+		return obj
+	}
+	service := &unstructured.Unstructured{}
+	service.SetAPIVersion("v1")
+	service.SetKind("Service")
+	service.SetAnnotations(map[string]string{metadata.AnnotationRollbackOnFailure: metadata.AnnotationTrue})
+
+	timeout := &timeoutError{object: deployment("")}
+
+	tests := []struct {
+		description     string
+		inputs          *unstructured.Unstructured
+		providerDefault bool
+		err             error
+		expected        bool
+	}{
+		{"Rollback disabled by default", deployment(""), false, timeout, false},
+		{"Rollback enabled by provider", deployment(""), true, timeout, true},
+		{"Rollback enabled by annotation", deployment(metadata.AnnotationTrue), false, timeout, true},
+		{"Rollback disabled by annotation", deployment(metadata.AnnotationFalse), true, timeout, false},
+		{"Rollback after initialization error", deployment(""), true,
+			&initializationError{object: deployment("")}, true},
+		{"No rollback after cancellation", deployment(""), true, &cancellationError{object: deployment("")}, false},
+		{"No rollback after API error", deployment(""), true, errors.New("connection refused"), false},
+		{"No rollback for other kinds", service, true, timeout, false},
+	}
+
+	for _, test := range tests {
+		c := UpdateConfig{Inputs: test.inputs, RollbackOnFailure: test.providerDefault}
+		assert.Equal(t, test.expected, shouldRollback(c, test.err), test.description)
+	}
+}
+
+func Test_rollback_LastAppliedConfig(t *testing.T) {
+	const lastAppliedConfig = "kubectl.kubernetes.io/last-applied-configuration"
+	// withLastAppliedConfig annotates the inputs with their own configuration, as the provider does.
+	withLastAppliedConfig := func(image string) *unstructured.Unstructured {
+		obj := deploymentInput("default", "foo")
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		containers[0].(map[string]interface{})["image"] = image
+		_ = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+		config, err := obj.MarshalJSON()
+		assert.NoError(t, err)
+		obj.SetAnnotations(map[string]string{lastAppliedConfig: string(config)})
+		return obj
+	}
+	previous, failed := withLastAppliedConfig("nginx:1.19"), withLastAppliedConfig("nginx:broken")
+
+	client := &patchResourceInterface{live: failed}
+	c := UpdateConfig{
+		ProviderConfig: ProviderConfig{
+			Context:     context.Background(),
+			DedupLogger: logging.NewLogger(context.Background(), nil, ""),
+			Resources:   emptyResources{},
+		},
+		Previous: previous,
+		Inputs:   failed,
+	}
+	awaiter := awaitSpec{awaitUpdate: func(updateAwaitConfig) error { return nil }}
+	err := rollback(c, client, awaiter, &timeoutError{object: failed})
+	assert.IsType(t, &rollbackError{}, err)
+
+	// The rollback restores the previous configuration, rather than removing the annotation.
+	patch := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(client.patch, &patch))
+	annotations, found, err := unstructured.NestedStringMap(patch, "metadata", "annotations")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Contains(t, annotations, lastAppliedConfig)
+	assert.Equal(t, previous.GetAnnotations()[lastAppliedConfig], annotations[lastAppliedConfig])
+}
+
+// emptyResources is an OpenAPI schema without any fields.
+type emptyResources struct{}
+
+func (emptyResources) LookupResource(gvk schema.GroupVersionKind) proto.Schema {
+	return &proto.Kind{}
+}
+
+// patchResourceInterface serves an object, and records the last patch applied to it.
+type patchResourceInterface struct {
+	mockResourceInterface
+	live  *unstructured.Unstructured
+	patch []byte
+}
+
+func (p *patchResourceInterface) Get(
+	ctx context.Context, name string, options metav1.GetOptions, subresources ...string,
+) (*unstructured.Unstructured, error) {
+	return p.live.DeepCopy(), nil
+}
+
+func (p *patchResourceInterface) Patch(
+	ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions,
+	subresources ...string,
+) (*unstructured.Unstructured, error) {
+	p.patch = data
+	return p.live.DeepCopy(), nil
+}
+
+// watchOptionsResourceInterface records the options of the watches opened on it.
+type watchOptionsResourceInterface struct {
+	mockResourceInterface
+	watchOptions []metav1.ListOptions
+}
+
+func (w *watchOptionsResourceInterface) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	w.watchOptions = append(w.watchOptions, opts)
+	return &chanWatcher{results: make(chan watch.Event)}, nil
+}
+
+func Test_watchForDeletion(t *testing.T) {
+	client := &watchOptionsResourceInterface{}
+	config := deleteAwaitConfig{
+		createAwaitConfig: mockAwaitConfig(serviceDeleting()),
+		clientForResource: client,
+	}
+
+	_, err := watchForDeletion(config, "foo")
+	assert.NoError(t, err)
+
+	// The watch selects the object on the server, rather than watching every object of its kind.
+	assert.Len(t, client.watchOptions, 1)
+	assert.Equal(t, "metadata.name=foo", client.watchOptions[0].FieldSelector)
+	assert.Equal(t, int64(300), *client.watchOptions[0].TimeoutSeconds)
+}
@@ -16,11 +16,15 @@ package await
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/openapi"
@@ -28,8 +32,14 @@ import (
 	"github.com/pulumi/pulumi/pkg/v2/resource/provider"
 	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v2/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
 	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic"
 )
 
@@ -49,9 +59,16 @@ type createAwaitConfig struct {
 	initialAPIVersion string
 	logger            *logging.DedupLogger
 	clientSet         *clients.DynamicClientSet
+	logClient         *clients.LogClient
 	currentInputs     *unstructured.Unstructured
 	currentOutputs    *unstructured.Unstructured
 	timeout           float64
+	defaultTimeouts   *metadata.DefaultTimeouts
+
+	// watches shares watches between awaiters. If it is nil, awaiters open dedicated watches.
+	watches *clients.WatchMultiplexer
+	// recorder records the watch events received by the awaiter, if recording is enabled.
+	recorder *recorder
 }
 
 func (cac *createAwaitConfig) logStatus(sev diag.Severity, message string) {
@@ -94,40 +111,59 @@ type deletionAwaiter func(deleteAwaitConfig) error
 // --------------------------------------------------------------------------
 
 const (
-	appsV1Deployment                            = "apps/v1/Deployment"
-	appsV1Beta1Deployment                       = "apps/v1beta1/Deployment"
-	appsV1Beta2Deployment                       = "apps/v1beta2/Deployment"
-	appsV1StatefulSet                           = "apps/v1/StatefulSet"
-	appsV1Beta1StatefulSet                      = "apps/v1beta1/StatefulSet"
-	appsV1Beta2StatefulSet                      = "apps/v1beta2/StatefulSet"
-	autoscalingV1HorizontalPodAutoscaler        = "autoscaling/v1/HorizontalPodAutoscaler"
-	batchV1Job                                  = "batch/v1/Job"
-	coreV1ConfigMap                             = "v1/ConfigMap"
-	coreV1LimitRange                            = "v1/LimitRange"
-	coreV1Namespace                             = "v1/Namespace"
-	coreV1PersistentVolume                      = "v1/PersistentVolume"
-	coreV1PersistentVolumeClaim                 = "v1/PersistentVolumeClaim"
-	coreV1Pod                                   = "v1/Pod"
-	coreV1ReplicationController                 = "v1/ReplicationController"
-	coreV1ResourceQuota                         = "v1/ResourceQuota"
-	coreV1Secret                                = "v1/Secret"
-	coreV1Service                               = "v1/Service"
-	coreV1ServiceAccount                        = "v1/ServiceAccount"
-	extensionsV1Beta1Deployment                 = "extensions/v1beta1/Deployment"
-	extensionsV1Beta1Ingress                    = "extensions/v1beta1/Ingress"
-	rbacAuthorizationV1ClusterRole              = "rbac.authorization.k8s.io/v1/ClusterRole"
-	rbacAuthorizationV1ClusterRoleBinding       = "rbac.authorization.k8s.io/v1/ClusterRoleBinding"
-	rbacAuthorizationV1Role                     = "rbac.authorization.k8s.io/v1/Role"
-	rbacAuthorizationV1RoleBinding              = "rbac.authorization.k8s.io/v1/RoleBinding"
-	rbacAuthorizationV1Alpha1ClusterRole        = "rbac.authorization.k8s.io/v1alpha1/ClusterRole"
-	rbacAuthorizationV1Alpha1ClusterRoleBinding = "rbac.authorization.k8s.io/v1alpha1/ClusterRoleBinding"
-	rbacAuthorizationV1Alpha1Role               = "rbac.authorization.k8s.io/v1alpha1/Role"
-	rbacAuthorizationV1Alpha1RoleBinding        = "rbac.authorization.k8s.io/v1alpha1/RoleBinding"
-	rbacAuthorizationV1Beta1ClusterRole         = "rbac.authorization.k8s.io/v1beta1/ClusterRole"
-	rbacAuthorizationV1Beta1ClusterRoleBinding  = "rbac.authorization.k8s.io/v1beta1/ClusterRoleBinding"
-	rbacAuthorizationV1Beta1Role                = "rbac.authorization.k8s.io/v1beta1/Role"
-	rbacAuthorizationV1Beta1RoleBinding         = "rbac.authorization.k8s.io/v1beta1/RoleBinding"
-	storageV1StorageClass                       = "storage.k8s.io/v1/StorageClass"
+	admissionregistrationV1MutatingWebhookConfiguration        = "admissionregistration.k8s.io/v1/MutatingWebhookConfiguration"
+	admissionregistrationV1ValidatingWebhookConfiguration      = "admissionregistration.k8s.io/v1/ValidatingWebhookConfiguration"
+	admissionregistrationV1Beta1MutatingWebhookConfiguration   = "admissionregistration.k8s.io/v1beta1/MutatingWebhookConfiguration"
+	admissionregistrationV1Beta1ValidatingWebhookConfiguration = "admissionregistration.k8s.io/v1beta1/ValidatingWebhookConfiguration"
+	apiextensionsV1CustomResourceDefinition                    = "apiextensions.k8s.io/v1/CustomResourceDefinition"
+	apiextensionsV1Beta1CustomResourceDefinition               = "apiextensions.k8s.io/v1beta1/CustomResourceDefinition"
+	apiregistrationV1APIService                                = "apiregistration.k8s.io/v1/APIService"
+	apiregistrationV1Beta1APIService                           = "apiregistration.k8s.io/v1beta1/APIService"
+	appsV1DaemonSet                                            = "apps/v1/DaemonSet"
+	appsV1Beta2DaemonSet                                       = "apps/v1beta2/DaemonSet"
+	appsV1ReplicaSet                                           = "apps/v1/ReplicaSet"
+	appsV1Beta2ReplicaSet                                      = "apps/v1beta2/ReplicaSet"
+	appsV1Deployment                                           = "apps/v1/Deployment"
+	appsV1Beta1Deployment                                      = "apps/v1beta1/Deployment"
+	appsV1Beta2Deployment                                      = "apps/v1beta2/Deployment"
+	appsV1StatefulSet                                          = "apps/v1/StatefulSet"
+	appsV1Beta1StatefulSet                                     = "apps/v1beta1/StatefulSet"
+	appsV1Beta2StatefulSet                                     = "apps/v1beta2/StatefulSet"
+	autoscalingV1HorizontalPodAutoscaler                       = "autoscaling/v1/HorizontalPodAutoscaler"
+	autoscalingV2Beta1HorizontalPodAutoscaler                  = "autoscaling/v2beta1/HorizontalPodAutoscaler"
+	autoscalingV2Beta2HorizontalPodAutoscaler                  = "autoscaling/v2beta2/HorizontalPodAutoscaler"
+	batchV1Job                                                 = "batch/v1/Job"
+	coreV1ConfigMap                                            = "v1/ConfigMap"
+	coreV1LimitRange                                           = "v1/LimitRange"
+	coreV1Namespace                                            = "v1/Namespace"
+	coreV1PersistentVolume                                     = "v1/PersistentVolume"
+	coreV1PersistentVolumeClaim                                = "v1/PersistentVolumeClaim"
+	coreV1Pod                                                  = "v1/Pod"
+	coreV1ReplicationController                                = "v1/ReplicationController"
+	coreV1ResourceQuota                                        = "v1/ResourceQuota"
+	coreV1Secret                                               = "v1/Secret"
+	coreV1Service                                              = "v1/Service"
+	coreV1ServiceAccount                                       = "v1/ServiceAccount"
+	extensionsV1Beta1DaemonSet                                 = "extensions/v1beta1/DaemonSet"
+	extensionsV1Beta1Deployment                                = "extensions/v1beta1/Deployment"
+	extensionsV1Beta1Ingress                                   = "extensions/v1beta1/Ingress"
+	extensionsV1Beta1ReplicaSet                                = "extensions/v1beta1/ReplicaSet"
+	networkingV1Ingress                                        = "networking.k8s.io/v1/Ingress"
+	networkingV1Beta1Ingress                                   = "networking.k8s.io/v1beta1/Ingress"
+	policyV1Beta1PodDisruptionBudget                           = "policy/v1beta1/PodDisruptionBudget"
+	rbacAuthorizationV1ClusterRole                             = "rbac.authorization.k8s.io/v1/ClusterRole"
+	rbacAuthorizationV1ClusterRoleBinding                      = "rbac.authorization.k8s.io/v1/ClusterRoleBinding"
+	rbacAuthorizationV1Role                                    = "rbac.authorization.k8s.io/v1/Role"
+	rbacAuthorizationV1RoleBinding                             = "rbac.authorization.k8s.io/v1/RoleBinding"
+	rbacAuthorizationV1Alpha1ClusterRole                       = "rbac.authorization.k8s.io/v1alpha1/ClusterRole"
+	rbacAuthorizationV1Alpha1ClusterRoleBinding                = "rbac.authorization.k8s.io/v1alpha1/ClusterRoleBinding"
+	rbacAuthorizationV1Alpha1Role                              = "rbac.authorization.k8s.io/v1alpha1/Role"
+	rbacAuthorizationV1Alpha1RoleBinding                       = "rbac.authorization.k8s.io/v1alpha1/RoleBinding"
+	rbacAuthorizationV1Beta1ClusterRole                        = "rbac.authorization.k8s.io/v1beta1/ClusterRole"
+	rbacAuthorizationV1Beta1ClusterRoleBinding                 = "rbac.authorization.k8s.io/v1beta1/ClusterRoleBinding"
+	rbacAuthorizationV1Beta1Role                               = "rbac.authorization.k8s.io/v1beta1/Role"
+	rbacAuthorizationV1Beta1RoleBinding                        = "rbac.authorization.k8s.io/v1beta1/RoleBinding"
+	storageV1StorageClass                                      = "storage.k8s.io/v1/StorageClass"
 )
 
 type awaitSpec struct {
@@ -137,6 +173,45 @@ type awaitSpec struct {
 	awaitDeletion deletionAwaiter
 }
 
+var apiServiceAwaiter = awaitSpec{
+	awaitCreation: untilApiregistrationAPIServiceAvailable,
+	awaitUpdate: func(u updateAwaitConfig) error {
+		return untilApiregistrationAPIServiceAvailable(u.createAwaitConfig)
+	},
+}
+
+var customResourceDefinitionAwaiter = awaitSpec{
+	awaitCreation: untilApiextensionsCRDEstablished,
+	awaitUpdate: func(u updateAwaitConfig) error {
+		return untilApiextensionsCRDEstablished(u.createAwaitConfig)
+	},
+}
+
+var daemonsetAwaiter = awaitSpec{
+	awaitCreation: func(c createAwaitConfig) error {
+		return makeDaemonSetInitAwaiter(updateAwaitConfig{createAwaitConfig: c}).Await()
+	},
+	awaitUpdate: func(u updateAwaitConfig) error {
+		return makeDaemonSetInitAwaiter(u).Await()
+	},
+	awaitRead: func(c createAwaitConfig) error {
+		return makeDaemonSetInitAwaiter(updateAwaitConfig{createAwaitConfig: c}).Read()
+	},
+	awaitDeletion: untilAppsDaemonSetDeleted,
+}
+
+var replicaSetAwaiter = awaitSpec{
+	awaitCreation: func(c createAwaitConfig) error {
+		return makeReplicaSetInitAwaiter(updateAwaitConfig{createAwaitConfig: c}).Await()
+	},
+	awaitUpdate: func(u updateAwaitConfig) error {
+		return makeReplicaSetInitAwaiter(u).Await()
+	},
+	awaitRead: func(c createAwaitConfig) error {
+		return makeReplicaSetInitAwaiter(updateAwaitConfig{createAwaitConfig: c}).Read()
+	},
+}
+
 var deploymentAwaiter = awaitSpec{
 	awaitCreation: func(c createAwaitConfig) error {
 		return makeDeploymentInitAwaiter(updateAwaitConfig{createAwaitConfig: c}).Await()
@@ -150,6 +225,19 @@ var deploymentAwaiter = awaitSpec{
 	awaitDeletion: untilAppsDeploymentDeleted,
 }
 
+var horizontalPodAutoscalerAwaiter = awaitSpec{
+	awaitCreation: untilAutoscalingHorizontalPodAutoscalerActive,
+	awaitUpdate: func(u updateAwaitConfig) error {
+		return untilAutoscalingHorizontalPodAutoscalerActive(u.createAwaitConfig)
+	},
+}
+
+var ingressAwaiter = awaitSpec{
+	awaitCreation: awaitIngressInit,
+	awaitRead:     awaitIngressRead,
+	awaitUpdate:   awaitIngressUpdate,
+}
+
 var jobAwaiter = awaitSpec{
 	awaitCreation: func(c createAwaitConfig) error {
 		return makeJobInitAwaiter(c).Await()
@@ -163,6 +251,20 @@ var jobAwaiter = awaitSpec{
 	awaitDeletion: untilBatchV1JobDeleted,
 }
 
+var webhookConfigurationAwaiter = awaitSpec{
+	awaitCreation: untilAdmissionregistrationWebhookServicesReady,
+	awaitUpdate: func(u updateAwaitConfig) error {
+		return untilAdmissionregistrationWebhookServicesReady(u.createAwaitConfig)
+	},
+}
+
+var podDisruptionBudgetAwaiter = awaitSpec{
+	awaitCreation: untilPolicyV1Beta1PodDisruptionBudgetObserved,
+	awaitUpdate: func(u updateAwaitConfig) error {
+		return untilPolicyV1Beta1PodDisruptionBudgetObserved(u.createAwaitConfig)
+	},
+}
+
 var statefulsetAwaiter = awaitSpec{
 	awaitCreation: func(c createAwaitConfig) error {
 		return makeStatefulSetInitAwaiter(updateAwaitConfig{createAwaitConfig: c}).Await()
@@ -180,17 +282,33 @@ var statefulsetAwaiter = awaitSpec{
 // about, but don't require await logic, vs. resource types that we don't know about.
 
 var awaiters = map[string]awaitSpec{
-	appsV1Deployment:                     deploymentAwaiter,
-	appsV1Beta1Deployment:                deploymentAwaiter,
-	appsV1Beta2Deployment:                deploymentAwaiter,
-	appsV1StatefulSet:                    statefulsetAwaiter,
-	appsV1Beta1StatefulSet:               statefulsetAwaiter,
-	appsV1Beta2StatefulSet:               statefulsetAwaiter,
-	autoscalingV1HorizontalPodAutoscaler: { /* NONE */ },
-	batchV1Job:                           jobAwaiter,
-	coreV1ConfigMap:                      { /* NONE */ },
-	coreV1LimitRange:                     { /* NONE */ },
+	admissionregistrationV1MutatingWebhookConfiguration:        webhookConfigurationAwaiter,
+	admissionregistrationV1ValidatingWebhookConfiguration:      webhookConfigurationAwaiter,
+	admissionregistrationV1Beta1MutatingWebhookConfiguration:   webhookConfigurationAwaiter,
+	admissionregistrationV1Beta1ValidatingWebhookConfiguration: webhookConfigurationAwaiter,
+	apiextensionsV1CustomResourceDefinition:                    customResourceDefinitionAwaiter,
+	apiextensionsV1Beta1CustomResourceDefinition:               customResourceDefinitionAwaiter,
+	apiregistrationV1APIService:                                apiServiceAwaiter,
+	apiregistrationV1Beta1APIService:                           apiServiceAwaiter,
+	appsV1DaemonSet:                                            daemonsetAwaiter,
+	appsV1Beta2DaemonSet:                                       daemonsetAwaiter,
+	appsV1ReplicaSet:                                           replicaSetAwaiter,
+	appsV1Beta2ReplicaSet:                                      replicaSetAwaiter,
+	appsV1Deployment:                                           deploymentAwaiter,
+	appsV1Beta1Deployment:                                      deploymentAwaiter,
+	appsV1Beta2Deployment:                                      deploymentAwaiter,
+	appsV1StatefulSet:                                          statefulsetAwaiter,
+	appsV1Beta1StatefulSet:                                     statefulsetAwaiter,
+	appsV1Beta2StatefulSet:                                     statefulsetAwaiter,
+	autoscalingV1HorizontalPodAutoscaler:                       horizontalPodAutoscalerAwaiter,
+	autoscalingV2Beta1HorizontalPodAutoscaler:                  horizontalPodAutoscalerAwaiter,
+	autoscalingV2Beta2HorizontalPodAutoscaler:                  horizontalPodAutoscalerAwaiter,
+	batchV1Job:       jobAwaiter,
+	coreV1ConfigMap:  { /* NONE */ },
+	coreV1LimitRange: { /* NONE */ },
 	coreV1Namespace: {
+		awaitCreation: untilCoreV1NamespaceActive,
+		awaitUpdate:   untilCoreV1NamespaceUpdated,
 		awaitDeletion: untilCoreV1NamespaceDeleted,
 	},
 	coreV1PersistentVolume: {
@@ -223,12 +341,13 @@ var awaiters = map[string]awaitSpec{
 	coreV1ServiceAccount: {
 		awaitCreation: untilCoreV1ServiceAccountInitialized,
 	},
-	extensionsV1Beta1Deployment: deploymentAwaiter,
-	extensionsV1Beta1Ingress: {
-		awaitCreation: awaitIngressInit,
-		awaitRead:     awaitIngressRead,
-		awaitUpdate:   awaitIngressUpdate,
-	},
+	extensionsV1Beta1DaemonSet:                  daemonsetAwaiter,
+	extensionsV1Beta1Deployment:                 deploymentAwaiter,
+	extensionsV1Beta1Ingress:                    ingressAwaiter,
+	extensionsV1Beta1ReplicaSet:                 replicaSetAwaiter,
+	networkingV1Ingress:                         ingressAwaiter,
+	networkingV1Beta1Ingress:                    ingressAwaiter,
+	policyV1Beta1PodDisruptionBudget:            podDisruptionBudgetAwaiter,
 	rbacAuthorizationV1ClusterRole:              { /* NONE */ },
 	rbacAuthorizationV1ClusterRoleBinding:       { /* NONE */ },
 	rbacAuthorizationV1Role:                     { /* NONE */ },
@@ -256,6 +375,274 @@ var awaiters = map[string]awaitSpec{
 
 // --------------------------------------------------------------------------
 
+// admissionregistration.k8s.io/v1/MutatingWebhookConfiguration,
+// admissionregistration.k8s.io/v1/ValidatingWebhookConfiguration,
+// admissionregistration.k8s.io/v1beta1/MutatingWebhookConfiguration,
+// admissionregistration.k8s.io/v1beta1/ValidatingWebhookConfiguration
+
+// --------------------------------------------------------------------------
+
+// webhookService is a Service referenced by the `clientConfig` of one or more admission webhooks.
+type webhookService struct {
+	namespace string
+	name      string
+	webhooks  []string
+}
+
+// webhookServices returns the Services referenced by the webhooks of a webhook configuration, in the
+// order they are first referenced. Webhooks that call a URL rather than a Service are ignored.
+func webhookServices(obj *unstructured.Unstructured) []*webhookService {
+	webhooks, _, _ := unstructured.NestedSlice(obj.Object, "webhooks")
+
+	var services []*webhookService
+	byKey := map[string]*webhookService{}
+	for _, w := range webhooks {
+		webhook, ok := w.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		webhookName, _, _ := unstructured.NestedString(webhook, "name")
+		namespace, _, _ := unstructured.NestedString(webhook, "clientConfig", "service", "namespace")
+		name, hasService, _ := unstructured.NestedString(webhook, "clientConfig", "service", "name")
+		if !hasService {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s", namespace, name)
+		svc, exists := byKey[key]
+		if !exists {
+			svc = &webhookService{namespace: namespace, name: name}
+			byKey[key] = svc
+			services = append(services, svc)
+		}
+		svc.webhooks = append(svc.webhooks, webhookName)
+	}
+
+	return services
+}
+
+// hasReadyEndpoints returns true if an Endpoints object has at least one ready address.
+func hasReadyEndpoints(endpoints *unstructured.Unstructured) bool {
+	subsets, _, _ := unstructured.NestedSlice(endpoints.Object, "subsets")
+	for _, s := range subsets {
+		subset, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if addresses, _, _ := unstructured.NestedSlice(subset, "addresses"); len(addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func untilAdmissionregistrationWebhookServicesReady(c createAwaitConfig) error {
+	//
+	// The API server calls admission webhooks for every matching request, so a webhook whose backing
+	// Service has no ready endpoints causes unrelated resource operations to fail. Wait until every
+	// Service referenced by the webhooks has at least one ready endpoint.
+	//
+	services := webhookServices(c.currentInputs)
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 300)
+	deadline := time.Now().Add(timeout)
+
+	for i, svc := range services {
+		svc := svc
+		blocking := fmt.Sprintf("[%d/%d] Waiting for Service \"%s/%s\" (used by webhook %s) to have ready endpoints",
+			i+1, len(services), svc.namespace, svc.name, strings.Join(svc.webhooks, ", "))
+
+		endpointsReady := func(endpoints *unstructured.Unstructured, err error) error {
+			if err != nil && !is404(err) {
+				return err
+			}
+			if err == nil && hasReadyEndpoints(endpoints) {
+				return nil
+			}
+
+			c.logStatus(diag.Info, blocking)
+			return watcher.RetryableError(fmt.Errorf("Service \"%s/%s\" has no ready endpoints",
+				svc.namespace, svc.name))
+		}
+
+		client, err := c.clientSet.ResourceClient(
+			schema.GroupVersionKind{Version: "v1", Kind: "Endpoints"}, svc.namespace)
+		if err != nil {
+			return err
+		}
+
+		err = watcher.ForObject(c.ctx, client, svc.name).RetryUntil(endpointsReady, time.Until(deadline))
+		if err != nil {
+			return errors.Wrapf(err, "webhook %s is not ready: Service \"%s/%s\" has no ready endpoints",
+				strings.Join(svc.webhooks, ", "), svc.namespace, svc.name)
+		}
+	}
+
+	if len(services) > 0 {
+		c.logStatus(diag.Info,
+			fmt.Sprintf("%sWebhook Services have ready endpoints", cmdutil.EmojiOr("✅ ", "")))
+	}
+	logger.V(3).Infof("Webhook configuration %q is ready", c.currentInputs.GetName())
+
+	return nil
+}
+
+// --------------------------------------------------------------------------
+
+// apiextensions.k8s.io/v1/CustomResourceDefinition,
+// apiextensions.k8s.io/v1beta1/CustomResourceDefinition
+
+// --------------------------------------------------------------------------
+
+func untilApiextensionsCRDEstablished(c createAwaitConfig) error {
+	client, err := c.clientSet.ResourceClient(c.currentInputs.GroupVersionKind(), c.currentInputs.GetNamespace())
+	if err != nil {
+		return err
+	}
+
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 300)
+	if err = awaitCRDEstablished(c, client, timeout); err != nil {
+		return err
+	}
+
+	// Invalidate the discovery cache so that custom resources of the new type can be resolved
+	// immediately, rather than failing with a "no matches for kind" error and retrying.
+	c.clientSet.RESTMapper.Reset()
+
+	c.logStatus(diag.Info,
+		fmt.Sprintf("%sCustomResourceDefinition established", cmdutil.EmojiOr("✅ ", "")))
+	logger.V(3).Infof("CustomResourceDefinition %q established", c.currentInputs.GetName())
+
+	return nil
+}
+
+// awaitCRDEstablished is a helper companion to `untilApiextensionsCRDEstablished` designed to make it easy to test
+// this module.
+func awaitCRDEstablished(c createAwaitConfig, client dynamic.ResourceInterface, timeout time.Duration) error {
+	//
+	// A CRD is ready to serve custom resources once the API server has accepted its names (i.e., they
+	// don't conflict with another CRD), and the new REST endpoint has been established. If the names
+	// are rejected, the CRD will never become established, so fail immediately rather than waiting
+	// for the timeout.
+	//
+	crdEstablished := func(crd *unstructured.Unstructured, err error) error {
+		if err != nil {
+			return err
+		}
+
+		namesAccepted := findCondition(crd, "NamesAccepted")
+		if namesAccepted != nil && namesAccepted["status"] == "False" {
+			return fmt.Errorf("CustomResourceDefinition %q names were not accepted: [%v] %v",
+				crd.GetName(), namesAccepted["reason"], namesAccepted["message"])
+		}
+		if namesAccepted == nil || namesAccepted["status"] != trueStatus {
+			c.logStatus(diag.Info, "[1/2] Waiting for CustomResourceDefinition names to be accepted")
+			return watcher.RetryableError(fmt.Errorf("CustomResourceDefinition %q names have not been accepted",
+				crd.GetName()))
+		}
+
+		established := findCondition(crd, "Established")
+		if established == nil || established["status"] != trueStatus {
+			c.logStatus(diag.Info, "[2/2] Waiting for CustomResourceDefinition to be established")
+			return watcher.RetryableError(fmt.Errorf("CustomResourceDefinition %q is not established",
+				crd.GetName()))
+		}
+
+		return nil
+	}
+
+	return watcher.ForObject(c.ctx, client, c.currentInputs.GetName()).
+		RetryUntil(crdEstablished, timeout)
+}
+
+// --------------------------------------------------------------------------
+
+// apiregistration.k8s.io/v1/APIService, apiregistration.k8s.io/v1beta1/APIService
+
+// --------------------------------------------------------------------------
+
+func untilApiregistrationAPIServiceAvailable(c createAwaitConfig) error {
+	//
+	// An APIService is available once the aggregator can reach the backing Service (for aggregated
+	// APIs like metrics-server), or immediately for APIs served locally by the API server. Until
+	// then, requests to the API group fail, so report the reason the APIService is unavailable.
+	//
+	apiServiceAvailable := func(apiService *unstructured.Unstructured, err error) error {
+		if err != nil {
+			return err
+		}
+
+		available := findCondition(apiService, statusAvailable)
+		if available != nil && available["status"] == trueStatus {
+			return nil
+		}
+
+		message := fmt.Sprintf("APIService %q is not available", apiService.GetName())
+		if available != nil {
+			message = fmt.Sprintf("%s: [%v] %v", message, available["reason"], available["message"])
+		}
+		c.logStatus(diag.Info, fmt.Sprintf("Waiting for APIService to become available: %s", message))
+		return watcher.RetryableError(errors.New(message))
+	}
+
+	client, err := c.clientSet.ResourceClient(c.currentInputs.GroupVersionKind(), c.currentInputs.GetNamespace())
+	if err != nil {
+		return err
+	}
+
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 300)
+	err = watcher.ForObject(c.ctx, client, c.currentInputs.GetName()).
+		RetryUntil(apiServiceAvailable, timeout)
+	if err != nil {
+		return err
+	}
+
+	c.logStatus(diag.Info, fmt.Sprintf("%sAPIService available", cmdutil.EmojiOr("✅ ", "")))
+	logger.V(3).Infof("APIService %q is available", c.currentInputs.GetName())
+
+	return nil
+}
+
+// --------------------------------------------------------------------------
+
+// apps/v1/DaemonSet, apps/v1beta2/DaemonSet, extensions/v1beta1/DaemonSet
+
+// --------------------------------------------------------------------------
+
+func untilAppsDaemonSetDeleted(config deleteAwaitConfig) error {
+	statusScheduled := func(daemonset *unstructured.Unstructured) (interface{}, bool) {
+		return openapi.Pluck(daemonset.Object, "status", "currentNumberScheduled")
+	}
+
+	daemonsetMissing := func(d *unstructured.Unstructured, err error) error {
+		if is404(err) {
+			return nil
+		} else if err != nil {
+			logger.V(3).Infof("Received error deleting DaemonSet %q: %#v", d.GetName(), err)
+			return err
+		}
+
+		currScheduled, _ := statusScheduled(d)
+
+		return watcher.RetryableError(
+			fmt.Errorf("DaemonSet %q still exists (%d Pods scheduled)", config.currentInputs.GetName(),
+				currScheduled))
+	}
+
+	// Wait until all Pods are gone. 10 minutes should be enough for clusters with ~10k Nodes.
+	timeout := metadata.TimeoutDuration(config.timeout, config.defaultTimeouts, config.currentInputs, 600)
+	err := watcher.ForObject(config.ctx, config.clientForResource, config.currentInputs.GetName()).
+		RetryUntil(daemonsetMissing, timeout)
+	if err != nil {
+		return err
+	}
+
+	logger.V(3).Infof("DaemonSet %q deleted", config.currentInputs.GetName())
+
+	return nil
+}
+
+// --------------------------------------------------------------------------
+
 // apps/v1/Deployment, apps/v1beta1/Deployment, apps/v1beta2/Deployment,
 // extensions/v1beta1/Deployment
 
@@ -293,7 +680,7 @@ func untilAppsDeploymentDeleted(config deleteAwaitConfig) error {
 	}
 
 	// Wait until all replicas are gone. 10 minutes should be enough for ~10k replicas.
-	timeout := metadata.TimeoutDuration(config.timeout, config.currentInputs, 600)
+	timeout := metadata.TimeoutDuration(config.timeout, config.defaultTimeouts, config.currentInputs, 600)
 	err := watcher.ForObject(config.ctx, config.clientForResource, config.currentInputs.GetName()).
 		RetryUntil(deploymentMissing, timeout)
 	if err != nil {
@@ -336,7 +723,7 @@ func untilAppsStatefulSetDeleted(config deleteAwaitConfig) error {
 	}
 
 	// Wait until all replicas are gone. 10 minutes should be enough for ~10k replicas.
-	timeout := metadata.TimeoutDuration(config.timeout, config.currentInputs, 600)
+	timeout := metadata.TimeoutDuration(config.timeout, config.defaultTimeouts, config.currentInputs, 600)
 	err := watcher.ForObject(config.ctx, config.clientForResource, config.currentInputs.GetName()).
 		RetryUntil(statefulsetmissing, timeout)
 	if err != nil {
@@ -350,6 +737,98 @@ func untilAppsStatefulSetDeleted(config deleteAwaitConfig) error {
 
 // --------------------------------------------------------------------------
 
+// autoscaling/v1/HorizontalPodAutoscaler, autoscaling/v2beta1/HorizontalPodAutoscaler,
+// autoscaling/v2beta2/HorizontalPodAutoscaler
+
+// --------------------------------------------------------------------------
+
+// hpaConditionsAnnotation is the annotation used by the autoscaling/v1 API to expose the conditions
+// that are part of `.status` in the autoscaling/v2 APIs.
+const hpaConditionsAnnotation = "autoscaling.alpha.kubernetes.io/conditions"
+
+// hpaCondition returns the HorizontalPodAutoscaler condition with the given type, or nil if the
+// controller has not reported it yet.
+func hpaCondition(hpa *unstructured.Unstructured, conditionType string) map[string]interface{} {
+	if condition := findCondition(hpa, conditionType); condition != nil {
+		return condition
+	}
+
+	raw, hasConditions := hpa.GetAnnotations()[hpaConditionsAnnotation]
+	if !hasConditions {
+		return nil
+	}
+	var conditions []map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &conditions); err != nil {
+		logger.V(3).Infof("Failed to parse %s annotation of %q: %v", hpaConditionsAnnotation, hpa.GetName(), err)
+		return nil
+	}
+	for _, condition := range conditions {
+		if condition["type"] == conditionType {
+			return condition
+		}
+	}
+	return nil
+}
+
+func untilAutoscalingHorizontalPodAutoscalerActive(c createAwaitConfig) error {
+	//
+	// An HPA is considered ready when the controller reports that it can fetch the scale target
+	// (`AbleToScale`), and can compute a replica count from its metrics (`ScalingActive`). Scaling is
+	// inactive by design if the target has been scaled to zero. Common failures are a missing scale
+	// target (`FailedGetScale`) and metrics that can't be fetched (e.g., `FailedGetResourceMetric`),
+	// so report the reason while waiting.
+	//
+	var lastMessage string
+	hpaActive := func(hpa *unstructured.Unstructured, err error) error {
+		if err != nil {
+			return err
+		}
+
+		ableToScale := hpaCondition(hpa, "AbleToScale")
+		scalingActive := hpaCondition(hpa, "ScalingActive")
+		switch {
+		case ableToScale == nil || scalingActive == nil:
+			lastMessage = "HorizontalPodAutoscaler controller has not reported status"
+			c.logStatus(diag.Info, "[1/2] Waiting for HorizontalPodAutoscaler controller to report status")
+		case ableToScale["status"] != trueStatus:
+			lastMessage = fmt.Sprintf("HorizontalPodAutoscaler is unable to scale: [%v] %v",
+				ableToScale["reason"], ableToScale["message"])
+			c.logStatus(diag.Warning, lastMessage)
+		case scalingActive["status"] != trueStatus && scalingActive["reason"] != "ScalingDisabled":
+			lastMessage = fmt.Sprintf("HorizontalPodAutoscaler scaling is not active: [%v] %v",
+				scalingActive["reason"], scalingActive["message"])
+			c.logStatus(diag.Info, fmt.Sprintf("[2/2] Waiting for HorizontalPodAutoscaler metrics: [%v] %v",
+				scalingActive["reason"], scalingActive["message"]))
+		default:
+			return nil
+		}
+
+		return watcher.RetryableError(errors.New(lastMessage))
+	}
+
+	client, err := c.clientSet.ResourceClient(c.currentInputs.GroupVersionKind(), c.currentInputs.GetNamespace())
+	if err != nil {
+		return err
+	}
+
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 300)
+	err = watcher.ForObject(c.ctx, client, c.currentInputs.GetName()).
+		RetryUntil(hpaActive, timeout)
+	if err != nil {
+		if lastMessage != "" {
+			return errors.Wrap(err, lastMessage)
+		}
+		return err
+	}
+
+	c.logStatus(diag.Info, fmt.Sprintf("%sHorizontalPodAutoscaler is active", cmdutil.EmojiOr("✅ ", "")))
+	logger.V(3).Infof("HorizontalPodAutoscaler %q is active", c.currentInputs.GetName())
+
+	return nil
+}
+
+// --------------------------------------------------------------------------
+
 // batch/v1/Job
 
 // --------------------------------------------------------------------------
@@ -366,7 +845,7 @@ func untilBatchV1JobDeleted(config deleteAwaitConfig) error {
 		return watcher.RetryableError(e)
 	}
 
-	timeout := metadata.TimeoutDuration(config.timeout, config.currentInputs, 300)
+	timeout := metadata.TimeoutDuration(config.timeout, config.defaultTimeouts, config.currentInputs, 300)
 	return watcher.ForObject(config.ctx, config.clientForResource, config.currentInputs.GetName()).
 		RetryUntil(jobMissingOrKilled, timeout)
 }
@@ -377,7 +856,109 @@ func untilBatchV1JobDeleted(config deleteAwaitConfig) error {
 
 // --------------------------------------------------------------------------
 
+const namespaceActive = "Active"
+
+// namespaceDeletionConditions are the conditions the Namespace controller reports on a terminating
+// Namespace to explain why its deletion has not completed.
+var namespaceDeletionConditions = []string{
+	"NamespaceContentRemaining",
+	"NamespaceFinalizersRemaining",
+	"NamespaceDeletionDiscoveryFailure",
+	"NamespaceDeletionContentFailure",
+}
+
+// namespaceDeletionBlockers returns the messages of the conditions that explain why the deletion of a
+// terminating Namespace has not completed, e.g., the resource types that still remain in it.
+func namespaceDeletionBlockers(ns *unstructured.Unstructured) []string {
+	var blockers []string
+	for _, conditionType := range namespaceDeletionConditions {
+		condition := findCondition(ns, conditionType)
+		if condition == nil || condition["status"] != trueStatus {
+			continue
+		}
+		if message, ok := condition["message"].(string); ok && message != "" {
+			blockers = append(blockers, message)
+		}
+	}
+	return blockers
+}
+
+func untilCoreV1NamespaceActive(c createAwaitConfig) error {
+	//
+	// A Namespace is considered ready when its phase is `Active`. Optionally (if the
+	// `pulumi.com/waitForDefaultServiceAccount` annotation is set), the Namespace is not considered
+	// ready until the ServiceAccount controller has created its `default` ServiceAccount, since Pods
+	// that don't specify a ServiceAccount are rejected until then.
+	//
+	name := c.currentInputs.GetName()
+	waitForServiceAccount := metadata.WaitForDefaultServiceAccount(c.currentInputs)
+	steps := 1
+	if waitForServiceAccount {
+		steps = 2
+	}
+
+	namespaceIsActive := func(ns *unstructured.Unstructured, err error) error {
+		if err != nil {
+			return err
+		}
+
+		statusPhase, _, _ := unstructured.NestedString(ns.Object, "status", "phase")
+		logger.V(3).Infof("Namespace %q status received: %#v", name, statusPhase)
+		if statusPhase == namespaceActive {
+			return nil
+		}
+
+		c.logStatus(diag.Info, fmt.Sprintf("[1/%d] Waiting for Namespace to become active", steps))
+		return watcher.RetryableError(fmt.Errorf("namespace %q is not active (%v)", name, statusPhase))
+	}
+
+	client, err := c.clientSet.ResourceClient(c.currentInputs.GroupVersionKind(), "")
+	if err != nil {
+		return err
+	}
+
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 300)
+	deadline := time.Now().Add(timeout)
+	err = watcher.ForObject(c.ctx, client, name).RetryUntil(namespaceIsActive, timeout)
+	if err != nil {
+		return err
+	}
+
+	if waitForServiceAccount {
+		serviceAccountExists := func(_ *unstructured.Unstructured, err error) error {
+			if is404(err) {
+				c.logStatus(diag.Info, "[2/2] Waiting for the default ServiceAccount to be created")
+				return watcher.RetryableError(fmt.Errorf("namespace %q has no default ServiceAccount", name))
+			}
+			return err
+		}
+
+		saClient, err := clients.ResourceClient(kinds.ServiceAccount, name, c.clientSet)
+		if err != nil {
+			return err
+		}
+		err = watcher.ForObject(c.ctx, saClient, "default").RetryUntil(serviceAccountExists, time.Until(deadline))
+		if err != nil {
+			return errors.Wrapf(err, "default ServiceAccount of Namespace %q was not created", name)
+		}
+	}
+
+	c.logStatus(diag.Info, fmt.Sprintf("%sNamespace is active", cmdutil.EmojiOr("✅ ", "")))
+	logger.V(3).Infof("Namespace %q is active", name)
+
+	return nil
+}
+
+func untilCoreV1NamespaceUpdated(c updateAwaitConfig) error {
+	return untilCoreV1NamespaceActive(c.createAwaitConfig)
+}
+
 func untilCoreV1NamespaceDeleted(config deleteAwaitConfig) error {
+	//
+	// A terminating Namespace is deleted once everything in it has been deleted. If that takes a
+	// while, report what is left, as described by the conditions of the Namespace.
+	//
+	var lastMessage string
 	namespaceMissingOrKilled := func(ns *unstructured.Unstructured, err error) error {
 		if is404(err) {
 			return nil
@@ -393,13 +974,24 @@ func untilCoreV1NamespaceDeleted(config deleteAwaitConfig) error {
 			return nil
 		}
 
+		if blockers := namespaceDeletionBlockers(ns); len(blockers) > 0 {
+			lastMessage = fmt.Sprintf("namespace %q is still terminating: %s",
+				config.currentInputs.GetName(), strings.Join(blockers, "; "))
+			config.logStatus(diag.Info, fmt.Sprintf("Waiting for Namespace contents to be deleted: %s",
+				strings.Join(blockers, "; ")))
+		}
+
 		return watcher.RetryableError(fmt.Errorf("namespace %q still exists (%v)",
 			config.currentInputs.GetName(), statusPhase))
 	}
 
-	timeout := metadata.TimeoutDuration(config.timeout, config.currentInputs, 300)
-	return watcher.ForObject(config.ctx, config.clientForResource, config.currentInputs.GetName()).
+	timeout := metadata.TimeoutDuration(config.timeout, config.defaultTimeouts, config.currentInputs, 300)
+	err := watcher.ForObject(config.ctx, config.clientForResource, config.currentInputs.GetName()).
 		RetryUntil(namespaceMissingOrKilled, timeout)
+	if err != nil && lastMessage != "" {
+		return errors.Wrap(err, lastMessage)
+	}
+	return err
 }
 
 // --------------------------------------------------------------------------
@@ -424,8 +1016,9 @@ func untilCoreV1PersistentVolumeInitialized(c createAwaitConfig) error {
 	if err != nil {
 		return err
 	}
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 300)
 	return watcher.ForObject(c.ctx, client, c.currentInputs.GetName()).
-		WatchUntil(pvAvailableOrBound, 5*time.Minute)
+		WatchUntil(pvAvailableOrBound, timeout)
 }
 
 // --------------------------------------------------------------------------
@@ -434,19 +1027,205 @@ func untilCoreV1PersistentVolumeInitialized(c createAwaitConfig) error {
 
 // --------------------------------------------------------------------------
 
+const (
+	// pvcStorageClassAnnotation is the deprecated annotation that specifies the StorageClass of a
+	// PersistentVolumeClaim. It takes precedence over `.spec.storageClassName`.
+	pvcStorageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
+
+	// Reasons of the Events the PersistentVolume controller reports for PersistentVolumeClaims.
+	pvcWaitForFirstConsumerReason = "WaitForFirstConsumer"
+	pvcProvisioningFailedReason   = "ProvisioningFailed"
+)
+
+// defaultStorageClassAnnotations mark the default StorageClass of a cluster.
+var defaultStorageClassAnnotations = []string{
+	"storageclass.kubernetes.io/is-default-class",
+	"storageclass.beta.kubernetes.io/is-default-class",
+}
+
+// isDefaultStorageClass returns true if the StorageClass is marked as the default StorageClass.
+func isDefaultStorageClass(sc *unstructured.Unstructured) bool {
+	for _, annotation := range defaultStorageClassAnnotations {
+		if sc.GetAnnotations()[annotation] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// pvcStorageClass returns the StorageClass of a PersistentVolumeClaim, or nil if the claim does not
+// use a StorageClass. If the claim does not specify a StorageClass, the default StorageClass is
+// returned, if there is one.
+func pvcStorageClass(c createAwaitConfig, pvc *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	client, err := clients.ResourceClient(kinds.StorageClass, "", c.clientSet)
+	if err != nil {
+		return nil, err
+	}
+
+	name, hasName := pvc.GetAnnotations()[pvcStorageClassAnnotation]
+	if !hasName {
+		name, hasName, _ = unstructured.NestedString(pvc.Object, "spec", "storageClassName")
+	}
+	if hasName {
+		// An empty StorageClass name explicitly requests a PersistentVolume without a StorageClass.
+		if name == "" {
+			return nil, nil
+		}
+		return client.Get(context.TODO(), name, metav1.GetOptions{})
+	}
+
+	storageClasses, err := client.List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range storageClasses.Items {
+		if sc := &storageClasses.Items[i]; isDefaultStorageClass(sc) {
+			return sc, nil
+		}
+	}
+	return nil, nil
+}
+
+// isWaitForFirstConsumer returns true if the StorageClass delays binding and provisioning of a
+// PersistentVolume until a Pod using the PersistentVolumeClaim is created.
+func isWaitForFirstConsumer(sc *unstructured.Unstructured) bool {
+	if sc == nil {
+		return false
+	}
+	mode, _, _ := unstructured.NestedString(sc.Object, "volumeBindingMode")
+	return mode == pvcWaitForFirstConsumerReason
+}
+
+// pvcProvisioningReasons are the reasons of the Events that report an attempt to provision a
+// PersistentVolume, and its outcome.
+var pvcProvisioningReasons = sets.NewString("Provisioning", "ProvisioningSucceeded", pvcProvisioningFailedReason)
+
+// pvcProvisioningFailure returns the most recent provisioning Event, formatted as an error message, if
+// it is a ProvisioningFailed Event, or the empty string otherwise. A failure that was followed by
+// another attempt, or by a success, is transient.
+func pvcProvisioningFailure(events []v1.Event) string {
+	var latest *v1.Event
+	for i := range events {
+		e := &events[i]
+		if !pvcProvisioningReasons.Has(e.Reason) {
+			continue
+		}
+		if latest == nil || eventTime(*e).After(eventTime(*latest)) {
+			latest = e
+		}
+	}
+
+	if latest == nil || latest.Reason != pvcProvisioningFailedReason {
+		return ""
+	}
+	return formatWarning(*latest)
+}
+
+// eventsForUID returns the Events whose involved object has the specified UID. Events are looked up by
+// the name of the involved object, so this excludes the Events of earlier objects with the same name.
+func eventsForUID(events []v1.Event, uid types.UID) []v1.Event {
+	var filtered []v1.Event
+	for _, e := range events {
+		if e.InvolvedObject.UID == uid {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// hasEventWithReason returns true if any of the Events has the specified reason.
+func hasEventWithReason(events []v1.Event, reason string) bool {
+	for _, e := range events {
+		if e.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
 func untilCoreV1PersistentVolumeClaimBound(c createAwaitConfig) error {
-	pvcBound := func(pvc *unstructured.Unstructured) bool {
-		statusPhase, _ := openapi.Pluck(pvc.Object, "status", "phase")
-		logger.V(3).Infof("Persistent volume claim %s status received: %#v", pvc.GetName(), statusPhase)
-		return statusPhase == statusBound
+	//
+	// A PersistentVolumeClaim is considered ready when it is bound to a PersistentVolume. If its
+	// StorageClass uses the `WaitForFirstConsumer` volume binding mode, binding is delayed until a Pod
+	// uses the claim, so the claim is also considered ready once the PersistentVolume controller has
+	// reported (with a `WaitForFirstConsumer` Event) that it is waiting for that Pod. The await fails
+	// immediately if the most recent attempt to provision a PersistentVolume for the claim failed.
+	//
+	name := c.currentInputs.GetName()
+	namespace := c.currentInputs.GetNamespace()
+
+	sc, err := pvcStorageClass(c, c.currentOutputs)
+	if err != nil {
+		logger.V(3).Infof("Failed to get StorageClass of PersistentVolumeClaim %q: %v", name, err)
 	}
 
-	client, err := c.clientSet.ResourceClient(c.currentInputs.GroupVersionKind(), c.currentInputs.GetNamespace())
+	eventClient, err := clients.ResourceClient(kinds.Event, namespace, c.clientSet)
 	if err != nil {
 		return err
 	}
-	return watcher.ForObject(c.ctx, client, c.currentInputs.GetName()).
-		WatchUntil(pvcBound, 5*time.Minute)
+
+	client, err := c.clientSet.ResourceClient(c.currentInputs.GroupVersionKind(), namespace)
+	if err != nil {
+		return err
+	}
+
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 300)
+	return awaitPVCBound(c, client, eventClient, sc, timeout)
+}
+
+// awaitPVCBound is a helper companion to `untilCoreV1PersistentVolumeClaimBound` designed to make it easy
+// to test this module.
+func awaitPVCBound(
+	c createAwaitConfig, client, eventClient dynamic.ResourceInterface, sc *unstructured.Unstructured,
+	timeout time.Duration,
+) error {
+	name := c.currentInputs.GetName()
+	namespace := c.currentInputs.GetNamespace()
+
+	var storageClassName string
+	if sc != nil {
+		storageClassName = sc.GetName()
+	}
+	waitForFirstConsumer := isWaitForFirstConsumer(sc)
+
+	pvcBound := func(pvc *unstructured.Unstructured, err error) error {
+		if err != nil {
+			return err
+		}
+
+		statusPhase, _, _ := unstructured.NestedString(pvc.Object, "status", "phase")
+		logger.V(3).Infof("Persistent volume claim %s status received: %#v", pvc.GetName(), statusPhase)
+		if statusPhase == statusBound {
+			c.logStatus(diag.Info, fmt.Sprintf("%sPersistentVolumeClaim has been bound", cmdutil.EmojiOr("✅ ", "")))
+			return nil
+		}
+
+		events, err := getEventsForObject(eventClient, namespace, name, string(kinds.PersistentVolumeClaim))
+		if err != nil {
+			logger.V(3).Infof("Failed to get Events for PersistentVolumeClaim %q: %v", name, err)
+		}
+		// Ignore the Events of an earlier claim with the same name, e.g., one that was replaced.
+		events = eventsForUID(events, pvc.GetUID())
+
+		if failure := pvcProvisioningFailure(events); failure != "" {
+			return fmt.Errorf("failed to provision a PersistentVolume for PersistentVolumeClaim %q: %s",
+				name, failure)
+		}
+
+		if waitForFirstConsumer && statusPhase == "Pending" &&
+			hasEventWithReason(events, pvcWaitForFirstConsumerReason) {
+			c.logStatus(diag.Info, fmt.Sprintf(
+				"%sPersistentVolumeClaim will be bound when a Pod uses it (StorageClass %q uses the "+
+					"WaitForFirstConsumer volume binding mode)", cmdutil.EmojiOr("✅ ", ""), storageClassName))
+			return nil
+		}
+
+		c.logStatus(diag.Info, "Waiting for PersistentVolumeClaim to be bound")
+		return watcher.RetryableError(fmt.Errorf("PersistentVolumeClaim %q is not bound (phase: %s)",
+			name, statusPhase))
+	}
+
+	return watcher.ForObject(c.ctx, client, name).RetryUntil(pvcBound, timeout)
 }
 
 // --------------------------------------------------------------------------
@@ -470,7 +1249,7 @@ func untilCoreV1PodDeleted(config deleteAwaitConfig) error {
 		return watcher.RetryableError(e)
 	}
 
-	timeout := metadata.TimeoutDuration(config.timeout, config.currentInputs, 300)
+	timeout := metadata.TimeoutDuration(config.timeout, config.defaultTimeouts, config.currentInputs, 300)
 	return watcher.ForObject(config.ctx, config.clientForResource, config.currentInputs.GetName()).
 		RetryUntil(podMissingOrKilled, timeout)
 }
@@ -501,10 +1280,11 @@ func untilCoreV1ReplicationControllerInitialized(c createAwaitConfig) error {
 		return err
 	}
 	// 10 mins should be sufficient for scheduling ~10k replicas
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 600)
 	err = watcher.ForObject(c.ctx, client, name).
 		WatchUntil(
 			waitForDesiredReplicasFunc(replicationControllerSpecReplicas, availableReplicas),
-			10*time.Minute)
+			timeout)
 	if err != nil {
 		return err
 	}
@@ -550,7 +1330,7 @@ func untilCoreV1ReplicationControllerDeleted(config deleteAwaitConfig) error {
 	}
 
 	// Wait until all replicas are gone. 10 minutes should be enough for ~10k replicas.
-	timeout := metadata.TimeoutDuration(config.timeout, config.currentInputs, 600)
+	timeout := metadata.TimeoutDuration(config.timeout, config.defaultTimeouts, config.currentInputs, 600)
 	err := watcher.ForObject(config.ctx, config.clientForResource, config.currentInputs.GetName()).
 		RetryUntil(rcMissing, timeout)
 	if err != nil {
@@ -569,6 +1349,16 @@ func untilCoreV1ReplicationControllerDeleted(config deleteAwaitConfig) error {
 // --------------------------------------------------------------------------
 
 func untilCoreV1ResourceQuotaInitialized(c createAwaitConfig) error {
+	client, err := c.clientSet.ResourceClient(c.currentInputs.GroupVersionKind(), c.currentInputs.GetNamespace())
+	if err != nil {
+		return err
+	}
+	return awaitResourceQuotaInitialized(c, client)
+}
+
+// awaitResourceQuotaInitialized is a helper companion to `untilCoreV1ResourceQuotaInitialized` designed to
+// make it easy to test this module.
+func awaitResourceQuotaInitialized(c createAwaitConfig, client dynamic.ResourceInterface) error {
 	rqInitialized := func(quota *unstructured.Unstructured) bool {
 		hardRaw, _ := openapi.Pluck(quota.Object, "spec", "hard")
 		hardStatusRaw, _ := openapi.Pluck(quota.Object, "status", "hard")
@@ -585,12 +1375,9 @@ func untilCoreV1ResourceQuotaInitialized(c createAwaitConfig) error {
 		return false
 	}
 
-	client, err := c.clientSet.ResourceClient(c.currentInputs.GroupVersionKind(), c.currentInputs.GetNamespace())
-	if err != nil {
-		return err
-	}
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 60)
 	return watcher.ForObject(c.ctx, client, c.currentInputs.GetName()).
-		WatchUntil(rqInitialized, 1*time.Minute)
+		WatchUntil(rqInitialized, timeout)
 }
 
 func untilCoreV1ResourceQuotaUpdated(c updateAwaitConfig) error {
@@ -638,8 +1425,61 @@ func untilCoreV1ServiceAccountInitialized(c createAwaitConfig) error {
 	if err != nil {
 		return err
 	}
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 300)
 	return watcher.ForObject(c.ctx, client, c.currentOutputs.GetName()).
-		WatchUntil(defaultSecretAllocated, 5*time.Minute)
+		WatchUntil(defaultSecretAllocated, timeout)
+}
+
+// --------------------------------------------------------------------------
+
+// policy/v1beta1/PodDisruptionBudget
+
+// --------------------------------------------------------------------------
+
+func untilPolicyV1Beta1PodDisruptionBudgetObserved(c createAwaitConfig) error {
+	//
+	// A PodDisruptionBudget is considered ready once the disruption controller has observed the
+	// current generation. A PDB whose selector matches no Pods protects nothing, which is almost
+	// always a mistake, so warn if that's the case.
+	//
+	var pdb *unstructured.Unstructured
+	pdbObserved := func(obj *unstructured.Unstructured, err error) error {
+		if err != nil {
+			return err
+		}
+		pdb = obj
+
+		observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+		if observedGeneration < obj.GetGeneration() {
+			c.logStatus(diag.Info, "Waiting for PodDisruptionBudget controller to observe the new spec")
+			return watcher.RetryableError(fmt.Errorf(
+				"PodDisruptionBudget controller has not observed generation %d (observed generation: %d)",
+				obj.GetGeneration(), observedGeneration))
+		}
+
+		return nil
+	}
+
+	client, err := c.clientSet.ResourceClient(c.currentInputs.GroupVersionKind(), c.currentInputs.GetNamespace())
+	if err != nil {
+		return err
+	}
+
+	timeout := metadata.TimeoutDuration(c.timeout, c.defaultTimeouts, c.currentInputs, 300)
+	err = watcher.ForObject(c.ctx, client, c.currentInputs.GetName()).
+		RetryUntil(pdbObserved, timeout)
+	if err != nil {
+		return err
+	}
+
+	if expectedPods, _, _ := unstructured.NestedInt64(pdb.Object, "status", "expectedPods"); expectedPods == 0 {
+		c.logStatus(diag.Warning,
+			"PodDisruptionBudget selector does not match any Pods (expectedPods is 0); check .spec.selector")
+	}
+
+	logger.V(3).Infof("PodDisruptionBudget %q observed", c.currentInputs.GetName())
+
+	return nil
 }
 
 // --------------------------------------------------------------------------
@@ -648,6 +1488,20 @@ func untilCoreV1ServiceAccountInitialized(c createAwaitConfig) error {
 
 // --------------------------------------------------------------------------
 
+// findCondition returns the entry of `.status.conditions` with the given type, or nil if there is no
+// such condition.
+func findCondition(obj *unstructured.Unstructured, conditionType string) map[string]interface{} {
+	rawConditions, _ := openapi.Pluck(obj.Object, "status", "conditions")
+	conditions, _ := rawConditions.([]interface{})
+	for _, rawCondition := range conditions {
+		condition, isMap := rawCondition.(map[string]interface{})
+		if isMap && condition["type"] == conditionType {
+			return condition
+		}
+	}
+	return nil
+}
+
 // waitForDesiredReplicasFunc takes an object whose job is to replicate pods, and blocks (polling)
 // it until the desired replicas are the same as the current replicas. The user provides two
 // functions to obtain the replicas spec and status fields, as well as a client to access them.
@@ -0,0 +1,511 @@
+// nolint: goconst
+package await
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_webhookServices(t *testing.T) {
+	obj, err := decodeUnstructured(`{
+    "apiVersion": "admissionregistration.k8s.io/v1",
+    "kind": "ValidatingWebhookConfiguration",
+    "metadata": {
+        "name": "foo"
+    },
+    "webhooks": [
+        {
+            "name": "pods.foo.example.com",
+            "clientConfig": {
+                "service": {
+                    "namespace": "foo-system",
+                    "name": "foo-webhook"
+                }
+            }
+        },
+        {
+            "name": "external.foo.example.com",
+            "clientConfig": {
+                "url": "https://foo.example.com/validate"
+            }
+        },
+        {
+            "name": "deployments.foo.example.com",
+            "clientConfig": {
+                "service": {
+                    "namespace": "foo-system",
+                    "name": "foo-webhook"
+                }
+            }
+        },
+        {
+            "name": "services.foo.example.com",
+            "clientConfig": {
+                "service": {
+                    "namespace": "default",
+                    "name": "bar-webhook"
+                }
+            }
+        }
+    ]
+}`)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, []*webhookService{
+		{
+			namespace: "foo-system",
+			name:      "foo-webhook",
+			webhooks:  []string{"pods.foo.example.com", "deployments.foo.example.com"},
+		},
+		{
+			namespace: "default",
+			name:      "bar-webhook",
+			webhooks:  []string{"services.foo.example.com"},
+		},
+	}, webhookServices(obj))
+}
+
+func Test_hasReadyEndpoints(t *testing.T) {
+	tests := []struct {
+		description string
+		endpoints   string
+		expected    bool
+	}{
+		{
+			description: "Endpoints with no subsets are not ready",
+			endpoints:   `{"apiVersion": "v1", "kind": "Endpoints", "metadata": {"name": "foo"}}`,
+			expected:    false,
+		},
+		{
+			description: "Endpoints with only unready addresses are not ready",
+			endpoints: `{"apiVersion": "v1", "kind": "Endpoints", "metadata": {"name": "foo"},
+				"subsets": [{"notReadyAddresses": [{"ip": "10.0.0.1"}], "ports": [{"port": 443}]}]}`,
+			expected: false,
+		},
+		{
+			description: "Endpoints with a ready address are ready",
+			endpoints: `{"apiVersion": "v1", "kind": "Endpoints", "metadata": {"name": "foo"},
+				"subsets": [{"addresses": [{"ip": "10.0.0.1"}], "ports": [{"port": 443}]}]}`,
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		obj, err := decodeUnstructured(test.endpoints)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(t, test.expected, hasReadyEndpoints(obj), test.description)
+	}
+}
+
+func Test_findCondition(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "False", "reason": "MissingEndpoints"},
+			},
+		},
+	}}
+
+	assert.Equal(t, "MissingEndpoints", findCondition(obj, "Available")["reason"])
+	assert.Nil(t, findCondition(obj, "Established"))
+}
+
+func Test_hpaCondition(t *testing.T) {
+	v1, err := decodeUnstructured(`{
+    "apiVersion": "autoscaling/v1",
+    "kind": "HorizontalPodAutoscaler",
+    "metadata": {
+        "name": "foo",
+        "annotations": {
+            "autoscaling.alpha.kubernetes.io/conditions": "[{\"type\":\"AbleToScale\",\"status\":\"False\",\"reason\":\"FailedGetScale\",\"message\":\"deployments/scale.apps \\\"foo\\\" not found\"}]"
+        }
+    }
+}`)
+	if err != nil {
+		panic(err)
+	}
+	v2, err := decodeUnstructured(`{
+    "apiVersion": "autoscaling/v2beta2",
+    "kind": "HorizontalPodAutoscaler",
+    "metadata": {
+        "name": "foo"
+    },
+    "status": {
+        "conditions": [
+            {"type": "AbleToScale", "status": "True", "reason": "SucceededGetScale"},
+            {"type": "ScalingActive", "status": "False", "reason": "FailedGetResourceMetric"}
+        ]
+    }
+}`)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, "FailedGetScale", hpaCondition(v1, "AbleToScale")["reason"])
+	assert.Equal(t, `deployments/scale.apps "foo" not found`, hpaCondition(v1, "AbleToScale")["message"])
+	assert.Nil(t, hpaCondition(v1, "ScalingActive"))
+	assert.Equal(t, "SucceededGetScale", hpaCondition(v2, "AbleToScale")["reason"])
+	assert.Equal(t, "FailedGetResourceMetric", hpaCondition(v2, "ScalingActive")["reason"])
+}
+
+func Test_awaitCRDEstablished(t *testing.T) {
+	tests := []struct {
+		description   string
+		conditions    string
+		expectedError string
+		timeout       bool
+	}{
+		{
+			description: "Should succeed when names are accepted and the CRD is established",
+			conditions: `[
+                {"type": "NamesAccepted", "status": "True", "reason": "NoConflicts"},
+                {"type": "Established", "status": "True", "reason": "InitialNamesAccepted"}
+            ]`,
+		},
+		{
+			description: "Should fail immediately when names conflict with another CRD",
+			conditions: `[
+                {"type": "NamesAccepted", "status": "False", "reason": "ListKindConflict",
+                 "message": "\"FooList\" is already in use"},
+                {"type": "Established", "status": "False", "reason": "NotAccepted"}
+            ]`,
+			expectedError: `CustomResourceDefinition "foos.example.com" names were not accepted: ` +
+				`[ListKindConflict] "FooList" is already in use`,
+		},
+		{
+			description: "Should time out when the CRD is not established",
+			conditions: `[
+                {"type": "NamesAccepted", "status": "True", "reason": "NoConflicts"},
+                {"type": "Established", "status": "False", "reason": "Installing"}
+            ]`,
+			timeout: true,
+		},
+	}
+
+	for _, test := range tests {
+		crd := crdWithConditions(test.conditions)
+		client := &staticResourceInterface{obj: crd}
+		err := awaitCRDEstablished(mockAwaitConfig(crd), client, time.Second)
+
+		switch {
+		case test.timeout:
+			_, isPartialErr := err.(PartialError)
+			assert.True(t, isPartialErr, test.description)
+			assert.Equal(t, "Timeout occurred polling for 'foos.example.com'", err.Error(), test.description)
+		case test.expectedError != "":
+			assert.EqualError(t, err, test.expectedError, test.description)
+		default:
+			assert.NoError(t, err, test.description)
+		}
+	}
+}
+
+func Test_storageClass(t *testing.T) {
+	storageClass := func(annotations map[string]string, bindingMode string) *unstructured.Unstructured {
+		sc := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion":        "storage.k8s.io/v1",
+			"kind":              "StorageClass",
+			"volumeBindingMode": bindingMode,
+		}}
+		sc.SetName("standard")
+		sc.SetAnnotations(annotations)
+		return sc
+	}
+
+	immediate := storageClass(nil, "Immediate")
+	assert.False(t, isDefaultStorageClass(immediate))
+	assert.False(t, isWaitForFirstConsumer(immediate))
+	assert.False(t, isWaitForFirstConsumer(nil))
+
+	waitForFirstConsumer := storageClass(
+		map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}, "WaitForFirstConsumer")
+	assert.True(t, isDefaultStorageClass(waitForFirstConsumer))
+	assert.True(t, isWaitForFirstConsumer(waitForFirstConsumer))
+
+	beta := storageClass(map[string]string{"storageclass.beta.kubernetes.io/is-default-class": "true"}, "")
+	assert.True(t, isDefaultStorageClass(beta))
+	assert.False(t, isWaitForFirstConsumer(beta))
+}
+
+func Test_pvcEvents(t *testing.T) {
+	event := func(eventType, reason, message string, minute int) v1.Event {
+		return v1.Event{
+			Type:          eventType,
+			Reason:        reason,
+			Message:       message,
+			LastTimestamp: metav1.NewTime(time.Date(2020, 5, 14, 18, minute, 0, 0, time.UTC)),
+		}
+	}
+
+	waiting := []v1.Event{
+		event(v1.EventTypeNormal, "WaitForFirstConsumer",
+			"waiting for first consumer to be created before binding", 1),
+	}
+	assert.True(t, hasEventWithReason(waiting, "WaitForFirstConsumer"))
+	assert.False(t, hasEventWithReason(waiting, "ProvisioningFailed"))
+	assert.Equal(t, "", pvcProvisioningFailure(waiting))
+	assert.Equal(t, "", pvcProvisioningFailure(nil))
+
+	failed := []v1.Event{
+		event(v1.EventTypeNormal, "ExternalProvisioning", "waiting for a volume to be created", 1),
+		event(v1.EventTypeWarning, "ProvisioningFailed", `storageclass.storage.k8s.io "fast" not found`, 2),
+		event(v1.EventTypeWarning, "ProvisioningFailed", "quota exceeded", 3),
+	}
+	assert.Equal(t, "[ProvisioningFailed] quota exceeded", pvcProvisioningFailure(failed))
+
+	recovered := []v1.Event{
+		event(v1.EventTypeWarning, "ProvisioningFailed", "quota exceeded", 1),
+		event(v1.EventTypeNormal, "ProvisioningSucceeded", "successfully provisioned volume", 2),
+	}
+	assert.Equal(t, "", pvcProvisioningFailure(recovered))
+
+	retrying := []v1.Event{
+		event(v1.EventTypeWarning, "ProvisioningFailed", "quota exceeded", 1),
+		event(v1.EventTypeNormal, "Provisioning", "External provisioner is provisioning volume", 2),
+	}
+	assert.Equal(t, "", pvcProvisioningFailure(retrying))
+
+	stale, current := event(v1.EventTypeWarning, "ProvisioningFailed", "quota exceeded", 1),
+		event(v1.EventTypeNormal, "ExternalProvisioning", "waiting for a volume to be created", 2)
+	stale.InvolvedObject.UID, current.InvolvedObject.UID = "old", "new"
+	assert.Equal(t, []v1.Event{current}, eventsForUID([]v1.Event{stale, current}, "new"))
+}
+
+func Test_awaitPVCBound(t *testing.T) {
+	event := func(uid types.UID, eventType, reason string, minute int) unstructured.Unstructured {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&v1.Event{
+			InvolvedObject: v1.ObjectReference{Kind: "PersistentVolumeClaim", Name: "data", UID: uid},
+			Type:           eventType,
+			Reason:         reason,
+			Message:        "message for " + reason,
+			LastTimestamp:  metav1.NewTime(time.Date(2020, 5, 14, 18, minute, 0, 0, time.UTC)),
+		})
+		if err != nil {
+			panic(err)
+		}
+		return unstructured.Unstructured{Object: obj}
+	}
+	waitForFirstConsumer := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion":        "storage.k8s.io/v1",
+		"kind":              "StorageClass",
+		"metadata":          map[string]interface{}{"name": "standard"},
+		"volumeBindingMode": "WaitForFirstConsumer",
+	}}
+
+	tests := []struct {
+		description   string
+		storageClass  *unstructured.Unstructured
+		events        []unstructured.Unstructured
+		timeout       bool
+		expectedError string
+	}{
+		{
+			description: "Should fail when the latest attempt to provision the claim failed",
+			events: []unstructured.Unstructured{
+				event("new", v1.EventTypeNormal, "Provisioning", 1),
+				event("new", v1.EventTypeWarning, "ProvisioningFailed", 2),
+			},
+			expectedError: `failed to provision a PersistentVolume for PersistentVolumeClaim "data": ` +
+				`[ProvisioningFailed] message for ProvisioningFailed`,
+		},
+		{
+			description: "Should ignore provisioning failures of an earlier claim with the same name",
+			events: []unstructured.Unstructured{
+				event("old", v1.EventTypeWarning, "ProvisioningFailed", 1),
+				event("new", v1.EventTypeNormal, "ExternalProvisioning", 2),
+			},
+			timeout: true,
+		},
+		{
+			description: "Should ignore a provisioning failure that was retried",
+			events: []unstructured.Unstructured{
+				event("new", v1.EventTypeWarning, "ProvisioningFailed", 1),
+				event("new", v1.EventTypeNormal, "Provisioning", 2),
+			},
+			timeout: true,
+		},
+		{
+			description:  "Should succeed when the claim waits for its first consumer",
+			storageClass: waitForFirstConsumer,
+			events: []unstructured.Unstructured{
+				event("new", v1.EventTypeNormal, "WaitForFirstConsumer", 1),
+			},
+		},
+		{
+			description:  "Should ignore a WaitForFirstConsumer Event of an earlier claim with the same name",
+			storageClass: waitForFirstConsumer,
+			events: []unstructured.Unstructured{
+				event("old", v1.EventTypeNormal, "WaitForFirstConsumer", 1),
+			},
+			timeout: true,
+		},
+	}
+
+	for _, test := range tests {
+		pvc := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "PersistentVolumeClaim",
+			"metadata":   map[string]interface{}{"name": "data", "namespace": "default", "uid": "new"},
+			"status":     map[string]interface{}{"phase": "Pending"},
+		}}
+		client := &staticResourceInterface{obj: pvc}
+		eventClient := &listResourceInterface{items: test.events}
+		err := awaitPVCBound(mockAwaitConfig(pvc), client, eventClient, test.storageClass, time.Second)
+
+		switch {
+		case test.timeout:
+			_, isPartialErr := err.(PartialError)
+			assert.True(t, isPartialErr, test.description)
+			assert.Equal(t, "Timeout occurred polling for 'data'", err.Error(), test.description)
+		case test.expectedError != "":
+			assert.EqualError(t, err, test.expectedError, test.description)
+		default:
+			assert.NoError(t, err, test.description)
+		}
+	}
+}
+
+// listResourceInterface serves a fixed list of objects.
+type listResourceInterface struct {
+	mockResourceInterface
+	items []unstructured.Unstructured
+}
+
+func (l *listResourceInterface) List(
+	ctx context.Context, opts metav1.ListOptions,
+) (*unstructured.UnstructuredList, error) {
+	return &unstructured.UnstructuredList{Items: l.items}, nil
+}
+
+func Test_namespaceDeletionBlockers(t *testing.T) {
+	ns, err := decodeUnstructured(`{
+    "apiVersion": "v1",
+    "kind": "Namespace",
+    "metadata": {
+        "name": "foo"
+    },
+    "status": {
+        "phase": "Terminating",
+        "conditions": [
+            {
+                "type": "NamespaceDeletionDiscoveryFailure",
+                "status": "False",
+                "reason": "ResourcesDiscovered",
+                "message": "All resources successfully discovered"
+            },
+            {
+                "type": "NamespaceContentRemaining",
+                "status": "True",
+                "reason": "SomeResourcesRemain",
+                "message": "Some resources are remaining: persistentvolumeclaims. has 1 resource instances, pods. has 2 resource instances"
+            },
+            {
+                "type": "NamespaceFinalizersRemaining",
+                "status": "True",
+                "reason": "SomeFinalizersRemain",
+                "message": "Some content in the namespace has finalizers remaining: kubernetes.io/pvc-protection in 1 resource instances"
+            }
+        ]
+    }
+}`)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, []string{
+		"Some resources are remaining: persistentvolumeclaims. has 1 resource instances, pods. has 2 resource instances",
+		"Some content in the namespace has finalizers remaining: kubernetes.io/pvc-protection in 1 resource instances",
+	}, namespaceDeletionBlockers(ns))
+
+	active := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"phase": "Active"},
+	}}
+	assert.Nil(t, namespaceDeletionBlockers(active))
+}
+
+func crdWithConditions(conditions string) *unstructured.Unstructured {
+	obj, err := decodeUnstructured(`{
+    "apiVersion": "apiextensions.k8s.io/v1",
+    "kind": "CustomResourceDefinition",
+    "metadata": {
+        "name": "foos.example.com"
+    },
+    "status": {
+        "conditions": ` + conditions + `
+    }
+}`)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+func Test_awaitResourceQuotaInitialized(t *testing.T) {
+	quota := func(statusHard string) *unstructured.Unstructured {
+		obj, err := decodeUnstructured(fmt.Sprintf(`{
+    "apiVersion": "v1",
+    "kind": "ResourceQuota",
+    "metadata": {
+        "name": "compute",
+        "namespace": "default"
+    },
+    "spec": {
+        "hard": {"pods": "10"}
+    },
+    "status": {
+        "hard": %s
+    }
+}`, statusHard))
+		if err != nil {
+			panic(err)
+		}
+		return obj
+	}
+
+	tests := []struct {
+		description string
+		quota       *unstructured.Unstructured
+		timeout     bool
+	}{
+		{
+			description: "Should succeed when the quota has been enforced",
+			quota:       quota(`{"pods": "10"}`),
+		},
+		{
+			description: "Should time out after the default timeout configured for ResourceQuotas",
+			quota:       quota(`{}`),
+			timeout:     true,
+		},
+	}
+
+	for _, test := range tests {
+		config := mockAwaitConfig(test.quota)
+		config.defaultTimeouts = &metadata.DefaultTimeouts{Seconds: map[string]int{"ResourceQuota": 1}, Multiplier: 1}
+
+		start := time.Now()
+		err := awaitResourceQuotaInitialized(config, &staticResourceInterface{obj: test.quota})
+
+		if test.timeout {
+			_, isPartialErr := err.(PartialError)
+			assert.True(t, isPartialErr, test.description)
+			// The built-in timeout of a ResourceQuota is one minute.
+			assert.Less(t, int64(time.Since(start)), int64(30*time.Second), test.description)
+		} else {
+			assert.NoError(t, err, test.description)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package await
+
+import (
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/states"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ------------------------------------------------------------------------------------------------
+
+// Await logic for extensions/v1beta1/DaemonSet, apps/v1beta2/DaemonSet, and apps/v1/DaemonSet.
+//
+// The goal of this code is to provide a fine-grained account of the status of a Kubernetes
+// DaemonSet as it is being rolled out. The idea is that if something goes wrong early, we want to
+// alert the user so they can cancel the operation instead of waiting for timeout (~10 minutes).
+//
+// A DaemonSet ensures that a copy of a Pod runs on every eligible Node in the cluster. Unlike a
+// Deployment or StatefulSet, the number of replicas is not specified by the user, but is computed by
+// the DaemonSet controller from the set of Nodes that the Pod template can be scheduled to.
+//
+// The success conditions are expressed by `states.NewDaemonSetChecker`, and depend on the update
+// strategy:
+//
+//   1. `.status.observedGeneration` must be at least `.metadata.generation`, which indicates that
+//      the DaemonSet controller has acknowledged the current spec.
+//   2. For the `RollingUpdate` strategy (the default), `.status.updatedNumberScheduled` and
+//      `.status.numberAvailable` must both match `.status.desiredNumberScheduled`.
+//   3. For the `OnDelete` strategy, Pods are only replaced when they are deleted out-of-band, so
+//      after the initial rollout we only wait for the spec to be observed, and report how many Pods
+//      are still running the old template.
+//
+// The event loop is implemented by the generic `stateAwaiter`, which also monitors the Pods owned
+// by the DaemonSet and reports any warnings/errors they produce.
+//
+// x-refs:
+//   * https://kubernetes.io/docs/concepts/workloads/controllers/daemonset/
+//   * https://kubernetes.io/docs/tasks/manage-daemon/update-daemon-set/
+
+// ------------------------------------------------------------------------------------------------
+
+const (
+	DefaultDaemonSetTimeoutMins = 10
+)
+
+func makeDaemonSetInitAwaiter(c updateAwaitConfig) *stateAwaiter {
+	podOwner := ResourceID{
+		Name:      c.currentInputs.GetName(),
+		Namespace: clients.NamespaceOrDefault(c.currentInputs.GetNamespace()),
+		GVK: schema.FromAPIVersionAndKind(
+			canonicalizeDaemonSetAPIVersion(c.currentInputs.GetAPIVersion()), string(kinds.DaemonSet)),
+		// NOTE: Pods created by the DaemonSet controller don't carry a generation, so leave this unset
+		// to match them.
+	}
+	return makeStateAwaiter(c, kinds.DaemonSet, states.NewDaemonSetChecker, DefaultDaemonSetTimeoutMins*60, &podOwner)
+}
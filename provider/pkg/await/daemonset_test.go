@@ -0,0 +1,297 @@
+// nolint: goconst
+package await
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func Test_Apps_DaemonSet(t *testing.T) {
+	const (
+		inputNamespace = "default"
+		inputName      = "foo"
+	)
+	podError := logging.WarningMessage(
+		"[Pod foo-abcde]: containers with unready status: [nginx] -- [ErrImagePull] manifest for nginx:busted not found")
+
+	tests := []struct {
+		description   string
+		strategy      string
+		do            func(daemonsets chan watch.Event, pods chan logging.Messages, timeout chan time.Time)
+		expectedError error
+	}{
+		{
+			description: "[Generation 1] Should succeed after creating DaemonSet",
+			strategy:    "RollingUpdate",
+			do: func(daemonsets chan watch.Event, pods chan logging.Messages, timeout chan time.Time) {
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 1, 0, 0, 0, 0))
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 1, 1, 3, 3, 1))
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 1, 1, 3, 3, 3))
+
+				// Timeout. Success.
+				timeout <- time.Now()
+			},
+		},
+		{
+			description: "[Generation 2] Should succeed after rolling update completes",
+			strategy:    "RollingUpdate",
+			do: func(daemonsets chan watch.Event, pods chan logging.Messages, timeout chan time.Time) {
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 2, 1, 3, 3, 3))
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 2, 2, 3, 1, 2))
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 2, 2, 3, 3, 3))
+
+				// Timeout. Success.
+				timeout <- time.Now()
+			},
+		},
+		{
+			description: "[Generation 2] Should succeed with OnDelete once new spec is observed",
+			strategy:    "OnDelete",
+			do: func(daemonsets chan watch.Event, pods chan logging.Messages, timeout chan time.Time) {
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "OnDelete", 2, 1, 3, 3, 3))
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "OnDelete", 2, 2, 3, 0, 3))
+
+				// Timeout. Success.
+				timeout <- time.Now()
+			},
+		},
+		{
+			description: "[Generation 1] Should succeed if DaemonSet matches no Nodes",
+			strategy:    "RollingUpdate",
+			do: func(daemonsets chan watch.Event, pods chan logging.Messages, timeout chan time.Time) {
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 1, 1, 0, 0, 0))
+
+				// Timeout. Success.
+				timeout <- time.Now()
+			},
+		},
+		{
+			description: "[Generation 1] Should fail if spec is never observed",
+			strategy:    "RollingUpdate",
+			do: func(daemonsets chan watch.Event, pods chan logging.Messages, timeout chan time.Time) {
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 1, 0, 0, 0, 0))
+
+				// Timeout. Failure.
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{
+				object: daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 1, 0, 0, 0, 0),
+				subErrors: []string{
+					"DaemonSet controller has not observed generation 1 (observed generation: 0)",
+				}},
+		},
+		{
+			description: "[Generation 2] Should fail and report Pod errors if rollout stalls",
+			strategy:    "RollingUpdate",
+			do: func(daemonsets chan watch.Event, pods chan logging.Messages, timeout chan time.Time) {
+				daemonsets <- watchAddedEvent(
+					daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 2, 2, 3, 1, 2))
+				pods <- logging.Messages{podError}
+
+				// Timeout. Failure.
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{
+				object: daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 2, 2, 3, 1, 2),
+				subErrors: []string{
+					"1 out of 3 Pods were updated to the current template",
+					"2 out of 3 Pods succeeded readiness checks",
+					podError.S,
+				}},
+		},
+	}
+
+	for _, test := range tests {
+		awaiter := makeDaemonSetInitAwaiter(
+			updateAwaitConfig{
+				createAwaitConfig: mockAwaitConfig(daemonsetInput(inputNamespace, inputName, test.strategy)),
+			})
+		daemonsets := make(chan watch.Event)
+		pods := make(chan logging.Messages)
+
+		timeout := make(chan time.Time)
+		go test.do(daemonsets, pods, timeout)
+
+		err := awaiter.await(&chanWatcher{results: daemonsets}, pods, timeout)
+		assert.Equal(t, test.expectedError, err, test.description)
+	}
+}
+
+func Test_Apps_DaemonSetRead(t *testing.T) {
+	const (
+		inputNamespace = "default"
+		inputName      = "foo"
+	)
+	tests := []struct {
+		description       string
+		daemonset         *unstructured.Unstructured
+		expectedSubErrors []string
+	}{
+		{
+			description: "Read should fail if DaemonSet status empty",
+			daemonset:   daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 1, 0, 0, 0, 0),
+			expectedSubErrors: []string{
+				"DaemonSet controller has not observed generation 1 (observed generation: 0)",
+			},
+		},
+		{
+			description: "Read should fail if DaemonSet is rolling out",
+			daemonset:   daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 1, 1, 3, 3, 1),
+			expectedSubErrors: []string{
+				"1 out of 3 Pods succeeded readiness checks",
+			},
+		},
+		{
+			description: "Read should succeed if DaemonSet is ready",
+			daemonset:   daemonsetStatus(inputNamespace, inputName, "RollingUpdate", 1, 1, 3, 3, 3),
+		},
+	}
+
+	for _, test := range tests {
+		awaiter := makeDaemonSetInitAwaiter(
+			updateAwaitConfig{
+				createAwaitConfig: mockAwaitConfig(daemonsetInput(inputNamespace, inputName, "RollingUpdate")),
+			})
+		err := awaiter.read(test.daemonset, nil)
+		if test.expectedSubErrors != nil {
+			assert.Equal(t, test.expectedSubErrors, err.(*initializationError).SubErrors(), test.description)
+		} else {
+			assert.Nil(t, err, test.description)
+		}
+	}
+}
+
+// --------------------------------------------------------------------------
+
+// DaemonSet objects.
+
+// --------------------------------------------------------------------------
+
+// daemonsetInput is the user-provided declaration of a DaemonSet.
+func daemonsetInput(namespace, name, strategy string) *unstructured.Unstructured {
+	obj, err := decodeUnstructured(fmt.Sprintf(`{
+    "kind": "DaemonSet",
+    "apiVersion": "apps/v1",
+    "metadata": {
+        "namespace": "%s",
+        "name": "%s"
+    },
+    "spec": {
+        "selector": {
+            "matchLabels": {
+                "app": "foo"
+            }
+        },
+        "updateStrategy": {
+            "type": "%s"
+        },
+        "template": {
+            "metadata": {
+                "labels": {
+                    "app": "foo"
+                }
+            },
+            "spec": {
+                "containers": [
+                    {
+                        "name": "nginx",
+                        "image": "nginx"
+                    }
+                ]
+            }
+        }
+    }
+}`, namespace, name, strategy))
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// daemonsetStatus is a DaemonSet object as reported by the API server with the given generation and
+// status counters.
+func daemonsetStatus(
+	namespace, name, strategy string, generation, observedGeneration, desired, updated, available int,
+) *unstructured.Unstructured {
+	obj, err := decodeUnstructured(fmt.Sprintf(`{
+    "kind": "DaemonSet",
+    "apiVersion": "apps/v1",
+    "metadata": {
+        "namespace": "%s",
+        "name": "%s",
+        "generation": %d
+    },
+    "spec": {
+        "selector": {
+            "matchLabels": {
+                "app": "foo"
+            }
+        },
+        "updateStrategy": {
+            "type": "%s"
+        },
+        "template": {
+            "metadata": {
+                "labels": {
+                    "app": "foo"
+                }
+            },
+            "spec": {
+                "containers": [
+                    {
+                        "name": "nginx",
+                        "image": "nginx"
+                    }
+                ]
+            }
+        }
+    },
+    "status": {
+        "observedGeneration": %d,
+        "desiredNumberScheduled": %d,
+        "currentNumberScheduled": %d,
+        "updatedNumberScheduled": %d,
+        "numberAvailable": %d
+    }
+}`, namespace, name, generation, strategy, observedGeneration, desired, desired, updated, available))
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+func Test_Apps_DaemonSet_Replay(t *testing.T) {
+	awaiter := makeDaemonSetInitAwaiter(
+		updateAwaitConfig{
+			createAwaitConfig: mockAwaitConfig(daemonsetInput("default", "foo", "RollingUpdate")),
+		})
+
+	r := newReplay("DaemonSet")
+	err := replayEvents(r, "daemonset/foo-create-20200514T180211.000Z.json", func() error {
+		return awaiter.await(r.watcher("DaemonSet"), nil, r.timeout)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"[1/3] Waiting for DaemonSet controller to observe the new spec of \"foo\"",
+		"[2/3] Waiting for DaemonSet \"foo\" to update Pods (3/3 Pods updated)",
+		"[3/3] Waiting for DaemonSet \"foo\" Pods to become available (1/3 Pods available)",
+		"[3/3] Waiting for DaemonSet \"foo\" Pods to become available (3/3 Pods available)",
+		"DaemonSet initialization complete",
+	}, statusMessages(awaiter.config.logger))
+}
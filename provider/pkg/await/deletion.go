@@ -0,0 +1,394 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package await
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
+	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ------------------------------------------------------------------------------------------------
+
+// Generic await logic for resource deletion.
+//
+// Resource types without specialized deletion logic are considered deleted when the API server
+// reports that the object is gone. Deletion is frequently blocked by finalizers (e.g., the cloud
+// load balancer finalizer on a Service, `kubernetes.io/pvc-protection` on a PersistentVolumeClaim,
+// or an operator's finalizer on a custom resource), so while waiting, the awaiter reports which
+// finalizers are still pending. For PersistentVolumeClaims, it also reports which Pods still mount
+// the claim, since the claim cannot be removed until they are gone.
+//
+// Users can opt in to stripping named finalizers once deletion has been blocked for a grace period
+// by setting the `pulumi.com/removeFinalizers` annotation (e.g., to "example.com/cleanup", or to
+// "*" to strip every finalizer), and optionally `pulumi.com/removeFinalizersAfterSeconds`. This
+// skips the cleanup the finalizer guards, so it is intended for development stacks.
+//
+// The event loop depends on the following channels:
+//
+//   1. The object channel, to which the Kubernetes API server will push every change to the object.
+//   2. A timeout channel, which fires after some minutes.
+//   3. A grace period channel, which fires when named finalizers should be stripped.
+//   4. A period channel, which is used to periodically check which Pods mount a claim.
+//   5. A cancellation channel, with which the user can signal cancellation (e.g., using SIGINT).
+//
+// Resource types with specialized deletion logic (e.g., Deployments, which wait for their Pods to
+// be removed) still report pending finalizers and strip the opted-in ones: the generic awaiter
+// monitors the object while the specialized logic runs.
+
+// ------------------------------------------------------------------------------------------------
+
+const (
+	pvcPodCheckPeriod = 10 * time.Second
+
+	allFinalizers = "*"
+)
+
+type genericDeletionAwaiter struct {
+	config     deleteAwaitConfig
+	object     *unstructured.Unstructured
+	deleted    bool
+	finalizers []string
+	mountedBy  []string
+
+	// removeFinalizers lists the finalizers that the user has opted in to stripping.
+	removeFinalizers []string
+	gracePeriod      time.Duration
+}
+
+func makeGenericDeletionAwaiter(c deleteAwaitConfig) *genericDeletionAwaiter {
+	removeFinalizers, gracePeriod := metadata.RemoveFinalizers(c.currentInputs)
+	return &genericDeletionAwaiter{
+		config:           c,
+		object:           c.currentInputs,
+		removeFinalizers: removeFinalizers,
+		gracePeriod:      gracePeriod,
+	}
+}
+
+// Await waits for the object to be deleted. The watch must be established before the delete
+// request is sent, so that the `Deleted` event is not missed.
+func (da *genericDeletionAwaiter) Await(objWatcher watch.Interface) error {
+	defer objWatcher.Stop()
+
+	gracePeriod := da.finalizerGracePeriod()
+
+	var period <-chan time.Time
+	if da.isPersistentVolumeClaim() {
+		ticker := time.NewTicker(pvcPodCheckPeriod)
+		defer ticker.Stop()
+		period = ticker.C
+		da.checkMountingPods()
+	}
+
+	timeout := metadata.TimeoutDuration(da.config.timeout, da.config.defaultTimeouts, da.config.currentInputs, 300)
+	err := da.await(objWatcher, time.After(timeout), gracePeriod, period)
+	if err == nil {
+		_ = clearStatus(da.config.ctx, da.config.host, da.config.urn)
+	}
+	return err
+}
+
+// await is a helper companion to `Await` designed to make it easy to test this module.
+func (da *genericDeletionAwaiter) await(
+	objWatcher watch.Interface, timeout, gracePeriod, period <-chan time.Time,
+) error {
+	for {
+		if da.deleted {
+			return nil
+		}
+
+		// Else, wait for updates.
+		select {
+		case <-da.config.ctx.Done():
+			if da.checkIfDeleted() {
+				continue
+			}
+			return &cancellationError{
+				object:    da.object,
+				subErrors: da.errorMessages(),
+			}
+		case <-timeout:
+			if da.checkIfDeleted() {
+				continue
+			}
+			return &timeoutError{
+				object:    da.object,
+				subErrors: da.errorMessages(),
+			}
+		case <-gracePeriod:
+			// Refresh the object, so that only the finalizers that are still pending are stripped.
+			if !da.checkIfDeleted() {
+				da.stripFinalizers()
+			}
+		case <-period:
+			da.checkMountingPods()
+		case event, ok := <-objWatcher.ResultChan():
+			if !ok {
+				// The watch timed out on the server side.
+				if da.checkIfDeleted() {
+					continue
+				}
+				return &timeoutError{
+					object:    da.object,
+					subErrors: da.errorMessages(),
+				}
+			}
+			if event.Type == watch.Error {
+				if da.checkIfDeleted() {
+					continue
+				}
+				return &initializationError{
+					object:    da.object,
+					subErrors: []string{errors.FromObject(event.Object).Error()},
+				}
+			}
+			da.processObjectEvent(event)
+		}
+	}
+}
+
+// awaitDeletionWithFinalizers waits for the object to be deleted with the specialized deletion logic of its resource
+// type, while the generic awaiter reports the finalizers that block the deletion, and strips the finalizers that the
+// user has opted in to stripping. The watch must be established before the delete request is sent.
+func awaitDeletionWithFinalizers(
+	c deleteAwaitConfig, objWatcher watch.Interface, awaitDeletion func(deleteAwaitConfig) error,
+) error {
+	da := makeGenericDeletionAwaiter(c)
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		da.monitor(objWatcher, stop, da.finalizerGracePeriod())
+	}()
+
+	err := awaitDeletion(c)
+	close(stop)
+	<-stopped
+
+	if err != nil && !da.deleted && len(da.finalizers) > 0 {
+		c.logger.LogMessage(logging.WarningMessage(fmt.Sprintf("Deletion of %q is blocked by finalizers: %s",
+			c.currentInputs.GetName(), strings.Join(da.finalizers, ", "))))
+	}
+	return err
+}
+
+// monitor processes the events of the object, reporting pending finalizers and stripping the opted-in ones once the
+// grace period has passed, until the object is deleted or stop is closed.
+func (da *genericDeletionAwaiter) monitor(
+	objWatcher watch.Interface, stop <-chan struct{}, gracePeriod <-chan time.Time,
+) {
+	for !da.deleted {
+		select {
+		case <-stop:
+			return
+		case <-gracePeriod:
+			if !da.checkIfDeleted() {
+				da.stripFinalizers()
+			}
+		case event, ok := <-objWatcher.ResultChan():
+			if !ok {
+				return
+			}
+			if event.Type != watch.Error {
+				da.processObjectEvent(event)
+			}
+		}
+	}
+}
+
+// finalizerGracePeriod returns a channel that fires when the opted-in finalizers should be stripped, or nil if the
+// user has not opted in to stripping finalizers.
+func (da *genericDeletionAwaiter) finalizerGracePeriod() <-chan time.Time {
+	if len(da.removeFinalizers) == 0 {
+		return nil
+	}
+	return time.After(da.gracePeriod)
+}
+
+func (da *genericDeletionAwaiter) processObjectEvent(event watch.Event) {
+	obj, isUnstructured := event.Object.(*unstructured.Unstructured)
+	if !isUnstructured {
+		logger.V(3).Infof("Watch received unknown object type %q", reflect.TypeOf(event.Object))
+		return
+	}
+
+	// Do nothing if this is not the object we're waiting for.
+	if obj.GetName() != da.config.currentInputs.GetName() {
+		return
+	}
+
+	if event.Type == watch.Deleted {
+		da.deleted = true
+		return
+	}
+
+	da.object = obj
+	da.finalizers = obj.GetFinalizers()
+
+	// The object may not have observed the delete request yet.
+	if obj.GetDeletionTimestamp() == nil {
+		return
+	}
+
+	if len(da.finalizers) > 0 {
+		da.config.logStatus(diag.Info, fmt.Sprintf("Waiting for finalizers to be removed: %s",
+			strings.Join(da.finalizers, ", ")))
+	}
+	if da.isPersistentVolumeClaim() && len(da.mountedBy) > 0 {
+		da.config.logStatus(diag.Info, da.mountedByMessage())
+	}
+}
+
+// checkIfDeleted fetches the latest version of the object, and returns true if it no longer exists.
+func (da *genericDeletionAwaiter) checkIfDeleted() bool {
+	if da.config.clientForResource == nil {
+		return false
+	}
+	deleted, obj := checkIfResourceDeleted(da.config.currentInputs.GetName(), da.config.clientForResource)
+	if deleted {
+		da.deleted = true
+	} else if obj != nil {
+		da.object = obj
+		da.finalizers = obj.GetFinalizers()
+	}
+	return deleted
+}
+
+// checkMountingPods records which Pods in the claim's namespace still mount the claim.
+func (da *genericDeletionAwaiter) checkMountingPods() {
+	podClient, err := da.config.clientSet.ResourceClient(
+		schema.GroupVersionKind{Version: "v1", Kind: string(kinds.Pod)}, da.config.currentInputs.GetNamespace())
+	if err != nil {
+		logger.V(3).Infof("Could not make client to list Pods: %v", err)
+		return
+	}
+	pods, err := podClient.List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.V(3).Infof("Could not list Pods: %v", err)
+		return
+	}
+
+	da.mountedBy = podsMountingClaim(pods.Items, da.config.currentInputs.GetName())
+	if len(da.mountedBy) > 0 {
+		da.config.logStatus(diag.Info, da.mountedByMessage())
+	}
+}
+
+// stripFinalizers removes the finalizers that the user has opted in to stripping from the live
+// object.
+func (da *genericDeletionAwaiter) stripFinalizers() {
+	if da.config.clientForResource == nil {
+		return
+	}
+
+	current := da.object.GetFinalizers()
+	var remaining, removed []string
+	for _, finalizer := range current {
+		if da.shouldRemoveFinalizer(finalizer) {
+			removed = append(removed, finalizer)
+		} else {
+			remaining = append(remaining, finalizer)
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+	if remaining == nil {
+		remaining = []string{}
+	}
+
+	// Guard the replacement with a test, so that finalizers added concurrently are not dropped.
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/finalizers", "value": current},
+		{"op": "replace", "path": "/metadata/finalizers", "value": remaining},
+	})
+	if err != nil {
+		return
+	}
+
+	da.config.logStatus(diag.Warning, fmt.Sprintf(
+		"Deletion still blocked after %s; removing finalizers as requested by the %s annotation: %s",
+		da.gracePeriod, metadata.AnnotationRemoveFinalizers, strings.Join(removed, ", ")))
+	_, err = da.config.clientForResource.Patch(
+		context.TODO(), da.object.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil && !is404(err) {
+		da.config.logStatus(diag.Warning, fmt.Sprintf("Failed to remove finalizers: %v", err))
+	}
+}
+
+func (da *genericDeletionAwaiter) shouldRemoveFinalizer(finalizer string) bool {
+	for _, f := range da.removeFinalizers {
+		if f == allFinalizers || f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func (da *genericDeletionAwaiter) isPersistentVolumeClaim() bool {
+	return da.config.currentInputs.GetAPIVersion() == "v1" &&
+		da.config.currentInputs.GetKind() == string(kinds.PersistentVolumeClaim)
+}
+
+func (da *genericDeletionAwaiter) mountedByMessage() string {
+	return fmt.Sprintf("PersistentVolumeClaim is still mounted by Pods: %s", strings.Join(da.mountedBy, ", "))
+}
+
+func (da *genericDeletionAwaiter) errorMessages() []string {
+	messages := []string{fmt.Sprintf("Resource %q was not deleted", da.config.currentInputs.GetName())}
+	if len(da.finalizers) > 0 {
+		messages = append(messages, fmt.Sprintf("Deletion is blocked by finalizers: %s",
+			strings.Join(da.finalizers, ", ")))
+	}
+	if len(da.mountedBy) > 0 {
+		messages = append(messages, da.mountedByMessage())
+	}
+	return messages
+}
+
+// podsMountingClaim returns the names of the Pods that mount the named PersistentVolumeClaim.
+func podsMountingClaim(pods []unstructured.Unstructured, claimName string) []string {
+	var names []string
+	for _, pod := range pods {
+		volumes, _, _ := unstructured.NestedSlice(pod.Object, "spec", "volumes")
+		for _, volume := range volumes {
+			v, ok := volume.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(v, "persistentVolumeClaim", "claimName")
+			if name == claimName {
+				names = append(names, pod.GetName())
+				break
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,223 @@
+// nolint: goconst
+package await
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func Test_GenericDeletion(t *testing.T) {
+	tests := []struct {
+		description   string
+		do            func(objects chan watch.Event, timeout chan time.Time)
+		expectedError error
+	}{
+		{
+			description: "Should succeed when object is deleted",
+			do: func(objects chan watch.Event, timeout chan time.Time) {
+				objects <- watchAddedEvent(serviceDeleting("service.kubernetes.io/load-balancer-cleanup"))
+				objects <- watch.Event{Type: watch.Deleted, Object: serviceDeleting()}
+			},
+		},
+		{
+			description: "Should ignore other objects",
+			do: func(objects chan watch.Event, timeout chan time.Time) {
+				other := serviceDeleting()
+				other.SetName("bar")
+				objects <- watch.Event{Type: watch.Deleted, Object: other}
+
+				// Timeout. Failure.
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{
+				object:    serviceDeleting(),
+				subErrors: []string{`Resource "foo" was not deleted`},
+			},
+		},
+		{
+			description: "Should report pending finalizers if timeout occurs",
+			do: func(objects chan watch.Event, timeout chan time.Time) {
+				objects <- watchAddedEvent(serviceDeleting("service.kubernetes.io/load-balancer-cleanup"))
+
+				// Timeout. Failure.
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{
+				object: serviceDeleting("service.kubernetes.io/load-balancer-cleanup"),
+				subErrors: []string{
+					`Resource "foo" was not deleted`,
+					"Deletion is blocked by finalizers: service.kubernetes.io/load-balancer-cleanup",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		awaiter := makeGenericDeletionAwaiter(
+			deleteAwaitConfig{createAwaitConfig: mockAwaitConfig(serviceDeleting())})
+		objects := make(chan watch.Event)
+
+		timeout := make(chan time.Time)
+		go test.do(objects, timeout)
+
+		err := awaiter.await(&chanWatcher{results: objects}, timeout, nil, nil)
+		assert.Equal(t, test.expectedError, err, test.description)
+	}
+}
+
+func Test_DeletionWithFinalizers(t *testing.T) {
+	deployment := deploymentDeleting("example.com/cleanup")
+	deployment.SetAnnotations(map[string]string{
+		metadata.AnnotationRemoveFinalizers:             "example.com/cleanup",
+		metadata.AnnotationRemoveFinalizersAfterSeconds: "0",
+	})
+	client := &finalizerResourceInterface{obj: deployment.DeepCopy()}
+	config := deleteAwaitConfig{createAwaitConfig: mockAwaitConfig(deployment), clientForResource: client}
+
+	objects := make(chan watch.Event)
+	go func() {
+		objects <- watch.Event{Type: watch.Modified, Object: deployment.DeepCopy()}
+	}()
+
+	// The Deployment is only deleted once its finalizer is stripped, while its specialized deletion logic waits.
+	err := awaitDeletionWithFinalizers(config, &chanWatcher{results: objects}, untilAppsDeploymentDeleted)
+	assert.NoError(t, err)
+	assert.True(t, client.patched)
+}
+
+func Test_DeletionWithFinalizers_Error(t *testing.T) {
+	deployment := deploymentDeleting("example.com/cleanup")
+	client := &finalizerResourceInterface{obj: deployment.DeepCopy()}
+	config := deleteAwaitConfig{createAwaitConfig: mockAwaitConfig(deployment), clientForResource: client}
+
+	objects := make(chan watch.Event)
+	awaitErr := fmt.Errorf("deployment %q still exists", deployment.GetName())
+	err := awaitDeletionWithFinalizers(config, &chanWatcher{results: objects}, func(deleteAwaitConfig) error {
+		objects <- watch.Event{Type: watch.Modified, Object: deployment.DeepCopy()}
+		return awaitErr
+	})
+
+	// Finalizers are not stripped unless the user opts in, and the error of the specialized logic is returned as-is.
+	assert.Equal(t, awaitErr, err)
+	assert.False(t, client.patched)
+}
+
+func Test_podsMountingClaim(t *testing.T) {
+	pods := []unstructured.Unstructured{
+		*podWithClaim("mounts-claim", "data"),
+		*podWithClaim("mounts-other-claim", "logs"),
+		*podWithClaim("no-claim", ""),
+	}
+
+	assert.Equal(t, []string{"mounts-claim"}, podsMountingClaim(pods, "data"))
+	assert.Nil(t, podsMountingClaim(pods, "cache"))
+}
+
+// --------------------------------------------------------------------------
+
+// Deletion objects.
+
+// --------------------------------------------------------------------------
+
+func serviceDeleting(finalizers ...string) *unstructured.Unstructured {
+	obj, err := decodeUnstructured(`{
+    "apiVersion": "v1",
+    "kind": "Service",
+    "metadata": {
+        "name": "foo",
+        "namespace": "default",
+        "deletionTimestamp": "2020-01-01T00:00:00Z"
+    },
+    "spec": {
+        "type": "LoadBalancer"
+    }
+}`)
+	if err != nil {
+		panic(err)
+	}
+	obj.SetFinalizers(finalizers)
+	return obj
+}
+
+func deploymentDeleting(finalizers ...string) *unstructured.Unstructured {
+	obj, err := decodeUnstructured(`{
+    "apiVersion": "apps/v1",
+    "kind": "Deployment",
+    "metadata": {
+        "name": "foo",
+        "namespace": "default",
+        "deletionTimestamp": "2020-01-01T00:00:00Z"
+    },
+    "spec": {
+        "replicas": 1
+    }
+}`)
+	if err != nil {
+		panic(err)
+	}
+	obj.SetFinalizers(finalizers)
+	return obj
+}
+
+// finalizerResourceInterface serves an object that is deleted once a patch strips its finalizers.
+type finalizerResourceInterface struct {
+	mockResourceInterface
+	mu      sync.Mutex
+	obj     *unstructured.Unstructured
+	patched bool
+}
+
+func (f *finalizerResourceInterface) Get(
+	ctx context.Context, name string, options metav1.GetOptions, subresources ...string,
+) (*unstructured.Unstructured, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.obj == nil {
+		return nil, errors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, name)
+	}
+	return f.obj.DeepCopy(), nil
+}
+
+func (f *finalizerResourceInterface) Patch(
+	ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string,
+) (*unstructured.Unstructured, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.patched = true
+	f.obj = nil
+	return nil, nil
+}
+
+func podWithClaim(name, claimName string) *unstructured.Unstructured {
+	volumes := "[]"
+	if claimName != "" {
+		volumes = fmt.Sprintf(`[{"name": "data", "persistentVolumeClaim": {"claimName": %q}}]`, claimName)
+	}
+	obj, err := decodeUnstructured(fmt.Sprintf(`{
+    "apiVersion": "v1",
+    "kind": "Pod",
+    "metadata": {
+        "name": %q,
+        "namespace": "default"
+    },
+    "spec": {
+        "volumes": %s
+    }
+}`, name, volumes))
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
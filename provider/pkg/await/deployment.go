@@ -3,8 +3,8 @@ package await
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
-	"strings"
 	"time"
 
 	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
@@ -18,6 +18,7 @@ import (
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/openapi"
 	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
 	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -60,6 +61,18 @@ import (
 //   3. `.status.conditions` has a status with `type` equal to `Available`, a `status` equal to
 //      `True`. If the Deployment is not available, we should fail the Deployment immediately.
 //
+// There are two exceptions to these conditions:
+//
+//   * A paused Deployment (`.spec.paused` is `true`) does not roll out changes to its Pod template
+//     until it is resumed, so it is considered ready as soon as the Deployment controller has
+//     observed the current spec (`.status.observedGeneration`).
+//   * The Deployment controller reports the `Progressing` condition only for Deployments with a
+//     progress deadline. If the condition is missing, and `.spec.progressDeadlineSeconds` is unset
+//     or set to the maximum value, condition 2 is replaced by the replica counts of the Deployment,
+//     following the semantics of `kubectl rollout status`. Because the controller will never report
+//     that such a rollout has stalled, the awaiter warns if the replica counts do not change for some
+//     minutes.
+//
 // The core event loop of this awaiter is actually individually straightforward, except for the
 // fact that it must aggregate statuses for all Pods in the new ReplicaSet. The event loop depends
 // on the following channels:
@@ -91,6 +104,10 @@ const (
 	revision                     = "deployment.kubernetes.io/revision"
 	DefaultDeploymentTimeoutMins = 10
 	extensionsv1b1ApiVersion     = "extensions/v1beta1"
+
+	// deploymentStallTimeout is how long a Deployment without a progress deadline may go without
+	// changing its replica counts before the awaiter reports that the rollout has stalled.
+	deploymentStallTimeout = 5 * time.Minute
 )
 
 type deploymentInitAwaiter struct {
@@ -100,6 +117,22 @@ type deploymentInitAwaiter struct {
 	pvcsAvailable          bool
 	updatedReplicaSetReady bool
 	replicaSetGeneration   string
+	readyReplicas          int64
+	desiredReplicas        int64
+	paused                 bool
+
+	// checker holds the readiness Conditions of the Deployment, evaluated against the last known version
+	// of the Deployment and the state of its current ReplicaSet and PersistentVolumeClaims.
+	checker *states.StateChecker
+
+	// rolloutChecker tracks the replica counts of Deployments without a progress deadline, which do
+	// not report the `Progressing` condition. rolloutProgress summarizes the counts, and is empty if the
+	// Deployment has a progress deadline.
+	rolloutChecker  *states.StateChecker
+	rolloutProgress string
+	lastProgress    time.Time
+	stalled         bool
+	now             func() time.Time
 
 	deploymentErrors map[string]string
 
@@ -118,6 +151,10 @@ func makeDeploymentInitAwaiter(c updateAwaitConfig) *deploymentInitAwaiter {
 		// NOTE: Generation 0 is invalid, so this is a good sentinel value.
 		replicaSetGeneration: "0",
 
+		checker:        states.NewDeploymentChecker(),
+		rolloutChecker: states.NewDeploymentReplicasChecker(),
+		now:            time.Now,
+
 		deploymentErrors: map[string]string{},
 
 		deployment:  c.currentOutputs,
@@ -145,13 +182,11 @@ func (dia *deploymentInitAwaiter) Await() error {
 	//      corresponding ReplicaSet), and therefore there is no rollout to mark as "Progressing".
 	//
 
-	deploymentClient, replicaSetClient, podClient, pvcClient, err := dia.makeClients()
-	if err != nil {
-		return err
-	}
+	namespace := dia.config.currentInputs.GetNamespace()
 
 	// Create Deployment watcher.
-	deploymentWatcher, err := deploymentClient.Watch(context.TODO(), metav1.ListOptions{})
+	deploymentWatcher, err := dia.config.watchKind(kinds.Deployment, namespace,
+		clients.ObjectNamed(dia.config.currentInputs.GetName()))
 	if err != nil {
 		return errors.Wrapf(err, "could not set up watch for Deployment object %q",
 			dia.config.currentInputs.GetName())
@@ -159,7 +194,7 @@ func (dia *deploymentInitAwaiter) Await() error {
 	defer deploymentWatcher.Stop()
 
 	// Create ReplicaSet watcher.
-	replicaSetWatcher, err := replicaSetClient.Watch(context.TODO(), metav1.ListOptions{})
+	replicaSetWatcher, err := dia.config.watchKind(kinds.ReplicaSet, namespace, nil)
 	if err != nil {
 		return errors.Wrapf(err,
 			"Could not create watcher for ReplicaSet objects associated with Deployment %q",
@@ -168,7 +203,7 @@ func (dia *deploymentInitAwaiter) Await() error {
 	defer replicaSetWatcher.Stop()
 
 	// Create Pod watcher.
-	podWatcher, err := podClient.Watch(context.TODO(), metav1.ListOptions{})
+	podWatcher, err := dia.config.watchKind(kinds.Pod, namespace, nil)
 	if err != nil {
 		return errors.Wrapf(err,
 			"Could not create watcher for Pods objects associated with Deployment %q",
@@ -177,7 +212,7 @@ func (dia *deploymentInitAwaiter) Await() error {
 	defer podWatcher.Stop()
 
 	// Create PersistentVolumeClaims watcher.
-	pvcWatcher, err := pvcClient.Watch(context.TODO(), metav1.ListOptions{})
+	pvcWatcher, err := dia.config.watchKind(kinds.PersistentVolumeClaim, namespace, nil)
 	if err != nil {
 		return errors.Wrapf(err,
 			"Could not create watcher for PersistentVolumeClaims objects associated with Deployment %q",
@@ -188,7 +223,8 @@ func (dia *deploymentInitAwaiter) Await() error {
 	aggregateErrorTicker := time.NewTicker(10 * time.Second)
 	defer aggregateErrorTicker.Stop()
 
-	timeout := metadata.TimeoutDuration(dia.config.timeout, dia.config.currentInputs, DefaultDeploymentTimeoutMins*60)
+	timeout := metadata.TimeoutDuration(dia.config.timeout, dia.config.defaultTimeouts,
+		dia.config.currentInputs, DefaultDeploymentTimeoutMins*60)
 	return dia.await(
 		deploymentWatcher, replicaSetWatcher, podWatcher, pvcWatcher, time.After(timeout), aggregateErrorTicker.C)
 }
@@ -287,52 +323,26 @@ func (dia *deploymentInitAwaiter) await(
 	deploymentWatcher, replicaSetWatcher, podWatcher, pvcWatcher watch.Interface,
 	timeout, aggregateErrorTicker <-chan time.Time,
 ) error {
-	dia.config.logStatus(diag.Info, "[1/2] Waiting for app ReplicaSet be marked available")
-
-	for {
-		if dia.checkAndLogStatus() {
-			return nil
-		}
-
-		// Else, wait for updates.
-		select {
-		case <-dia.config.ctx.Done():
-			return &cancellationError{
-				object:    dia.deployment,
-				subErrors: dia.errorMessages(),
-			}
-		case <-timeout:
-			return &timeoutError{
-				object:    dia.deployment,
-				subErrors: dia.errorMessages(),
-			}
-		case <-aggregateErrorTicker:
+	loop := awaitLoop{
+		ctx:           dia.config.ctx,
+		ready:         dia.checkAndLogStatus,
+		object:        func() *unstructured.Unstructured { return dia.deployment },
+		errorMessages: dia.errorMessages,
+	}
+	return loop.run(timeout,
+		ticks(aggregateErrorTicker, func() {
 			messages := dia.aggregatePodErrors()
 			for _, message := range messages {
 				dia.config.logMessage(message)
 			}
-		case event := <-deploymentWatcher.ResultChan():
-			dia.processDeploymentEvent(event)
-		case event := <-replicaSetWatcher.ResultChan():
-			dia.processReplicaSetEvent(event)
-		case event := <-podWatcher.ResultChan():
-			dia.processPodEvent(event)
-		case event := <-pvcWatcher.ResultChan():
-			dia.processPersistentVolumeClaimsEvent(event)
-		}
-	}
+			dia.checkRolloutStalled()
+		}),
+		watchEvents(deploymentWatcher, dia.processDeploymentEvent),
+		watchEvents(replicaSetWatcher, dia.processReplicaSetEvent),
+		watchEvents(podWatcher, dia.processPodEvent),
+		watchEvents(pvcWatcher, dia.processPersistentVolumeClaimsEvent))
 }
 
-// Check whether we've succeeded, log the result as a status message to the provider. There are two
-// cases:
-//
-//   1. If the generation of the Deployment is > 1, we need to check that (1) the Deployment is
-//      marked as available, (2) the ReplicaSet we're trying to roll to is marked as Available,
-//      and (3) the Deployment has marked the new ReplicaSet as "ready".
-//   2. If it's the first generation of the Deployment, the object is simply created, rather than
-//      rolled out. This means there is no rollout to be marked as "progressing", so we need only
-//      check that the Deployment was created, and the corresponding ReplicaSet needs to be marked
-//      available.
 func (dia *deploymentInitAwaiter) isEveryPVCReady() bool {
 	if len(dia.pvcs) == 0 || (len(dia.pvcs) > 0 && dia.pvcsAvailable) {
 		return true
@@ -341,30 +351,43 @@ func (dia *deploymentInitAwaiter) isEveryPVCReady() bool {
 	return false
 }
 
+// checkAndLogStatus evaluates the Deployment readiness Conditions and logs the result as a status message
+// to the provider. Unless the Deployment is paused, it is ready when:
+//
+//   1. Its PersistentVolumeClaims are bound.
+//   2. It is marked as available.
+//   3. If the generation of its ReplicaSet is > 1, the ReplicaSet we're trying to roll to is marked as
+//      available. The first generation of the Deployment is simply created, rather than rolled out, so
+//      there is no rollout to be marked as "progressing".
+//   4. The ReplicaSet of the current generation has its desired number of ready Pods.
 func (dia *deploymentInitAwaiter) checkAndLogStatus() bool {
-	if dia.replicaSetGeneration == "1" {
-		if dia.deploymentAvailable && dia.updatedReplicaSetReady {
-			if !dia.isEveryPVCReady() {
-				return false
-			}
-
-			dia.config.logStatus(diag.Info,
-				fmt.Sprintf("%sDeployment initialization complete", cmdutil.EmojiOr("✅ ", "")))
-			return true
-		}
-	} else {
-		if dia.deploymentAvailable && dia.replicaSetAvailable && dia.updatedReplicaSetReady {
-			if !dia.isEveryPVCReady() {
-				return false
-			}
+	if dia.paused {
+		dia.config.logStatus(diag.Info,
+			fmt.Sprintf("%sDeployment is paused; changes to its Pod template will be rolled out when it is resumed",
+				cmdutil.EmojiOr("✅ ", "")))
+		return true
+	}
 
-			dia.config.logStatus(diag.Info,
-				fmt.Sprintf("%sDeployment initialization complete", cmdutil.EmojiOr("✅ ", "")))
-			return true
-		}
+	obj, err := clients.FromUnstructured(dia.deployment)
+	if err != nil {
+		logger.V(3).Infof("Failed to unmarshal Deployment %q: %v", dia.deployment.GetName(), err)
+		return false
 	}
 
-	return false
+	messages := dia.checker.Update(&states.DeploymentState{
+		Deployment:             obj.(*appsv1.Deployment),
+		Rollout:                dia.replicaSetGeneration != "1",
+		Available:              dia.deploymentAvailable,
+		NewReplicaSetAvailable: dia.replicaSetAvailable,
+		UpdatedReplicaSetReady: dia.updatedReplicaSetReady,
+		ReadyReplicas:          dia.readyReplicas,
+		DesiredReplicas:        dia.desiredReplicas,
+		UnboundClaims:          dia.getFailedPersistentValueClaims(),
+	})
+	for _, message := range messages {
+		dia.config.logMessage(message)
+	}
+	return dia.checker.Ready()
 }
 
 func (dia *deploymentInitAwaiter) processDeploymentEvent(event watch.Event) {
@@ -392,6 +415,13 @@ func (dia *deploymentInitAwaiter) processDeploymentEvent(event watch.Event) {
 
 	dia.deployment = deployment
 
+	// A paused Deployment does not roll out changes to its Pod template, so there is nothing to wait
+	// for once the Deployment controller has observed the current spec.
+	dia.paused = isDeploymentPaused(deployment) && isDeploymentObserved(deployment)
+	if dia.paused {
+		return
+	}
+
 	// extensions/v1beta1 does not include the "Progressing" status for rollouts.
 	// Note: We must use the annotated creation apiVersion rather than the API-reported apiVersion, because
 	// the Progressing status field will not be present if the Deployment was created with the `extensions/v1beta1` API,
@@ -430,6 +460,7 @@ func (dia *deploymentInitAwaiter) processDeploymentEvent(event watch.Event) {
 
 	// Success occurs when the ReplicaSet of the `replicaSetGeneration` is marked as available, and
 	// when the deployment is available.
+	hasProgressingCondition := false
 	for _, rawCondition := range conditions {
 		condition, isMap := rawCondition.(map[string]interface{})
 		if !isMap {
@@ -440,6 +471,7 @@ func (dia *deploymentInitAwaiter) processDeploymentEvent(event watch.Event) {
 			// Since we can't tell for sure from this version of the API, mark as available.
 			dia.replicaSetAvailable = true
 		} else if condition["type"] == "Progressing" {
+			hasProgressingCondition = true
 			isProgressing := condition["status"] == trueStatus
 			if !isProgressing {
 				rawReason, hasReason := condition["reason"]
@@ -480,10 +512,59 @@ func (dia *deploymentInitAwaiter) processDeploymentEvent(event watch.Event) {
 		}
 	}
 
+	// Deployments without a progress deadline do not report the "Progressing" condition, so fall back
+	// to the replica counts to decide whether the new ReplicaSet is available.
+	if extensionsV1Beta1API || hasProgressingCondition || hasProgressDeadline(deployment) {
+		dia.rolloutProgress = ""
+	} else {
+		dia.checkRolloutProgress(deployment)
+	}
+
 	dia.checkReplicaSetStatus()
 	dia.checkPersistentVolumeClaimStatus()
 }
 
+// checkRolloutProgress checks the replica counts of a Deployment without a progress deadline, and
+// records the time at which they last changed, so that stalled rollouts can be reported.
+func (dia *deploymentInitAwaiter) checkRolloutProgress(deployment *unstructured.Unstructured) {
+	obj, err := clients.FromUnstructured(deployment)
+	if err != nil {
+		logger.V(3).Infof("Failed to unmarshal Deployment event: %v", err)
+		return
+	}
+	d := obj.(*appsv1.Deployment)
+
+	dia.rolloutChecker.Update(d)
+	dia.replicaSetAvailable = dia.rolloutChecker.Ready()
+
+	progress := fmt.Sprintf("%d/%d/%d/%d/%d", d.Status.ObservedGeneration, d.Status.Replicas,
+		d.Status.UpdatedReplicas, d.Status.ReadyReplicas, d.Status.AvailableReplicas)
+	if progress != dia.rolloutProgress {
+		dia.rolloutProgress = progress
+		dia.lastProgress = dia.now()
+		dia.stalled = false
+	}
+}
+
+// checkRolloutStalled reports a rollout of a Deployment without a progress deadline as stalled if its
+// replica counts have not changed for `deploymentStallTimeout`.
+func (dia *deploymentInitAwaiter) checkRolloutStalled() {
+	if dia.rolloutProgress == "" || dia.replicaSetAvailable || dia.stalled {
+		return
+	}
+	if dia.now().Sub(dia.lastProgress) < deploymentStallTimeout {
+		return
+	}
+
+	dia.stalled = true
+	dia.config.logStatus(diag.Warning, dia.stalledMessage())
+}
+
+func (dia *deploymentInitAwaiter) stalledMessage() string {
+	return fmt.Sprintf("[RolloutStalled] Deployment has no progress deadline, and its rollout has not "+
+		"made progress in %d minutes", int(deploymentStallTimeout.Minutes()))
+}
+
 func (dia *deploymentInitAwaiter) processReplicaSetEvent(event watch.Event) {
 	rs, isUnstructured := event.Object.(*unstructured.Unstructured)
 	if !isUnstructured {
@@ -618,12 +699,7 @@ func (dia *deploymentInitAwaiter) checkReplicaSetStatus() {
 		}
 	}
 
-	if !dia.updatedReplicaSetReady {
-		dia.config.logStatus(
-			diag.Info,
-			fmt.Sprintf("[1/2] Waiting for app ReplicaSet be marked available (%d/%d Pods available)",
-				readyReplicas, specReplicas))
-	}
+	dia.readyReplicas, dia.desiredReplicas = readyReplicas, specReplicas
 
 	if dia.updatedReplicaSetReady && specReplicasExists && specReplicas == 0 {
 		dia.config.logStatus(
@@ -737,18 +813,8 @@ func (dia *deploymentInitAwaiter) processPersistentVolumeClaimsEvent(event watch
 }
 
 func (dia *deploymentInitAwaiter) aggregatePodErrors() logging.Messages {
-	rs, exists := dia.replicaSets[dia.replicaSetGeneration]
-	if !exists {
-		return nil
-	}
-
 	var messages logging.Messages
-	for _, unstructuredPod := range dia.pods {
-		// Filter down to only Pods owned by the active ReplicaSet.
-		if !isOwnedBy(unstructuredPod, rs) {
-			continue
-		}
-
+	for _, unstructuredPod := range dia.activePods() {
 		// Check the pod for errors.
 		checker := states.NewPodChecker()
 		pod, err := clients.PodFromUnstructured(unstructuredPod)
@@ -783,46 +849,60 @@ func (dia *deploymentInitAwaiter) errorMessages() []string {
 	for _, message := range dia.deploymentErrors {
 		messages = append(messages, message)
 	}
-
-	if dia.replicaSetGeneration == "1" {
-		if !dia.isEveryPVCReady() {
-			failed := dia.getFailedPersistentValueClaims()
-			msg := fmt.Sprintf("Failed to bind PersistentVolumeClaim(s): %q", strings.Join(failed, ","))
-			messages = append(messages, msg)
-		}
-		if !dia.deploymentAvailable {
-			messages = append(messages,
-				"Minimum number of live Pods was not attained")
-		} else if !dia.updatedReplicaSetReady {
-			messages = append(messages,
-				"Minimum number of Pods to consider the application live was not attained")
-		}
-	} else {
-		if !dia.isEveryPVCReady() {
-			failed := dia.getFailedPersistentValueClaims()
-			msg := fmt.Sprintf("Failed to bind PersistentVolumeClaim(s): %q", strings.Join(failed, ","))
-			messages = append(messages, msg)
-		}
-		if !dia.deploymentAvailable {
-			messages = append(messages,
-				"Minimum number of live Pods was not attained")
-		} else if !dia.replicaSetAvailable {
-			messages = append(messages,
-				"Minimum number of Pods to consider the application live was not attained")
-		} else if !dia.updatedReplicaSetReady {
-			messages = append(messages,
-				"Attempted to roll forward to new ReplicaSet, but minimum number of Pods did not become live")
-		}
+	if dia.stalled {
+		messages = append(messages, dia.stalledMessage())
+	}
+	if dia.rolloutProgress != "" {
+		messages = append(messages, dia.rolloutChecker.Failures()...)
 	}
 
+	messages = append(messages, dia.checker.Failures()...)
+
 	errorMessages := dia.aggregatePodErrors()
 	for _, message := range errorMessages {
 		messages = append(messages, message.S)
 	}
+	messages = append(messages, podDiagnostics(dia.config.createAwaitConfig, failingPods(dia.activePods()))...)
 
 	return messages
 }
 
+// isDeploymentPaused returns true if the rollout of the Deployment is paused.
+func isDeploymentPaused(deployment *unstructured.Unstructured) bool {
+	paused, _, _ := unstructured.NestedBool(deployment.Object, "spec", "paused")
+	return paused
+}
+
+// isDeploymentObserved returns true if the Deployment controller has observed the current spec.
+func isDeploymentObserved(deployment *unstructured.Unstructured) bool {
+	observedGeneration, _, _ := unstructured.NestedInt64(deployment.Object, "status", "observedGeneration")
+	return deployment.GetGeneration() != 0 && observedGeneration >= deployment.GetGeneration()
+}
+
+// hasProgressDeadline returns true if the Deployment controller reports the "Progressing" condition
+// for the Deployment, i.e., if `.spec.progressDeadlineSeconds` is set to a value other than the
+// maximum.
+func hasProgressDeadline(deployment *unstructured.Unstructured) bool {
+	deadline, found, _ := unstructured.NestedInt64(deployment.Object, "spec", "progressDeadlineSeconds")
+	return found && deadline != math.MaxInt32
+}
+
+// activePods returns the Pods owned by the active ReplicaSet.
+func (dia *deploymentInitAwaiter) activePods() []*unstructured.Unstructured {
+	rs, exists := dia.replicaSets[dia.replicaSetGeneration]
+	if !exists {
+		return nil
+	}
+
+	var pods []*unstructured.Unstructured
+	for _, pod := range dia.pods {
+		if isOwnedBy(pod, rs) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
 //nolint: nakedret
 func (dia *deploymentInitAwaiter) makeClients() (
 	deploymentClient, replicaSetClient, podClient, pvcClient dynamic.ResourceInterface, err error,
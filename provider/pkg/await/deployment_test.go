@@ -3,6 +3,7 @@ package await
 
 import (
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -760,6 +761,160 @@ func Test_Apps_Deployment_MultipleUpdates(t *testing.T) {
 	}
 }
 
+func Test_Apps_Deployment_Paused(t *testing.T) {
+	tests := []struct {
+		description   string
+		do            func(deployments, replicaSets chan watch.Event, timeout chan time.Time)
+		expectedError error
+	}{
+		{
+			description: "[Revision 2] Should succeed once the pause is observed",
+			do: func(deployments, replicaSets chan watch.Event, timeout chan time.Time) {
+				// User pauses the Deployment and updates its Pod template. The controller does not
+				// create a new ReplicaSet until the Deployment is resumed.
+				deployments <- watchAddedEvent(
+					deploymentPaused(inputNamespace, deploymentInputName, revision1, false))
+				deployments <- watchAddedEvent(
+					deploymentPaused(inputNamespace, deploymentInputName, revision1, true))
+
+				// Timeout. Success.
+				timeout <- time.Now()
+			},
+		},
+		{
+			description: "[Revision 2] Should fail if the pause is not observed",
+			do: func(deployments, replicaSets chan watch.Event, timeout chan time.Time) {
+				deployments <- watchAddedEvent(
+					deploymentPaused(inputNamespace, deploymentInputName, revision2, false))
+
+				// Timeout. Failure.
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{
+				object: deploymentPaused(inputNamespace, deploymentInputName, revision2, false),
+				subErrors: []string{
+					"Attempted to roll forward to new ReplicaSet, but minimum number of Pods did not become live"}},
+		},
+	}
+
+	for _, test := range tests {
+		awaiter := makeDeploymentInitAwaiter(
+			updateAwaitConfig{
+				createAwaitConfig: mockAwaitConfig(deploymentInput(inputNamespace, deploymentInputName)),
+			})
+		deployments := make(chan watch.Event)
+		replicaSets := make(chan watch.Event)
+
+		timeout := make(chan time.Time)
+		period := make(chan time.Time)
+		go test.do(deployments, replicaSets, timeout)
+
+		err := awaiter.await(&chanWatcher{results: deployments}, &chanWatcher{results: replicaSets},
+			&chanWatcher{}, &chanWatcher{}, timeout, period)
+		assert.Equal(t, test.expectedError, err, test.description)
+		if test.expectedError == nil {
+			messages := statusMessages(awaiter.config.logger)
+			assert.Equal(t,
+				"Deployment is paused; changes to its Pod template will be rolled out when it is resumed",
+				messages[len(messages)-1], test.description)
+		}
+	}
+}
+
+func Test_Apps_Deployment_WithoutProgressDeadline(t *testing.T) {
+	tests := []struct {
+		description string
+		do          func(deployments, replicaSets chan watch.Event, timeout, period chan time.Time,
+			advance func(time.Duration))
+		expectedError error
+	}{
+		{
+			description: "[Revision 2] Should succeed once every replica is updated and available",
+			do: func(deployments, replicaSets chan watch.Event, timeout, period chan time.Time,
+				advance func(time.Duration)) {
+				deployments <- watchAddedEvent(
+					deploymentWithoutProgressDeadline(inputNamespace, deploymentInputName, revision2, 0))
+				replicaSets <- watchAddedEvent(
+					availableReplicaSet(inputNamespace, replicaSetGeneratedName, deploymentInputName, revision2))
+				deployments <- watchAddedEvent(
+					deploymentWithoutProgressDeadline(inputNamespace, deploymentInputName, revision2, 1))
+
+				// Timeout. Success.
+				timeout <- time.Now()
+			},
+		},
+		{
+			description: "[Revision 2] Should fail if replicas are not available",
+			do: func(deployments, replicaSets chan watch.Event, timeout, period chan time.Time,
+				advance func(time.Duration)) {
+				deployments <- watchAddedEvent(
+					deploymentWithoutProgressDeadline(inputNamespace, deploymentInputName, revision2, 0))
+				replicaSets <- watchAddedEvent(
+					availableReplicaSet(inputNamespace, replicaSetGeneratedName, deploymentInputName, revision2))
+
+				// Not stalled yet.
+				advance(deploymentStallTimeout / 2)
+				period <- time.Now()
+
+				// Timeout. Failure.
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{
+				object: deploymentWithoutProgressDeadline(inputNamespace, deploymentInputName, revision2, 0),
+				subErrors: []string{
+					"0 out of 1 updated replicas are available",
+					"Minimum number of Pods to consider the application live was not attained"}},
+		},
+		{
+			description: "[Revision 2] Should report a stalled rollout",
+			do: func(deployments, replicaSets chan watch.Event, timeout, period chan time.Time,
+				advance func(time.Duration)) {
+				deployments <- watchAddedEvent(
+					deploymentWithoutProgressDeadline(inputNamespace, deploymentInputName, revision2, 0))
+				replicaSets <- watchAddedEvent(
+					availableReplicaSet(inputNamespace, replicaSetGeneratedName, deploymentInputName, revision2))
+
+				// The replica counts do not change.
+				advance(deploymentStallTimeout)
+				deployments <- watchAddedEvent(
+					deploymentWithoutProgressDeadline(inputNamespace, deploymentInputName, revision2, 0))
+				period <- time.Now()
+
+				// Timeout. Failure.
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{
+				object: deploymentWithoutProgressDeadline(inputNamespace, deploymentInputName, revision2, 0),
+				subErrors: []string{
+					"[RolloutStalled] Deployment has no progress deadline, and its rollout has not made " +
+						"progress in 5 minutes",
+					"0 out of 1 updated replicas are available",
+					"Minimum number of Pods to consider the application live was not attained"}},
+		},
+	}
+
+	for _, test := range tests {
+		awaiter := makeDeploymentInitAwaiter(
+			updateAwaitConfig{
+				createAwaitConfig: mockAwaitConfig(deploymentInput(inputNamespace, deploymentInputName)),
+			})
+		now := time.Now()
+		awaiter.now = func() time.Time { return now }
+		deployments := make(chan watch.Event)
+		replicaSets := make(chan watch.Event)
+
+		timeout := make(chan time.Time)
+		period := make(chan time.Time)
+		go test.do(deployments, replicaSets, timeout, period, func(d time.Duration) {
+			now = now.Add(d)
+		})
+
+		err := awaiter.await(&chanWatcher{results: deployments}, &chanWatcher{results: replicaSets},
+			&chanWatcher{}, &chanWatcher{}, timeout, period)
+		assert.Equal(t, test.expectedError, err, test.description)
+	}
+}
+
 func Test_Core_Deployment_Read(t *testing.T) {
 	tests := []struct {
 		description        string
@@ -874,6 +1029,36 @@ func Test_Core_Deployment_Read(t *testing.T) {
 			replicaset:         availableReplicaSet,
 			replicaSetRevision: revision2,
 		},
+		{
+			description: "[Revision 2] Read should succeed if Deployment is paused",
+			deployment: func(namespace, name, revision string) *unstructured.Unstructured {
+				return deploymentPaused(namespace, name, revision, true)
+			},
+			deploymentRevision: revision1,
+			replicaset:         availableReplicaSet,
+			replicaSetRevision: revision1,
+		},
+		{
+			description: "[Revision 2] Read should succeed if Deployment without progress deadline rolled out",
+			deployment: func(namespace, name, revision string) *unstructured.Unstructured {
+				return deploymentWithoutProgressDeadline(namespace, name, revision, 1)
+			},
+			deploymentRevision: revision2,
+			replicaset:         availableReplicaSet,
+			replicaSetRevision: revision2,
+		},
+		{
+			description: "[Revision 2] Read should fail if Deployment without progress deadline is unavailable",
+			deployment: func(namespace, name, revision string) *unstructured.Unstructured {
+				return deploymentWithoutProgressDeadline(namespace, name, revision, 0)
+			},
+			deploymentRevision: revision2,
+			replicaset:         availableReplicaSet,
+			replicaSetRevision: revision2,
+			expectedSubErrors: []string{
+				"0 out of 1 updated replicas are available",
+				"Minimum number of Pods to consider the application live was not attained"},
+		},
 	}
 
 	for _, test := range tests {
@@ -1286,6 +1471,7 @@ func deploymentRevision1Created(namespace, name string) *unstructured.Unstructur
     },
     "spec": {
         "replicas": 1,
+        "progressDeadlineSeconds": 600,
         "selector": {
             "matchLabels": {
                 "app": "foo"
@@ -1352,6 +1538,7 @@ func deploymentRevision2Created(namespace, name string) *unstructured.Unstructur
     },
     "spec": {
         "replicas": 1,
+        "progressDeadlineSeconds": 600,
         "selector": {
             "matchLabels": {
                 "app": "foo"
@@ -1542,6 +1729,52 @@ func deploymentUpdated(namespace, name, revision string) *unstructured.Unstructu
 	return obj
 }
 
+// deploymentPaused is `deploymentUpdated`, with its rollout paused. If `observed` is true, the
+// Deployment controller has observed the current spec, and reports that the rollout is paused.
+func deploymentPaused(namespace, name, revision string, observed bool) *unstructured.Unstructured {
+	obj := deploymentUpdated(namespace, name, revision)
+	if err := unstructured.SetNestedField(obj.Object, true, "spec", "paused"); err != nil {
+		panic(err)
+	}
+	if !observed {
+		return obj
+	}
+
+	if err := unstructured.SetNestedField(obj.Object, int64(2), "status", "observedGeneration"); err != nil {
+		panic(err)
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	progressing := conditions[1].(map[string]interface{})
+	progressing["status"] = "Unknown"
+	progressing["reason"] = "DeploymentPaused"
+	progressing["message"] = "Deployment is paused"
+	if err := unstructured.SetNestedSlice(obj.Object, conditions, "status", "conditions"); err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// deploymentWithoutProgressDeadline is `deploymentRevision2Created`, with `.spec.progressDeadlineSeconds`
+// set to the maximum value, so that the Deployment controller does not report the 'Progressing'
+// condition. The updated replica has the specified number of available replicas.
+func deploymentWithoutProgressDeadline(namespace, name, revision string, available int64) *unstructured.Unstructured {
+	obj := deploymentRevision2Created(namespace, name)
+	obj.SetAnnotations(map[string]string{
+		"deployment.kubernetes.io/revision": revision,
+		"pulumi.com/autonamed":              "true",
+	})
+	if err := unstructured.SetNestedField(obj.Object, int64(math.MaxInt32), "spec", "progressDeadlineSeconds"); err != nil {
+		panic(err)
+	}
+	if err := unstructured.SetNestedField(obj.Object, available, "status", "readyReplicas"); err != nil {
+		panic(err)
+	}
+	if err := unstructured.SetNestedField(obj.Object, available, "status", "availableReplicas"); err != nil {
+		panic(err)
+	}
+	return obj
+}
+
 func deploymentScaledToZero(namespace, name, revision string) *unstructured.Unstructured {
 	obj, err := decodeUnstructured(fmt.Sprintf(`{
     "kind": "Deployment",
@@ -0,0 +1,175 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package await
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/states"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
+	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// --------------------------------------------------------------------------
+
+// Failure diagnostics.
+//
+// When a Pod-based workload fails to become ready, the reasons reported by the Pod checker (e.g.,
+// `CrashLoopBackOff`) rarely explain *why* it failed. These helpers collect the last log lines of
+// the failed containers (including the previous instance of a restarted container) and the recent
+// Warning events of the failing Pods, so that they can be included in the await error.
+
+// --------------------------------------------------------------------------
+
+const (
+	// maxDiagnosedPods is the maximum number of failing Pods whose logs and events are collected.
+	maxDiagnosedPods = 3
+	// maxPodWarnings is the maximum number of Warning events collected for each Pod.
+	maxPodWarnings = 5
+)
+
+// failedContainer identifies a container whose logs explain a failure.
+type failedContainer struct {
+	name string
+	// previous is true if the relevant logs are those of the previous instance of the container,
+	// e.g., because it crashed and is waiting to be restarted.
+	previous bool
+}
+
+// failedContainers returns the containers of the Pod that exited with a non-zero code, or that are
+// waiting to be restarted after terminating.
+func failedContainers(pod *v1.Pod) []failedContainer {
+	var failed []failedContainer
+	var statuses []v1.ContainerStatus
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		switch {
+		case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+			failed = append(failed, failedContainer{name: status.Name})
+		case status.State.Waiting != nil && status.LastTerminationState.Terminated != nil:
+			failed = append(failed, failedContainer{name: status.Name, previous: true})
+		}
+	}
+	return failed
+}
+
+// failingPods returns the Pods for which the Pod checker reports warnings or errors, sorted by name.
+func failingPods(pods []*unstructured.Unstructured) []*v1.Pod {
+	var failing []*v1.Pod
+	for _, unstructuredPod := range pods {
+		pod, err := clients.PodFromUnstructured(unstructuredPod)
+		if err != nil {
+			logger.V(3).Infof("Failed to unmarshal Pod: %v", err)
+			continue
+		}
+		messages := states.NewPodChecker().Update(pod)
+		if len(messages.MessagesWithSeverity(diag.Warning, diag.Error)) > 0 {
+			failing = append(failing, pod)
+		}
+	}
+
+	sort.Slice(failing, func(i, j int) bool {
+		return failing[i].Name < failing[j].Name
+	})
+	return failing
+}
+
+// podDiagnostics returns the recent Warning events of each Pod, and the last log lines of its failed
+// containers. The number of log lines is set by the `pulumi.com/failureLogLines` annotation.
+func podDiagnostics(c createAwaitConfig, pods []*v1.Pod) []string {
+	if len(pods) > maxDiagnosedPods {
+		pods = pods[:maxDiagnosedPods]
+	}
+
+	var messages []string
+	for _, pod := range pods {
+		messages = append(messages, podWarnings(c, pod)...)
+		messages = append(messages, podLogs(c, pod)...)
+	}
+	return messages
+}
+
+func podWarnings(c createAwaitConfig, pod *v1.Pod) []string {
+	if c.clientSet == nil {
+		return nil
+	}
+
+	eventClient, err := clients.ResourceClient(kinds.Event, pod.Namespace, c.clientSet)
+	if err != nil {
+		logger.V(3).Infof("Could not make client to list Events: %v", err)
+		return nil
+	}
+	warnings, err := getLastWarningsForObject(eventClient, pod.Namespace, pod.Name, string(kinds.Pod), maxPodWarnings)
+	if err != nil {
+		logger.V(3).Infof("Could not list Events for Pod %q: %v", pod.Name, err)
+		return nil
+	}
+
+	var messages []string
+	for _, warning := range warnings {
+		messages = append(messages, fmt.Sprintf("Pod %q warning: %s", podID(pod), formatWarning(warning)))
+	}
+	return messages
+}
+
+func podLogs(c createAwaitConfig, pod *v1.Pod) []string {
+	lines := metadata.FailureLogLines(c.currentInputs)
+	if c.logClient == nil || lines == 0 {
+		return nil
+	}
+
+	var messages []string
+	for _, container := range failedContainers(pod) {
+		logs, err := c.logClient.TailLogs(pod.Namespace, pod.Name, container.name, container.previous, lines)
+		if err != nil {
+			logger.V(3).Infof("Could not get logs of container %q in Pod %q: %v", container.name, pod.Name, err)
+			continue
+		}
+		if message := formatContainerLogs(pod, container, logs); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// formatContainerLogs formats the logs of a container as a single, indented message.
+func formatContainerLogs(pod *v1.Pod, container failedContainer, logs string) string {
+	logs = strings.TrimRight(logs, "\n")
+	if strings.TrimSpace(logs) == "" {
+		return ""
+	}
+
+	instance := ""
+	if container.previous {
+		instance = " (previous instance)"
+	}
+	header := fmt.Sprintf("Logs of container %q in Pod %q%s:", container.name, podID(pod), instance)
+	return header + "\n    " + strings.Join(strings.Split(logs, "\n"), "\n    ")
+}
+
+func podID(pod *v1.Pod) string {
+	if pod.Namespace == "" {
+		return pod.Name
+	}
+	return fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+}
@@ -0,0 +1,69 @@
+// nolint: goconst
+package await
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_failedContainers(t *testing.T) {
+	pod := &v1.Pod{
+		Status: v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "migrate", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}}},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "exited", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1}}},
+				{
+					Name:                 "crashing",
+					State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 2}},
+				},
+				{Name: "pulling", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ErrImagePull"}}},
+				{Name: "running", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			},
+		},
+	}
+
+	assert.Equal(t, []failedContainer{
+		{name: "exited"},
+		{name: "crashing", previous: true},
+	}, failedContainers(pod))
+}
+
+func Test_formatContainerLogs(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo-abc123", Namespace: "default"}}
+
+	assert.Equal(t,
+		"Logs of container \"app\" in Pod \"default/foo-abc123\" (previous instance):\n"+
+			"    starting server\n"+
+			"    panic: missing DATABASE_URL",
+		formatContainerLogs(pod, failedContainer{name: "app", previous: true},
+			"starting server\npanic: missing DATABASE_URL\n"))
+	assert.Equal(t, "", formatContainerLogs(pod, failedContainer{name: "app"}, "\n"))
+}
+
+func Test_lastWarnings(t *testing.T) {
+	at := func(seconds int) metav1.Time {
+		return metav1.NewTime(time.Unix(int64(seconds), 0))
+	}
+	events := []v1.Event{
+		{Type: v1.EventTypeWarning, Reason: "BackOff", Message: "Back-off restarting failed container",
+			LastTimestamp: at(10), Count: 5},
+		{Type: v1.EventTypeNormal, Reason: "Pulled", Message: "Container image pulled", LastTimestamp: at(30)},
+		{Type: v1.EventTypeWarning, Reason: "Unhealthy", Message: "Readiness probe failed",
+			EventTime: metav1.NewMicroTime(time.Unix(20, 0))},
+		{Type: v1.EventTypeWarning, Reason: "BackOff", Message: "Back-off restarting failed container",
+			LastTimestamp: at(5)},
+		{Type: v1.EventTypeWarning, Reason: "FailedMount", Message: "Unable to attach volumes", LastTimestamp: at(1)},
+	}
+
+	warnings := lastWarnings(events, 2)
+	assert.Len(t, warnings, 2)
+	assert.Equal(t, "[Unhealthy] Readiness probe failed", formatWarning(warnings[0]))
+	assert.Equal(t, "[BackOff] Back-off restarting failed container (x5)", formatWarning(warnings[1]))
+}
@@ -15,6 +15,12 @@ type PartialError interface {
 	Object() *unstructured.Unstructured
 }
 
+// RollbackError represents an update that failed and was rolled back to the previous configuration
+// of the object.
+type RollbackError interface {
+	RolledBack() bool
+}
+
 // cancellationError represents an operation that failed because the user cancelled it.
 type cancellationError struct {
 	object    *unstructured.Unstructured
@@ -84,3 +90,39 @@ func (ie *initializationError) SubErrors() []string {
 func (ie *initializationError) Object() *unstructured.Unstructured {
 	return ie.object
 }
+
+// rollbackError occurs when an update fails to fully initialize, and the previous configuration of the
+// object is re-applied. If the rollback itself fails to become ready, `rollbackErr` is set.
+type rollbackError struct {
+	subErrors   []string
+	object      *unstructured.Unstructured
+	rollbackErr error
+}
+
+var _ error = (*rollbackError)(nil)
+var _ AggregatedError = (*rollbackError)(nil)
+var _ PartialError = (*rollbackError)(nil)
+var _ RollbackError = (*rollbackError)(nil)
+
+func (re *rollbackError) Error() string {
+	if re.rollbackErr != nil {
+		return fmt.Sprintf(
+			"Resource '%s' failed to update, and the rollback to its previous configuration also failed: %v",
+			re.object.GetName(), re.rollbackErr)
+	}
+	return fmt.Sprintf(
+		"Resource '%s' failed to update and was rolled back to its previous configuration", re.object.GetName())
+}
+
+// SubErrors returns the errors that caused the update to be rolled back.
+func (re *rollbackError) SubErrors() []string {
+	return re.subErrors
+}
+
+func (re *rollbackError) Object() *unstructured.Unstructured {
+	return re.object
+}
+
+func (re *rollbackError) RolledBack() bool {
+	return true
+}
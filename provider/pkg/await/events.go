@@ -0,0 +1,240 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package await
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
+	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ------------------------------------------------------------------------------------------------
+
+// Streaming of Kubernetes Events while awaiting a resource.
+//
+// Most problems that keep a resource from becoming ready are reported as Warning Events, often for
+// an object the resource owns rather than the resource itself (e.g., `FailedScheduling` for a Pod
+// of a Deployment). When the `streamEvents` provider config is set, these Events are shown as status
+// messages as soon as they arrive, rather than only in the error reported when the await fails.
+//
+// Events are shown for the awaited object, and for the ReplicaSets and Pods it owns, directly or
+// transitively. Ownership is tracked by watching ReplicaSets and Pods in the object's namespace, so
+// an Event that arrives before the object it refers to is held until the owner of that object is
+// known. Each reason is shown only once, since many controllers report the same problem repeatedly.
+
+// ------------------------------------------------------------------------------------------------
+
+// eventClockSkew is how far before the start of an await an Event may have been reported and still
+// be shown, to allow for differences between the clocks of the cluster and the provider.
+const eventClockSkew = time.Minute
+
+type eventStreamer struct {
+	config createAwaitConfig
+	since  time.Time
+
+	// owners maps the UID of each ReplicaSet and Pod in the namespace to the UIDs of its owners.
+	owners map[types.UID][]types.UID
+	// pending holds the Warning Events for objects that are not known to be owned by the awaited object.
+	pending map[types.UID][]v1.Event
+	// reported holds the reasons of the Events that were already shown.
+	reported map[string]bool
+}
+
+func newEventStreamer(c createAwaitConfig, since time.Time) *eventStreamer {
+	return &eventStreamer{
+		config:   c,
+		since:    since,
+		owners:   map[types.UID][]types.UID{},
+		pending:  map[types.UID][]v1.Event{},
+		reported: map[string]bool{},
+	}
+}
+
+// streamEvents shows the Warning Events of the awaited object and the objects it owns until the
+// returned function is called.
+func streamEvents(c createAwaitConfig) func() {
+	namespace := c.currentInputs.GetNamespace()
+
+	// Events for cluster-scoped objects are reported in the "default" namespace.
+	eventNamespace := namespace
+	if eventNamespace == "" {
+		eventNamespace = "default"
+	}
+	eventWatcher, err := c.watchKind(kinds.Event, eventNamespace, nil)
+	if err != nil {
+		logger.V(3).Infof("Could not set up watch for Events of %q: %v", c.currentInputs.GetName(), err)
+		return func() {}
+	}
+	watchers := []watch.Interface{eventWatcher}
+
+	var ownedObjects []<-chan watch.Event
+	if namespace != "" {
+		for _, kind := range []kinds.Kind{kinds.ReplicaSet, kinds.Pod} {
+			w, err := c.watchKind(kind, namespace, nil)
+			if err != nil {
+				logger.V(3).Infof("Could not set up watch for %s objects owned by %q: %v",
+					kind, c.currentInputs.GetName(), err)
+				continue
+			}
+			watchers = append(watchers, w)
+			ownedObjects = append(ownedObjects, w.ResultChan())
+		}
+	}
+
+	es := newEventStreamer(c, time.Now().Add(-eventClockSkew))
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		es.run(done, eventWatcher.ResultChan(), ownedObjects...)
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		for _, w := range watchers {
+			w.Stop()
+		}
+	}
+}
+
+// run processes Events and changes to owned objects until `done` is closed.
+func (es *eventStreamer) run(done <-chan struct{}, events <-chan watch.Event, ownedObjects ...<-chan watch.Event) {
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(events)},
+	}
+	for _, ch := range ownedObjects {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)})
+	}
+
+	for {
+		chosen, value, ok := reflect.Select(cases)
+		switch {
+		case chosen == 0:
+			return
+		case !ok:
+			// The channel was closed, so stop selecting it. A zero Value is ignored by reflect.Select.
+			cases[chosen].Chan = reflect.Value{}
+		case chosen == 1:
+			es.processEvent(value.Interface().(watch.Event))
+		default:
+			es.processOwnedObject(value.Interface().(watch.Event))
+		}
+	}
+}
+
+func (es *eventStreamer) processEvent(event watch.Event) {
+	if event.Type != watch.Added && event.Type != watch.Modified {
+		return
+	}
+	obj, isUnstructured := event.Object.(*unstructured.Unstructured)
+	if !isUnstructured {
+		logger.V(3).Infof("Event watch received unknown object type %q", reflect.TypeOf(event.Object))
+		return
+	}
+
+	var e v1.Event
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &e); err != nil {
+		logger.V(3).Infof("Failed to convert Event %q: %v", obj.GetName(), err)
+		return
+	}
+	if e.Type != v1.EventTypeWarning || eventTime(e).Before(es.since) {
+		return
+	}
+
+	if es.isAwaitedObject(e.InvolvedObject) || es.isOwned(e.InvolvedObject.UID) {
+		es.report(e)
+		return
+	}
+	es.pending[e.InvolvedObject.UID] = append(es.pending[e.InvolvedObject.UID], e)
+}
+
+func (es *eventStreamer) processOwnedObject(event watch.Event) {
+	obj, isUnstructured := event.Object.(*unstructured.Unstructured)
+	if !isUnstructured || event.Type == watch.Deleted {
+		return
+	}
+
+	var owners []types.UID
+	for _, owner := range obj.GetOwnerReferences() {
+		owners = append(owners, owner.UID)
+	}
+	es.owners[obj.GetUID()] = owners
+
+	// The owner of an object may be known only after Events for that object were received.
+	for uid, events := range es.pending {
+		if !es.isOwned(uid) {
+			continue
+		}
+		delete(es.pending, uid)
+		for _, e := range events {
+			es.report(e)
+		}
+	}
+}
+
+// isAwaitedObject returns true if the object an Event refers to is the awaited object.
+func (es *eventStreamer) isAwaitedObject(ref v1.ObjectReference) bool {
+	if outputs := es.config.currentOutputs; outputs != nil && outputs.GetUID() != "" {
+		return ref.UID == outputs.GetUID()
+	}
+	return ref.Kind == es.config.currentInputs.GetKind() && ref.Name == es.config.currentInputs.GetName()
+}
+
+// isOwned returns true if the object with the specified UID is owned by the awaited object, directly
+// or transitively.
+func (es *eventStreamer) isOwned(uid types.UID) bool {
+	outputs := es.config.currentOutputs
+	if outputs == nil || outputs.GetUID() == "" {
+		return false
+	}
+
+	visited := map[types.UID]bool{}
+	queue := []types.UID{uid}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == outputs.GetUID() {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		queue = append(queue, es.owners[current]...)
+	}
+	return false
+}
+
+func (es *eventStreamer) report(e v1.Event) {
+	if es.reported[e.Reason] {
+		return
+	}
+	es.reported[e.Reason] = true
+
+	es.config.logStatus(diag.Warning, fmt.Sprintf("[%s] %s %q: %s",
+		e.Reason, e.InvolvedObject.Kind, e.InvolvedObject.Name, strings.TrimSpace(e.Message)))
+}
@@ -0,0 +1,107 @@
+// nolint: goconst
+package await
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func ownedObject(kind, name, uid, ownerUID string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind(kind)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetUID(types.UID(uid))
+	obj.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Deployment", Name: "foo", UID: types.UID(ownerUID)}})
+	return obj
+}
+
+func kubeEvent(eventType, reason, kind, name, uid, message string, at time.Time) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Event",
+		"metadata": map[string]interface{}{
+			"namespace": "default",
+			"name":      name + "." + reason,
+		},
+		"involvedObject": map[string]interface{}{
+			"kind":      kind,
+			"namespace": "default",
+			"name":      name,
+			"uid":       uid,
+		},
+		"type":          eventType,
+		"reason":        reason,
+		"message":       message,
+		"lastTimestamp": at.UTC().Format(time.RFC3339),
+	}}
+}
+
+func Test_eventStreamer(t *testing.T) {
+	deployment := deploymentInput("default", "foo")
+	deployment.SetUID("deployment-uid")
+	config := mockAwaitConfig(deployment)
+
+	now := time.Now()
+	es := newEventStreamer(config, now.Add(-time.Minute))
+
+	events := make(chan watch.Event)
+	replicaSets := make(chan watch.Event)
+	pods := make(chan watch.Event)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		es.run(done, events, replicaSets, pods)
+	}()
+
+	// Warning Event for the Deployment itself.
+	events <- watchAddedEvent(kubeEvent("Warning", "FailedCreate", "Deployment", "foo", "deployment-uid",
+		"quota exceeded", now))
+	// Events that are not shown: a Normal Event, an Event reported before the await started, and an
+	// Event for a Pod that is not owned by the Deployment.
+	events <- watchAddedEvent(kubeEvent("Normal", "ScalingReplicaSet", "Deployment", "foo", "deployment-uid",
+		"Scaled up replica set foo-abc to 1", now))
+	events <- watchAddedEvent(kubeEvent("Warning", "FailedMount", "Deployment", "foo", "deployment-uid",
+		"old failure", now.Add(-time.Hour)))
+	pods <- watchAddedEvent(ownedObject("Pod", "bar-xyz", "other-pod-uid", "other-uid"))
+	events <- watchAddedEvent(kubeEvent("Warning", "BackOff", "Pod", "bar-xyz", "other-pod-uid",
+		"Back-off restarting failed container", now))
+
+	// The Event for the Pod arrives before its ReplicaSet is known.
+	pods <- watchAddedEvent(ownedObject("Pod", "foo-abc-123", "pod-uid", "rs-uid"))
+	events <- watchAddedEvent(kubeEvent("Warning", "FailedScheduling", "Pod", "foo-abc-123", "pod-uid",
+		"0/5 nodes are available: 5 Insufficient cpu.", now))
+	replicaSets <- watchAddedEvent(ownedObject("ReplicaSet", "foo-abc", "rs-uid", "deployment-uid"))
+
+	// Repeated reasons are only shown once.
+	events <- watchAddedEvent(kubeEvent("Warning", "FailedScheduling", "Pod", "foo-abc-123", "pod-uid",
+		"0/5 nodes are available: 5 Insufficient cpu.", now.Add(time.Second)))
+
+	close(done)
+	<-stopped
+
+	assert.Equal(t, []string{
+		`[FailedCreate] Deployment "foo": quota exceeded`,
+		`[FailedScheduling] Pod "foo-abc-123": 0/5 nodes are available: 5 Insufficient cpu.`,
+	}, statusMessages(config.logger))
+}
+
+func Test_eventStreamer_withoutUID(t *testing.T) {
+	config := mockAwaitConfig(deploymentInput("default", "foo"))
+	es := newEventStreamer(config, time.Now().Add(-time.Minute))
+
+	es.processEvent(watchAddedEvent(kubeEvent("Warning", "FailedCreate", "Deployment", "foo", "",
+		"quota exceeded", time.Now())))
+	es.processEvent(watchAddedEvent(kubeEvent("Warning", "FailedCreate", "Deployment", "bar", "",
+		"quota exceeded", time.Now())))
+
+	assert.Equal(t, []string{`[FailedCreate] Deployment "foo": quota exceeded`}, statusMessages(config.logger))
+}
@@ -3,22 +3,22 @@ package await
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/states"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/openapi"
-	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
-	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
 	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1b1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
@@ -26,7 +26,8 @@ import (
 
 // ------------------------------------------------------------------------------------------------
 
-// Await logic for extensions/v1beta1/Ingress.
+// Await logic for extensions/v1beta1/Ingress, networking.k8s.io/v1beta1/Ingress, and
+// networking.k8s.io/v1/Ingress.
 //
 // The goal of this code is to provide a fine-grained account of the status of a Kubernetes Ingress
 // resource as it is being initialized. The idea is that if something goes wrong early, we want
@@ -44,6 +45,11 @@ import (
 // The `ingressInitAwaiter` will synchronously process events from the union of all these channels.
 // Any time the success conditions described above a reached, we will terminate the awaiter.
 //
+// All of these versions are normalized to the networking.k8s.io/v1 shape before they are checked,
+// so the same rules apply regardless of which apiVersion the user wrote the Ingress against.
+// Backends that reference a resource other than a Service (`backend.resource`) are not backed by
+// Endpoints, so they are skipped rather than waited on.
+//
 // x-refs:
 //   * https://github.com/nginxinc/kubernetes-ingress/blob/5847d1f3906287d2771f3767d61c15ac02522caa/docs/report-ingress-status.md
 
@@ -56,20 +62,25 @@ const (
 type ingressInitAwaiter struct {
 	config                    createAwaitConfig
 	ingress                   *unstructured.Unstructured
-	ingressReady              bool
+	ingressExists             bool
 	endpointsSettled          bool
 	knownEndpointObjects      sets.String
 	knownExternalNameServices sets.String
+
+	// checker holds the readiness Conditions of the Ingress, evaluated against the last known version of
+	// the Ingress and the Endpoints and Services it may target.
+	checker *states.StateChecker
 }
 
 func makeIngressInitAwaiter(c createAwaitConfig) *ingressInitAwaiter {
 	return &ingressInitAwaiter{
 		config:                    c,
 		ingress:                   c.currentOutputs,
-		ingressReady:              false,
+		ingressExists:             false,
 		endpointsSettled:          false,
 		knownEndpointObjects:      sets.NewString(),
 		knownExternalNameServices: sets.NewString(),
+		checker:                   states.NewIngressChecker(),
 	}
 }
 
@@ -95,20 +106,18 @@ func (iia *ingressInitAwaiter) Await() error {
 	//   3.  Ingress entry exists for .status.loadBalancer.ingress.
 	//
 
-	ingressClient, endpointsClient, servicesClient, err := iia.makeClients()
-	if err != nil {
-		return err
-	}
+	namespace := iia.config.currentInputs.GetNamespace()
 
 	// Create ingress watcher.
-	ingressWatcher, err := ingressClient.Watch(context.TODO(), metav1.ListOptions{})
+	ingressWatcher, err := iia.config.watchKind(kinds.Ingress, namespace,
+		clients.ObjectNamed(iia.config.currentInputs.GetName()))
 	if err != nil {
 		return errors.Wrapf(err, "Could not set up watch for Ingress object %q",
 			iia.config.currentInputs.GetName())
 	}
 	defer ingressWatcher.Stop()
 
-	endpointWatcher, err := endpointsClient.Watch(context.TODO(), metav1.ListOptions{})
+	endpointWatcher, err := iia.config.watchKind(kinds.Endpoints, namespace, nil)
 	if err != nil {
 		return errors.Wrapf(err,
 			"Could not create watcher for Endpoint objects associated with Ingress %q",
@@ -116,14 +125,16 @@ func (iia *ingressInitAwaiter) Await() error {
 	}
 	defer endpointWatcher.Stop()
 
-	serviceWatcher, err := servicesClient.Watch(context.TODO(), metav1.ListOptions{})
+	serviceWatcher, err := iia.config.watchKind(kinds.Service, namespace, nil)
 	if err != nil {
 		return errors.Wrapf(err,
 			"Could not create watcher for Service objects associated with Ingress %q",
 			iia.config.currentInputs.GetName())
 	}
+	defer serviceWatcher.Stop()
 
-	timeout := metadata.TimeoutDuration(iia.config.timeout, iia.config.currentInputs, DefaultIngressTimeoutMins*60)
+	timeout := metadata.TimeoutDuration(iia.config.timeout, iia.config.defaultTimeouts,
+		iia.config.currentInputs, DefaultIngressTimeoutMins*60)
 	return iia.await(ingressWatcher, serviceWatcher, endpointWatcher, make(chan struct{}), time.After(timeout))
 }
 
@@ -197,44 +208,21 @@ func (iia *ingressInitAwaiter) await(
 	settled chan struct{},
 	timeout <-chan time.Time,
 ) error {
-	iia.config.logStatus(diag.Info, "[1/3] Finding a matching service for each Ingress path")
-
-	for {
-		// Check whether we've succeeded.
-		if iia.checkAndLogStatus() {
-			return nil
-		}
-
-		// Else, wait for updates.
-		select {
-		case <-iia.config.ctx.Done():
-			// On cancel, check one last time if the ingress is ready.
-			if iia.ingressReady && iia.checkIfEndpointsReady() {
-				return nil
-			}
-			return &cancellationError{
-				object:    iia.ingress,
-				subErrors: iia.errorMessages(),
-			}
-		case <-timeout:
-			// On timeout, check one last time if the ingress is ready.
-			if iia.ingressReady && iia.checkIfEndpointsReady() {
-				return nil
-			}
-			return &timeoutError{
-				object:    iia.ingress,
-				subErrors: iia.errorMessages(),
-			}
-		case <-settled:
+	loop := awaitLoop{
+		ctx:           iia.config.ctx,
+		ready:         iia.checkAndLogStatus,
+		object:        func() *unstructured.Unstructured { return iia.ingress },
+		errorMessages: iia.errorMessages,
+	}
+	return loop.run(timeout,
+		signals(settled, func() {
 			iia.endpointsSettled = true
-		case event := <-ingressWatcher.ResultChan():
-			iia.processIngressEvent(event)
-		case event := <-endpointWatcher.ResultChan():
+		}),
+		watchEvents(ingressWatcher, iia.processIngressEvent),
+		watchEvents(endpointWatcher, func(event watch.Event) {
 			iia.processEndpointEvent(event, settled)
-		case event := <-serviceWatcher.ResultChan():
-			iia.processServiceEvent(event)
-		}
-	}
+		}),
+		watchEvents(serviceWatcher, iia.processServiceEvent))
 }
 
 func (iia *ingressInitAwaiter) processServiceEvent(event watch.Event) {
@@ -273,95 +261,87 @@ func (iia *ingressInitAwaiter) processIngressEvent(event watch.Event) {
 		return
 	}
 
-	// Start with a blank slate.
-	iia.ingressReady = false
-
 	// Mark the ingress as not ready if it's deleted.
 	if event.Type == watch.Deleted {
+		iia.ingressExists = false
 		return
 	}
 
 	iia.ingress = ingress
-	obj, err := decodeIngress(ingress)
-	if err != nil {
-		logger.V(3).Infof("Unable to decode Ingress object from unstructured: %#v", ingress)
-		return
-	}
-
-	logger.V(3).Infof("Received status for ingress %q: %#v", inputIngressName, obj.Status)
-
-	// Update status of ingress object so that we can check success.
-	iia.ingressReady = len(obj.Status.LoadBalancer.Ingress) > 0
+	iia.ingressExists = true
 
-	logger.V(3).Infof("Waiting for ingress %q to update .status.loadBalancer with hostname/IP",
-		inputIngressName)
+	logger.V(3).Infof("Received status for ingress %q: %#v", inputIngressName, ingress.Object["status"])
 }
 
-func decodeIngress(u *unstructured.Unstructured) (*networkingv1b1.Ingress, error) {
+// decodeIngress decodes an Ingress of any supported apiVersion into the networking.k8s.io/v1 shape.
+func decodeIngress(u *unstructured.Unstructured) (*networkingv1.Ingress, error) {
 	b, err := u.MarshalJSON()
 	if err != nil {
 		return nil, err
 	}
-	var obj networkingv1b1.Ingress
-	err = json.Unmarshal(b, &obj)
-	if err != nil {
-		return nil, err
-	}
 
-	return &obj, nil
-}
+	if u.GetAPIVersion() == string(kinds.NetworkingV1) {
+		var obj networkingv1.Ingress
+		err = json.Unmarshal(b, &obj)
+		if err != nil {
+			return nil, err
+		}
+		return &obj, nil
+	}
 
-func (iia *ingressInitAwaiter) checkIfEndpointsReady() bool {
-	obj, err := decodeIngress(iia.ingress)
+	// extensions/v1beta1 and networking.k8s.io/v1beta1 share the same schema.
+	var legacy networkingv1b1.Ingress
+	err = json.Unmarshal(b, &legacy)
 	if err != nil {
-		logger.V(3).Infof("Unable to decode Ingress object from unstructured: %#v", iia.ingress)
-		return false
+		return nil, err
 	}
 
-	for _, rule := range obj.Spec.Rules {
-		if rule.HTTP == nil {
-			iia.config.logStatus(diag.Error, fmt.Sprintf("expected value %q is unset for ingress: %s",
-				".spec.rules[*].http", obj.Name))
-			return false
-		}
-		for _, path := range rule.HTTP.Paths {
-			// Ignore ExternalName services
-			if iia.knownExternalNameServices.Has(path.Backend.ServiceName) {
-				continue
-			}
-
-			if !iia.knownEndpointObjects.Has(path.Backend.ServiceName) {
-				iia.config.logStatus(diag.Info, fmt.Sprintf("No matching service found for ingress rule: %s",
-					expectedIngressPath(rule.Host, path.Path, path.Backend.ServiceName)))
-
-				return false
+	obj := networkingv1.Ingress{
+		TypeMeta:   legacy.TypeMeta,
+		ObjectMeta: legacy.ObjectMeta,
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: legacy.Spec.IngressClassName,
+			DefaultBackend:   ingressBackendFromV1Beta1(legacy.Spec.Backend),
+		},
+		Status: networkingv1.IngressStatus{
+			LoadBalancer: legacy.Status.LoadBalancer,
+		},
+	}
+	for _, legacyRule := range legacy.Spec.Rules {
+		rule := networkingv1.IngressRule{Host: legacyRule.Host}
+		if legacyRule.HTTP != nil {
+			rule.HTTP = &networkingv1.HTTPIngressRuleValue{}
+			for _, legacyPath := range legacyRule.HTTP.Paths {
+				rule.HTTP.Paths = append(rule.HTTP.Paths, networkingv1.HTTPIngressPath{
+					Path:    legacyPath.Path,
+					Backend: *ingressBackendFromV1Beta1(&legacyPath.Backend),
+				})
 			}
 		}
+		obj.Spec.Rules = append(obj.Spec.Rules, rule)
 	}
 
-	return true
+	return &obj, nil
 }
 
-// expectedIngressPath is a helper to print a useful error message.
-func expectedIngressPath(host, path, serviceName string) string {
-	rulePath := path
-	if host != "" {
-		rulePath = host + path
+// ingressBackendFromV1Beta1 converts a v1beta1 backend (`serviceName`/`servicePort`) into the v1
+// backend shape (`service.name`/`service.port.number|name`).
+func ingressBackendFromV1Beta1(backend *networkingv1b1.IngressBackend) *networkingv1.IngressBackend {
+	if backend == nil {
+		return nil
 	}
 
-	// It is valid for a user not to specify either a host or path [1]. In this case, any traffic not
-	// matching another rule is routed to the specified Service for this rule. Print
-	// `"" (default path)` to make this expectation clear to users.
-	//
-	// [1] https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.13/#httpingresspath-v1beta1-extensions
-	if rulePath == "" {
-		rulePath = `"" (default path)`
-	} else {
-		rulePath = fmt.Sprintf("%q", rulePath)
+	if backend.Resource != nil {
+		return &networkingv1.IngressBackend{Resource: backend.Resource}
 	}
 
-	// [host][path] -> serviceName
-	return fmt.Sprintf("%s -> %q", rulePath, serviceName)
+	service := &networkingv1.IngressServiceBackend{Name: backend.ServiceName}
+	if backend.ServicePort.Type == intstr.String {
+		service.Port.Name = backend.ServicePort.StrVal
+	} else {
+		service.Port.Number = backend.ServicePort.IntVal
+	}
+	return &networkingv1.IngressBackend{Service: service}
 }
 
 func (iia *ingressInitAwaiter) processEndpointEvent(event watch.Event, settledCh chan<- struct{}) {
@@ -393,33 +373,32 @@ func (iia *ingressInitAwaiter) processEndpointEvent(event watch.Event, settledCh
 }
 
 func (iia *ingressInitAwaiter) errorMessages() []string {
-	messages := make([]string, 0)
-
-	if !iia.checkIfEndpointsReady() {
-		messages = append(messages,
-			"Ingress has at least one rule that does not target any Service. "+
-				"Field '.spec.rules[].http.paths[].backend.serviceName' may not match any active Service")
-	}
-
-	if !iia.ingressReady {
-		messages = append(messages,
-			"Ingress .status.loadBalancer field was not updated with a hostname/IP address. "+
-				"\n    for more information about this error, see https://pulumi.io/xdv72s")
-	}
-
-	return messages
+	return iia.checker.Failures()
 }
 
+// checkAndLogStatus evaluates the Ingress readiness Conditions against the last known version of the
+// Ingress and the Endpoints and Services it may target, and returns true if the live Ingress is ready.
 func (iia *ingressInitAwaiter) checkAndLogStatus() bool {
-	success := iia.ingressReady && iia.checkIfEndpointsReady()
-	if success {
-		iia.config.logStatus(diag.Info,
-			fmt.Sprintf("%sIngress initialization complete", cmdutil.EmojiOr("✅ ", "")))
-	} else if iia.checkIfEndpointsReady() {
-		iia.config.logStatus(diag.Info, "[2/3] Waiting for update of .status.loadBalancer with hostname/IP")
+	obj, err := decodeIngress(iia.ingress)
+	if err != nil {
+		logger.V(3).Infof("Unable to decode Ingress object from unstructured: %#v", iia.ingress)
+		return false
 	}
 
-	return success
+	messages := iia.checker.Update(&states.IngressState{
+		Ingress:              obj,
+		Endpoints:            iia.knownEndpointObjects,
+		ExternalNameServices: iia.knownExternalNameServices,
+	})
+
+	// Readiness is only determined by the live object.
+	if !iia.ingressExists {
+		return false
+	}
+	for _, message := range messages {
+		iia.config.logMessage(message)
+	}
+	return iia.checker.Ready()
 }
 
 func (iia *ingressInitAwaiter) makeClients() (
@@ -214,6 +214,51 @@ func Test_Extensions_Ingress_Read(t *testing.T) {
 
 }
 
+func Test_Networking_V1_Ingress_Read(t *testing.T) {
+	tests := []struct {
+		description       string
+		ingress           func(namespace, name, targetService string) *unstructured.Unstructured
+		endpoint          func(namespace, name string) *unstructured.Unstructured
+		expectedSubErrors []string
+	}{
+		{
+			description: "Read should succeed when Ingress is allocated an IP address and all backends match an existing Endpoint",
+			ingress:     initializedIngressV1,
+			endpoint:    initializedEndpoint,
+		},
+		{
+			description: "Read should fail if not all Ingress backends match existing Endpoints",
+			ingress:     initializedIngressV1,
+			expectedSubErrors: []string{
+				"Ingress has at least one rule that does not target any Service. " +
+					"Field '.spec.rules[].http.paths[].backend.service.name' may not match any active Service",
+			},
+		},
+		{
+			description: "Read should skip resource backends",
+			ingress:     initializedIngressV1ResourceBackend,
+		},
+	}
+
+	for _, test := range tests {
+		ingress := test.ingress("default", "foo", "foo-4setj4y6")
+		awaiter := makeIngressInitAwaiter(mockAwaitConfig(ingress))
+
+		endpointList := unstructuredList()
+		if test.endpoint != nil {
+			endpoint := test.endpoint("default", "foo-4setj4y6")
+			endpointList = unstructuredList(*endpoint)
+		}
+		err := awaiter.read(ingress, endpointList, unstructuredList())
+
+		if test.expectedSubErrors != nil {
+			assert.Equal(t, test.expectedSubErrors, err.(*initializationError).SubErrors(), test.description)
+		} else {
+			assert.Nil(t, err, test.description)
+		}
+	}
+}
+
 // --------------------------------------------------------------------------
 
 // Utility constructs.
@@ -336,27 +381,102 @@ func initializedIngressUnspecifiedPath(namespace, name, targetService string) *u
 	return obj
 }
 
-func Test_expectedIngressPath(t *testing.T) {
-	type args struct {
-		host        string
-		path        string
-		serviceName string
-	}
-	tests := []struct {
-		name string
-		args args
-		want string
-	}{
-		{name: "host + path", args: args{host: "foo", path: "/bar", serviceName: "baz"}, want: `"foo/bar" -> "baz"`},
-		{name: "host only", args: args{host: "foo", serviceName: "baz"}, want: `"foo" -> "baz"`},
-		{name: "path only", args: args{path: "/bar", serviceName: "baz"}, want: `"/bar" -> "baz"`},
-		{name: "empty", args: args{serviceName: "baz"}, want: `"" (default path) -> "baz"`},
+func initializedIngressV1(namespace, name, targetService string) *unstructured.Unstructured {
+	obj, err := decodeUnstructured(fmt.Sprintf(`{
+    "apiVersion": "networking.k8s.io/v1",
+    "kind": "Ingress",
+    "metadata": {
+        "name": "%s",
+        "namespace": "%s"
+    },
+    "spec": {
+        "ingressClassName": "nginx",
+        "defaultBackend": {
+            "service": {
+                "name": "%s",
+                "port": {
+                    "name": "http"
+                }
+            }
+        },
+        "rules": [
+            {
+                "http": {
+                    "paths": [
+                        {
+                            "backend": {
+                                "service": {
+                                    "name": "%s",
+                                    "port": {
+                                        "number": 80
+                                    }
+                                }
+                            },
+                            "path": "/nginx",
+                            "pathType": "Prefix"
+                        }
+                    ]
+                }
+            }
+        ]
+    },
+    "status": {
+        "loadBalancer": {
+            "ingress": [
+                {
+                    "hostname": "localhost"
+                }
+            ]
+        }
+    }
+}`, name, namespace, targetService, targetService))
+	if err != nil {
+		panic(err)
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := expectedIngressPath(tt.args.host, tt.args.path, tt.args.serviceName); got != tt.want {
-				t.Errorf("expectedIngressPath() = %v, want %v", got, tt.want)
-			}
-		})
+	return obj
+}
+
+func initializedIngressV1ResourceBackend(namespace, name, _ string) *unstructured.Unstructured {
+	obj, err := decodeUnstructured(fmt.Sprintf(`{
+    "apiVersion": "networking.k8s.io/v1",
+    "kind": "Ingress",
+    "metadata": {
+        "name": "%s",
+        "namespace": "%s"
+    },
+    "spec": {
+        "rules": [
+            {
+                "http": {
+                    "paths": [
+                        {
+                            "backend": {
+                                "resource": {
+                                    "apiGroup": "k8s.example.com",
+                                    "kind": "StorageBucket",
+                                    "name": "static-assets"
+                                }
+                            },
+                            "path": "/static",
+                            "pathType": "ImplementationSpecific"
+                        }
+                    ]
+                }
+            }
+        ]
+    },
+    "status": {
+        "loadBalancer": {
+            "ingress": [
+                {
+                    "hostname": "localhost"
+                }
+            ]
+        }
+    }
+}`, name, namespace))
+	if err != nil {
+		panic(err)
 	}
+	return obj
 }
@@ -29,6 +29,7 @@ import (
 	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
@@ -94,26 +95,28 @@ func makeJobInitAwaiter(c createAwaitConfig) *jobInitAwaiter {
 }
 
 func (jia *jobInitAwaiter) Await() error {
-	jobClient, err := clients.ResourceClient(kinds.Job, jia.config.currentInputs.GetNamespace(), jia.config.clientSet)
-	if err != nil {
-		return errors.Wrapf(err,
-			"Could not make client to watch Job %q",
-			jia.config.currentInputs.GetName())
-	}
-	jobWatcher, err := jobClient.Watch(context.TODO(), metav1.ListOptions{})
+	jobWatcher, err := jia.config.watchKind(kinds.Job, jia.config.currentInputs.GetNamespace(),
+		clients.ObjectNamed(jia.config.currentInputs.GetName()))
 	if err != nil {
 		return errors.Wrapf(err, "Couldn't set up watch for Job object %q",
 			jia.config.currentInputs.GetName())
 	}
 	defer jobWatcher.Stop()
 
-	podAggregator, err := NewPodAggregator(ResourceIDFromUnstructured(jia.job), jia.config.clientSet)
+	podWatcher, err := jia.config.watchKind(kinds.Pod, jia.job.GetNamespace(), nil)
+	if err != nil {
+		return errors.Wrapf(err, "Could not create watcher for Pods associated with Job %q",
+			jia.config.currentInputs.GetName())
+	}
+	podAggregator, err := NewPodAggregator(ResourceIDFromUnstructured(jia.job), jia.config.clientSet, podWatcher)
 	if err != nil {
+		podWatcher.Stop()
 		return errors.Wrapf(err, "Could not create PodAggregator for %s", jia.resource.GVKString())
 	}
 	defer podAggregator.Stop()
 
-	timeout := metadata.TimeoutDuration(jia.config.timeout, jia.config.currentInputs, DefaultJobTimeoutMins*60)
+	timeout := metadata.TimeoutDuration(jia.config.timeout, jia.config.defaultTimeouts,
+		jia.config.currentInputs, DefaultJobTimeoutMins*60)
 	for {
 		if jia.state.Ready() {
 			return nil
@@ -164,7 +167,7 @@ func (jia *jobInitAwaiter) Read() error {
 		return nil
 	}
 
-	podAggregator, err := NewPodAggregator(ResourceIDFromUnstructured(jia.job), jia.config.clientSet)
+	podAggregator, err := NewPodAggregator(ResourceIDFromUnstructured(jia.job), jia.config.clientSet, nil)
 	if err != nil {
 		return errors.Wrapf(err, "Could not create PodAggregator for %s", jia.resource.GVKString())
 	}
@@ -232,6 +235,38 @@ func (jia *jobInitAwaiter) errorMessages() []string {
 	for _, message := range jia.errors.Messages {
 		messages = append(messages, message.S)
 	}
+	messages = append(messages, podDiagnostics(jia.config, failingPods(jia.pods()))...)
 
 	return messages
 }
+
+// pods returns the Pods created by the Job.
+func (jia *jobInitAwaiter) pods() []*unstructured.Unstructured {
+	if jia.config.clientSet == nil || jia.job == nil {
+		return nil
+	}
+
+	matchLabels, _, _ := unstructured.NestedStringMap(jia.job.Object, "spec", "selector", "matchLabels")
+	if len(matchLabels) == 0 {
+		return nil
+	}
+
+	podClient, err := clients.ResourceClient(kinds.Pod, jia.job.GetNamespace(), jia.config.clientSet)
+	if err != nil {
+		logger.V(3).Infof("Could not make client to list Pods: %v", err)
+		return nil
+	}
+	list, err := podClient.List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(matchLabels).String(),
+	})
+	if err != nil {
+		logger.V(3).Infof("Could not list Pods for Job %q: %v", jia.job.GetName(), err)
+		return nil
+	}
+
+	var pods []*unstructured.Unstructured
+	for i := range list.Items {
+		pods = append(pods, &list.Items[i])
+	}
+	return pods
+}
@@ -0,0 +1,153 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package await
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ------------------------------------------------------------------------------------------------
+
+// Shared event loop for awaiters.
+//
+// Most awaiters wait on the union of the same kinds of channels: a watch on the awaited object, watches
+// on related objects (Pods, Endpoints, Services, ...), periodic tickers, a timeout, and a cancellation
+// channel. `awaitLoop` implements that loop once, so that an awaiter only has to supply its success
+// check, its error messages, and a handler for each channel.
+//
+// Channels that are closed (e.g., because a watch timed out on the server side) are no longer
+// selected, rather than spinning on the zero value.
+
+// ------------------------------------------------------------------------------------------------
+
+// eventSource is a channel the awaitLoop receives from, along with the handler for received values.
+type eventSource struct {
+	ch     reflect.Value
+	handle func(value reflect.Value)
+}
+
+// watchEvents returns an eventSource for the events of a watch.
+func watchEvents(watcher watch.Interface, handle func(event watch.Event)) eventSource {
+	return eventSource{
+		ch: reflect.ValueOf(watcher.ResultChan()),
+		handle: func(value reflect.Value) {
+			handle(value.Interface().(watch.Event))
+		},
+	}
+}
+
+// aggregatorMessages returns an eventSource for the messages reported by a PodAggregator.
+func aggregatorMessages(ch <-chan logging.Messages, handle func(messages logging.Messages)) eventSource {
+	return eventSource{
+		ch: reflect.ValueOf(ch),
+		handle: func(value reflect.Value) {
+			handle(value.Interface().(logging.Messages))
+		},
+	}
+}
+
+// ticks returns an eventSource for a ticker, or any other channel of times.
+func ticks(ch <-chan time.Time, handle func()) eventSource {
+	return eventSource{
+		ch: reflect.ValueOf(ch),
+		handle: func(reflect.Value) {
+			handle()
+		},
+	}
+}
+
+// signals returns an eventSource for a channel that carries no data.
+func signals(ch <-chan struct{}, handle func()) eventSource {
+	return eventSource{
+		ch: reflect.ValueOf(ch),
+		handle: func(reflect.Value) {
+			handle()
+		},
+	}
+}
+
+// awaitLoop waits until an object is ready, the timeout fires, or the operation is cancelled.
+type awaitLoop struct {
+	ctx context.Context
+
+	// ready checks whether the object is ready, and logs its status. It is called before waiting for
+	// each event.
+	ready func() bool
+	// lastChance is optional. If set, it is called on timeout or cancellation, and the await succeeds if
+	// it returns true.
+	lastChance func() bool
+	// object returns the latest version of the awaited object, which is reported in errors.
+	object func() *unstructured.Unstructured
+	// errorMessages returns the reasons the object is not ready, which are reported in errors.
+	errorMessages func() []string
+}
+
+const (
+	loopCancelled = iota
+	loopTimeout
+	loopSources
+)
+
+// run processes events from the sources until the object is ready, or returns an error on timeout or
+// cancellation.
+func (l awaitLoop) run(timeout <-chan time.Time, sources ...eventSource) error {
+	cases := []reflect.SelectCase{
+		loopCancelled: {Dir: reflect.SelectRecv, Chan: reflect.ValueOf(l.ctx.Done())},
+		loopTimeout:   {Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timeout)},
+	}
+	for _, source := range sources {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: source.ch})
+	}
+
+	for {
+		if l.ready() {
+			return nil
+		}
+
+		// Else, wait for updates.
+		chosen, value, ok := reflect.Select(cases)
+		switch chosen {
+		case loopCancelled:
+			if l.lastChance != nil && l.lastChance() {
+				return nil
+			}
+			return &cancellationError{
+				object:    l.object(),
+				subErrors: l.errorMessages(),
+			}
+		case loopTimeout:
+			if l.lastChance != nil && l.lastChance() {
+				return nil
+			}
+			return &timeoutError{
+				object:    l.object(),
+				subErrors: l.errorMessages(),
+			}
+		default:
+			if !ok {
+				// The channel was closed, so stop selecting it. A zero Value is ignored by reflect.Select.
+				cases[chosen].Chan = reflect.Value{}
+				continue
+			}
+			sources[chosen-loopSources].handle(value)
+		}
+	}
+}
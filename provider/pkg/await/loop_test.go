@@ -0,0 +1,74 @@
+// nolint: goconst
+package await
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func Test_awaitLoop(t *testing.T) {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "foo"},
+	}}
+
+	tests := []struct {
+		description   string
+		lastChance    bool
+		do            func(events chan watch.Event, timeout chan time.Time)
+		expectedError error
+	}{
+		{
+			description: "Should succeed once ready",
+			do: func(events chan watch.Event, timeout chan time.Time) {
+				events <- watchAddedEvent(object)
+			},
+		},
+		{
+			description: "Should stop selecting closed channels",
+			do: func(events chan watch.Event, timeout chan time.Time) {
+				close(events)
+
+				// Timeout. Failure.
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{object: object, subErrors: []string{"not ready"}},
+		},
+		{
+			description: "Should succeed on timeout if ready at the last chance",
+			lastChance:  true,
+			do: func(events chan watch.Event, timeout chan time.Time) {
+				// Timeout. Success.
+				timeout <- time.Now()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		ready := false
+		loop := awaitLoop{
+			ctx:           context.Background(),
+			ready:         func() bool { return ready },
+			object:        func() *unstructured.Unstructured { return object },
+			errorMessages: func() []string { return []string{"not ready"} },
+		}
+		if test.lastChance {
+			loop.lastChance = func() bool { return true }
+		}
+
+		events := make(chan watch.Event)
+		timeout := make(chan time.Time)
+		go test.do(events, timeout)
+
+		err := loop.run(timeout, watchEvents(&chanWatcher{results: events}, func(event watch.Event) {
+			ready = event.Type == watch.Added
+		}))
+		assert.Equal(t, test.expectedError, err, test.description)
+	}
+}
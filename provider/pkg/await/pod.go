@@ -11,6 +11,7 @@ import (
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
 	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/watch"
@@ -113,6 +114,12 @@ func (pia *podInitAwaiter) errorMessages() []string {
 		messages = append(messages, message.S)
 	}
 
+	if pia.pod != nil {
+		if pod, err := clients.PodFromUnstructured(pia.pod); err == nil {
+			messages = append(messages, podDiagnostics(pia.config, []*v1.Pod{pod})...)
+		}
+	}
+
 	return messages
 }
 
@@ -129,21 +136,16 @@ func awaitPodUpdate(u updateAwaitConfig) error {
 }
 
 func (pia *podInitAwaiter) Await() error {
-	podClient, err := clients.ResourceClient(
-		kinds.Pod, pia.config.currentInputs.GetNamespace(), pia.config.clientSet)
-	if err != nil {
-		return errors.Wrapf(err,
-			"Could not make client to watch Pod %q",
-			pia.config.currentInputs.GetName())
-	}
-	podWatcher, err := podClient.Watch(context.TODO(), metav1.ListOptions{})
+	podWatcher, err := pia.config.watchKind(kinds.Pod, pia.config.currentInputs.GetNamespace(),
+		clients.ObjectNamed(pia.config.currentInputs.GetName()))
 	if err != nil {
 		return errors.Wrapf(err, "Couldn't set up watch for Pod object %q",
 			pia.config.currentInputs.GetName())
 	}
 	defer podWatcher.Stop()
 
-	timeout := metadata.TimeoutDuration(pia.config.timeout, pia.config.currentInputs, DefaultPodTimeoutMins*60)
+	timeout := metadata.TimeoutDuration(pia.config.timeout, pia.config.defaultTimeouts,
+		pia.config.currentInputs, DefaultPodTimeoutMins*60)
 	for {
 		if pia.state.Ready() {
 			return nil
@@ -0,0 +1,168 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package await
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ------------------------------------------------------------------------------------------------
+
+// Recording of await workflows.
+//
+// When the `recordAwaitEventsToDirectory` provider config (or the
+// `PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY` environment variable) is set, every watch event
+// received while awaiting a resource is captured, and written to
+// `<directory>/<kind>/<name>-<operation>-<timestamp>.json` when the await completes. This includes
+// events for related objects, such as the Pods of a Deployment, and events for objects that the
+// awaiter ignores.
+//
+// Each recording is a JSON array of watch events in the order they were received, in the same
+// `{"type": ..., "object": ...}` shape that the API server uses. Recordings can be moved to
+// `recordings/workflows/<kind>` and replayed through an awaiter in a test, so that an await failure
+// observed against a real cluster can be turned into a regression test.
+
+// ------------------------------------------------------------------------------------------------
+
+// recorder captures the watch events received while awaiting a single resource.
+type recorder struct {
+	path string
+
+	mu     sync.Mutex
+	events []recordedEvent
+}
+
+// recordedEvent is the serialized form of a watch.Event.
+type recordedEvent struct {
+	Type   watch.EventType `json:"type"`
+	Object runtime.Object  `json:"object"`
+}
+
+func newRecorder(directory string, obj *unstructured.Unstructured, operation string) *recorder {
+	name := fmt.Sprintf("%s-%s-%s.json", obj.GetName(), operation, time.Now().UTC().Format("20060102T150405.000Z"))
+	return &recorder{
+		path: filepath.Join(directory, strings.ToLower(obj.GetKind()), name),
+	}
+}
+
+func (r *recorder) record(event watch.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Copy the object, since the awaiter owns the original.
+	if event.Object != nil {
+		event.Object = event.Object.DeepCopyObject()
+	}
+	r.events = append(r.events, recordedEvent{Type: event.Type, Object: event.Object})
+}
+
+// save writes the recorded events to the recording file. Nothing is written if no events were
+// received.
+func (r *recorder) save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.events) == 0 {
+		return nil
+	}
+	b, err := json.MarshalIndent(r.events, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, b, 0600)
+}
+
+// watch returns a watcher that records every event received from `w`. If `r` is nil, `w` is returned
+// unchanged.
+func (r *recorder) watch(w watch.Interface, err error) (watch.Interface, error) {
+	if r == nil || err != nil {
+		return w, err
+	}
+
+	rw := &recordingWatcher{
+		watcher:  w,
+		recorder: r,
+		results:  make(chan watch.Event),
+		done:     make(chan struct{}),
+	}
+	go rw.forward()
+	return rw, nil
+}
+
+// startRecording returns the recorder that awaiters should use and a function that saves the
+// recording, which must be called once the await completes. If recording is disabled, the recorder is
+// nil.
+func (c ProviderConfig) startRecording(obj *unstructured.Unstructured, operation string) (*recorder, func()) {
+	if c.RecordingDirectory == "" || obj == nil {
+		return nil, func() {}
+	}
+
+	r := newRecorder(c.RecordingDirectory, obj, operation)
+	return r, func() {
+		if err := r.save(); err != nil && c.DedupLogger != nil {
+			c.DedupLogger.LogMessage(logging.WarningMessage(
+				fmt.Sprintf("Failed to save await recording to %q: %v", r.path, err)))
+		}
+	}
+}
+
+// recordingWatcher is a `watch.Interface` that records the events of the watcher it wraps.
+type recordingWatcher struct {
+	watcher  watch.Interface
+	recorder *recorder
+	results  chan watch.Event
+	done     chan struct{}
+	stop     sync.Once
+}
+
+var _ watch.Interface = (*recordingWatcher)(nil)
+
+func (rw *recordingWatcher) forward() {
+	defer close(rw.results)
+	for event := range rw.watcher.ResultChan() {
+		rw.recorder.record(event)
+		select {
+		case rw.results <- event:
+		case <-rw.done:
+			return
+		}
+	}
+}
+
+func (rw *recordingWatcher) Stop() {
+	rw.stop.Do(func() {
+		close(rw.done)
+		rw.watcher.Stop()
+	})
+}
+
+func (rw *recordingWatcher) ResultChan() <-chan watch.Event {
+	return rw.results
+}
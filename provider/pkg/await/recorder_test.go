@@ -0,0 +1,61 @@
+// nolint: goconst
+package await
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/recordings"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func Test_recorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "await-recording")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	obj := daemonsetInput("default", "foo", "RollingUpdate")
+	rec := newRecorder(dir, obj, "create")
+	assert.Equal(t, filepath.Join(dir, "daemonset"), filepath.Dir(rec.path))
+
+	results := make(chan watch.Event)
+	w, err := rec.watch(&chanWatcher{results: results}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	events := []watch.Event{
+		{Type: watch.Added, Object: obj},
+		{Type: watch.Modified, Object: daemonsetStatus("default", "foo", "RollingUpdate", 1, 1, 3, 3, 3)},
+		{Type: watch.Deleted, Object: obj},
+	}
+	go func() {
+		for _, event := range events {
+			results <- event
+		}
+		close(results)
+	}()
+
+	var received []watch.Event
+	for event := range w.ResultChan() {
+		received = append(received, event)
+	}
+	w.Stop()
+	assert.Equal(t, events, received)
+
+	if !assert.NoError(t, rec.save()) {
+		return
+	}
+	assert.Equal(t, events, recordings.MustLoadEvents(rec.path))
+}
+
+func Test_recorder_disabled(t *testing.T) {
+	c := ProviderConfig{}
+	rec, save := c.startRecording(daemonsetInput("default", "foo", "RollingUpdate"), "create")
+	assert.Nil(t, rec)
+	save()
+}
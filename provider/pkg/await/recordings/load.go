@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // mustConvertObjToUnstructured converts a raw object to Unstructured and panics on error.
@@ -77,3 +78,31 @@ func MustLoadWorkflow(path string) []*unstructured.Unstructured {
 
 	return unstructureds
 }
+
+// MustLoadEvents loads a JSON array of watch events from the specified path, as written by the provider when the
+// `recordAwaitEventsToDirectory` config is set, and returns the corresponding watch.Events. Elements that are bare
+// objects rather than `{"type": ..., "object": ...}` events are treated as `ADDED` events, so that workflows can be
+// loaded too. This function is intended to be used with vetted test data and will panic on error.
+func MustLoadEvents(path string) []watch.Event {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+
+	var elements []map[string]interface{}
+	if err := json.Unmarshal(b, &elements); err != nil {
+		panic(err)
+	}
+	var events []watch.Event
+	for _, element := range elements {
+		eventType, isEvent := element["type"].(string)
+		obj, hasObject := element["object"]
+		if !isEvent || !hasObject {
+			events = append(events, watch.Event{Type: watch.Added, Object: mustConvertObjToUnstructured(element)})
+			continue
+		}
+		events = append(events, watch.Event{Type: watch.EventType(eventType), Object: mustConvertObjToUnstructured(obj)})
+	}
+
+	return events
+}
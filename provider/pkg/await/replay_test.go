@@ -0,0 +1,82 @@
+// nolint: goconst
+package await
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/recordings"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// replay feeds a recording of watch events to an awaiter. Each event is sent to the watcher for the
+// kind of its object, and events for kinds that the awaiter does not watch are dropped. Once every
+// event has been sent, the timeout fires, so that the awaiter reports the state it reached.
+type replay struct {
+	watchers map[string]chan watch.Event
+	timeout  chan time.Time
+	done     chan struct{}
+}
+
+func newReplay(kinds ...string) *replay {
+	r := &replay{
+		watchers: map[string]chan watch.Event{},
+		timeout:  make(chan time.Time),
+		done:     make(chan struct{}),
+	}
+	for _, kind := range kinds {
+		r.watchers[kind] = make(chan watch.Event)
+	}
+	return r
+}
+
+// watcher returns the watcher to which the events for objects of the specified kind are sent.
+func (r *replay) watcher(kind string) *chanWatcher {
+	return &chanWatcher{results: r.watchers[kind]}
+}
+
+// run replays the events while `await` runs, and returns the error it returns. The replay stops
+// early if the awaiter returns before every event has been sent.
+func (r *replay) run(events []watch.Event, await func() error) error {
+	go func() {
+		for _, event := range events {
+			obj, isUnstructured := event.Object.(*unstructured.Unstructured)
+			if !isUnstructured {
+				continue
+			}
+			results, watched := r.watchers[obj.GetKind()]
+			if !watched {
+				continue
+			}
+			select {
+			case results <- event:
+			case <-r.done:
+				return
+			}
+		}
+		select {
+		case r.timeout <- time.Now():
+		case <-r.done:
+		}
+	}()
+
+	defer close(r.done)
+	return await()
+}
+
+// replayEvents loads the recording with the specified name from `recordings/events`, and replays it
+// with `r` while `await` runs.
+func replayEvents(r *replay, name string, await func() error) error {
+	return r.run(recordings.MustLoadEvents(filepath.Join("recordings", "events", name)), await)
+}
+
+// statusMessages returns the messages that were logged since the last call.
+func statusMessages(logger *logging.DedupLogger) []string {
+	var messages []string
+	for _, message := range logger.GetNewMessages() {
+		messages = append(messages, message.S)
+	}
+	return messages
+}
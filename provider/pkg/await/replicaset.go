@@ -0,0 +1,57 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package await
+
+import (
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/states"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ------------------------------------------------------------------------------------------------
+
+// Await logic for extensions/v1beta1/ReplicaSet, apps/v1beta2/ReplicaSet, and apps/v1/ReplicaSet.
+//
+// ReplicaSets are usually managed by a Deployment, but may also be created directly. The success
+// conditions are expressed by `states.NewReplicaSetChecker`:
+//
+//   1. `.status.observedGeneration` must be at least `.metadata.generation`.
+//   2. `.status.availableReplicas` must be at least `.spec.replicas`.
+//
+// If the ReplicaSet controller fails to create Pods (e.g., because a ResourceQuota is exceeded), the
+// `ReplicaFailure` condition is reported as a warning, and included in the error if the await fails.
+//
+// The event loop is implemented by the generic `stateAwaiter`, which also monitors the Pods owned
+// by the ReplicaSet and reports any warnings/errors they produce.
+//
+// x-refs:
+//   * https://kubernetes.io/docs/concepts/workloads/controllers/replicaset/
+
+// ------------------------------------------------------------------------------------------------
+
+const (
+	DefaultReplicaSetTimeoutMins = 10
+)
+
+func makeReplicaSetInitAwaiter(c updateAwaitConfig) *stateAwaiter {
+	podOwner := ResourceID{
+		Name:      c.currentInputs.GetName(),
+		Namespace: clients.NamespaceOrDefault(c.currentInputs.GetNamespace()),
+		GVK: schema.FromAPIVersionAndKind(
+			canonicalizeReplicaSetAPIVersion(c.currentInputs.GetAPIVersion()), string(kinds.ReplicaSet)),
+	}
+	return makeStateAwaiter(c, kinds.ReplicaSet, states.NewReplicaSetChecker, DefaultReplicaSetTimeoutMins*60, &podOwner)
+}
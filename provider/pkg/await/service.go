@@ -2,7 +2,6 @@ package await
 
 import (
 	"context"
-	"fmt"
 	"reflect"
 	"time"
 
@@ -14,13 +13,12 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/states"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/cluster"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/openapi"
-	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
-	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
 	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
 )
 
@@ -71,10 +69,14 @@ const (
 type serviceInitAwaiter struct {
 	config           createAwaitConfig
 	service          *unstructured.Unstructured
-	serviceReady     bool
+	serviceExists    bool
 	endpointsReady   bool
 	endpointsSettled bool
 	serviceType      string
+
+	// checker holds the readiness Conditions of the Service, evaluated against the last known version
+	// of the Service and the state of its Endpoints.
+	checker *states.StateChecker
 }
 
 func makeServiceInitAwaiter(c createAwaitConfig) *serviceInitAwaiter {
@@ -90,10 +92,11 @@ func makeServiceInitAwaiter(c createAwaitConfig) *serviceInitAwaiter {
 	return &serviceInitAwaiter{
 		config:           c,
 		service:          c.currentOutputs,
-		serviceReady:     false,
+		serviceExists:    false,
 		endpointsReady:   false,
 		endpointsSettled: false,
 		serviceType:      t,
+		checker:          states.NewServiceChecker(),
 	}
 }
 
@@ -120,13 +123,11 @@ func (sia *serviceInitAwaiter) Await() error {
 	//   4. External IP address is allocated (if we're type `LoadBalancer`).
 	//
 
-	serviceClient, endpointsClient, err := sia.makeClients()
-	if err != nil {
-		return err
-	}
+	namespace := sia.config.currentOutputs.GetNamespace()
+	name := sia.config.currentOutputs.GetName()
 
 	// Create service watcher.
-	serviceWatcher, err := serviceClient.Watch(context.TODO(), metav1.ListOptions{})
+	serviceWatcher, err := sia.config.watchKind(kinds.Service, namespace, clients.ObjectNamed(name))
 	if err != nil {
 		return errors.Wrapf(err, "Could set up watch for Service object '%s'",
 			sia.config.currentInputs.GetName())
@@ -134,7 +135,8 @@ func (sia *serviceInitAwaiter) Await() error {
 	defer serviceWatcher.Stop()
 
 	// Create endpoint watcher.
-	endpointWatcher, err := endpointsClient.Watch(context.TODO(), metav1.ListOptions{})
+	// NOTE: The Endpoints object created by the service has the same name as the service.
+	endpointWatcher, err := sia.config.watchKind(kinds.Endpoints, namespace, clients.ObjectNamed(name))
 	if err != nil {
 		return errors.Wrapf(err,
 			"Could not create watcher for Endpoint objects associated with Service %q",
@@ -144,7 +146,8 @@ func (sia *serviceInitAwaiter) Await() error {
 
 	version := cluster.TryGetServerVersion(sia.config.clientSet.DiscoveryClientCached)
 
-	timeout := metadata.TimeoutDuration(sia.config.timeout, sia.config.currentInputs, DefaultServiceTimeoutMins*60)
+	timeout := metadata.TimeoutDuration(sia.config.timeout, sia.config.defaultTimeouts,
+		sia.config.currentInputs, DefaultServiceTimeoutMins*60)
 	return sia.await(serviceWatcher, endpointWatcher, time.After(timeout), make(chan struct{}), version)
 }
 
@@ -221,42 +224,28 @@ func (sia *serviceInitAwaiter) await(
 	settled chan struct{},
 	version cluster.ServerVersion,
 ) error {
-	sia.config.logStatus(diag.Info, "[1/3] Finding Pods to direct traffic to")
-
-	for {
-		// Check whether we've succeeded.
-		if sia.checkAndLogStatus(version) {
-			return nil
-		}
-
-		// Else, wait for updates.
-		select {
-		case <-sia.config.ctx.Done():
-			// On cancel, check one last time if the service is ready.
-			if sia.serviceReady && sia.endpointsReady {
-				return nil
-			}
-			return &cancellationError{
-				object:    sia.service,
-				subErrors: sia.errorMessages(),
-			}
-		case <-timeout:
-			// On timeout, check one last time if the service is ready.
-			if sia.serviceReady && sia.endpointsReady {
-				return nil
-			}
-			return &timeoutError{
-				object:    sia.service,
-				subErrors: sia.errorMessages(),
-			}
-		case <-settled:
+	loop := awaitLoop{
+		ctx: sia.config.ctx,
+		ready: func() bool {
+			return sia.checkAndLogStatus(version)
+		},
+		// On timeout or cancellation, check one last time if the service is ready, without waiting
+		// for the Endpoints to settle.
+		lastChance: func() bool {
 			sia.endpointsSettled = true
-		case event := <-serviceWatcher.ResultChan():
-			sia.processServiceEvent(event)
-		case event := <-endpointWatcher.ResultChan():
-			sia.processEndpointEvent(event, settled)
-		}
+			return sia.checkAndLogStatus(version)
+		},
+		object:        func() *unstructured.Unstructured { return sia.service },
+		errorMessages: sia.errorMessages,
 	}
+	return loop.run(timeout,
+		signals(settled, func() {
+			sia.endpointsSettled = true
+		}),
+		watchEvents(serviceWatcher, sia.processServiceEvent),
+		watchEvents(endpointWatcher, func(event watch.Event) {
+			sia.processEndpointEvent(event, settled)
+		}))
 }
 
 func (sia *serviceInitAwaiter) processServiceEvent(event watch.Event) {
@@ -274,33 +263,16 @@ func (sia *serviceInitAwaiter) processServiceEvent(event watch.Event) {
 		return
 	}
 
-	// Start with a blank slate.
-	sia.serviceReady = false
-
 	// Mark the service as not ready if it's deleted.
 	if event.Type == watch.Deleted {
+		sia.serviceExists = false
 		return
 	}
 
 	sia.service = service
+	sia.serviceExists = true
 
-	if sia.serviceType == string(v1.ServiceTypeLoadBalancer) {
-		// If it's type `LoadBalancer`, check whether an IP was allocated.
-		lbIngress, _ := openapi.Pluck(service.Object, "status", "loadBalancer", "ingress")
-		status, _ := openapi.Pluck(service.Object, "status")
-
-		logger.V(3).Infof("Received status for service %q: %#v", inputServiceName, status)
-		ing, isSlice := lbIngress.([]interface{})
-
-		// Update status of service object so that we can check success.
-		sia.serviceReady = isSlice && len(ing) > 0
-
-		logger.V(3).Infof("Waiting for service %q to assign IP/hostname for a load balancer",
-			inputServiceName)
-	} else {
-		// If it's not type `LoadBalancer`, report success.
-		sia.serviceReady = true
-	}
+	logger.V(3).Infof("Received status for service %q: %#v", inputServiceName, service.Object["status"])
 }
 
 func (sia *serviceInitAwaiter) processEndpointEvent(event watch.Event, settledCh chan<- struct{}) {
@@ -346,23 +318,7 @@ func (sia *serviceInitAwaiter) processEndpointEvent(event watch.Event, settledCh
 }
 
 func (sia *serviceInitAwaiter) errorMessages() []string {
-	messages := make([]string, 0)
-	if sia.emptyHeadlessOrExternalName() {
-		return messages
-	}
-
-	if !sia.endpointsReady {
-		messages = append(messages,
-			"Service does not target any Pods. Selected Pods may not be ready, or "+
-				"field '.spec.selector' may not match labels on any Pods")
-	}
-
-	if sia.serviceType == string(v1.ServiceTypeLoadBalancer) && !sia.serviceReady {
-		messages = append(messages,
-			"Service was not allocated an IP address; does your cloud provider support this?")
-	}
-
-	return messages
+	return sia.checker.Failures()
 }
 
 // isHeadlessService checks if the Service has a defined .spec.clusterIP
@@ -421,28 +377,33 @@ func (sia *serviceInitAwaiter) hasHeadlessServicePortBug(version cluster.ServerV
 // shouldWaitForPods determines whether to wait for Pods to be ready before marking the Service ready.
 func (sia *serviceInitAwaiter) shouldWaitForPods(version cluster.ServerVersion) bool {
 	// For these special cases, skip the wait for Pod logic.
-	if sia.emptyHeadlessOrExternalName() || sia.hasHeadlessServicePortBug(version) {
-		sia.endpointsReady = true
-		return false
-	}
-
-	return true
+	return !sia.emptyHeadlessOrExternalName() && !sia.hasHeadlessServicePortBug(version)
 }
 
+// checkAndLogStatus evaluates the Service readiness Conditions against the last known version of the
+// Service and the state of its Endpoints, and returns true if the live Service is ready.
 func (sia *serviceInitAwaiter) checkAndLogStatus(version cluster.ServerVersion) bool {
-	if !sia.shouldWaitForPods(version) {
-		return sia.serviceReady
+	obj, err := clients.FromUnstructured(sia.service)
+	if err != nil {
+		logger.V(3).Infof("Failed to unmarshal Service %q: %v", sia.service.GetName(), err)
+		return false
 	}
 
-	success := sia.serviceReady && sia.endpointsSettled && sia.endpointsReady
-	if success {
-		sia.config.logStatus(diag.Info,
-			fmt.Sprintf("%sService initialization complete", cmdutil.EmojiOr("✅ ", "")))
-	} else if sia.endpointsSettled && sia.endpointsReady {
-		sia.config.logStatus(diag.Info, "[2/3] Attempting to allocate IP address to Service")
-	}
+	messages := sia.checker.Update(&states.ServiceState{
+		Service:          obj.(*v1.Service),
+		EndpointsReady:   sia.endpointsReady,
+		EndpointsSettled: sia.endpointsSettled,
+		SkipEndpoints:    !sia.shouldWaitForPods(version),
+	})
 
-	return success
+	// Readiness is only determined by the live object.
+	if !sia.serviceExists {
+		return false
+	}
+	for _, message := range messages {
+		sia.config.logMessage(message)
+	}
+	return sia.checker.Ready()
 }
 
 func (sia *serviceInitAwaiter) makeClients() (
@@ -90,6 +90,19 @@ func Test_Core_Service(t *testing.T) {
 					uninitializedEndpoint("default", "foo-4setj4y6"))
 			},
 		},
+		{
+			description:  "Should fail if Service is deleted before Endpoints settle",
+			serviceInput: serviceInput,
+			do: func(services, endpoints chan watch.Event, settled chan struct{}, timeout chan time.Time) {
+				services <- watchAddedEvent(initializedService("default", "foo-4setj4y6"))
+				endpoints <- watchAddedEvent(initializedEndpoint("default", "foo-4setj4y6"))
+				services <- watch.Event{Type: watch.Deleted, Object: initializedService("default", "foo-4setj4y6")}
+
+				settled <- struct{}{}
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{object: initializedService("default", "foo-4setj4y6")},
+		},
 		{
 			description:  "Should fail if neither the Service nor the Endpoints have initialized",
 			serviceInput: serviceInput,
@@ -0,0 +1,236 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package await
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/states"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ------------------------------------------------------------------------------------------------
+
+// Generic await logic for resources whose readiness is expressed as a `states.StateChecker`.
+//
+// The checker's Conditions are evaluated every time the object changes, and the awaiter succeeds
+// once they are all true. If the await fails, the Failure of every Condition that is still false is
+// reported, along with any warnings or errors reported by the Pods the object owns.
+//
+// The event loop depends on the following channels:
+//
+//   1. The object channel, to which the Kubernetes API server will push every change to the object.
+//   2. The PodAggregator channel, which monitors Pods owned by the object (if any), and reports any
+//      warnings/errors produced by those Pods.
+//   3. A timeout channel, which fires after some minutes.
+//   4. A cancellation channel, with which the user can signal cancellation (e.g., using SIGINT).
+
+// ------------------------------------------------------------------------------------------------
+
+type stateAwaiter struct {
+	config         updateAwaitConfig
+	kind           kinds.Kind
+	newChecker     func() *states.StateChecker
+	timeoutSeconds int
+
+	// podOwner identifies the owner of the Pods to monitor. Pods are not monitored if it is nil.
+	podOwner *ResourceID
+
+	checker   *states.StateChecker
+	object    *unstructured.Unstructured
+	deleted   bool
+	podErrors logging.TimeOrderedLogSet
+}
+
+func makeStateAwaiter(
+	c updateAwaitConfig, kind kinds.Kind, newChecker func() *states.StateChecker, timeoutSeconds int,
+	podOwner *ResourceID,
+) *stateAwaiter {
+	return &stateAwaiter{
+		config:         c,
+		kind:           kind,
+		newChecker:     newChecker,
+		timeoutSeconds: timeoutSeconds,
+		podOwner:       podOwner,
+
+		checker: newChecker(),
+		object:  c.currentOutputs,
+	}
+}
+
+// Await blocks until every Condition of the checker is true, or the await times out or is cancelled.
+func (sa *stateAwaiter) Await() error {
+	objWatcher, err := sa.config.watchKind(sa.kind, sa.config.currentInputs.GetNamespace(),
+		clients.ObjectNamed(sa.config.currentInputs.GetName()))
+	if err != nil {
+		return errors.Wrapf(err, "Could not set up watch for %s object %q",
+			sa.kind, sa.config.currentInputs.GetName())
+	}
+	defer objWatcher.Stop()
+
+	var podMessages <-chan logging.Messages
+	if sa.podOwner != nil {
+		podWatcher, err := sa.config.watchKind(kinds.Pod, sa.podOwner.Namespace, nil)
+		if err != nil {
+			return errors.Wrapf(err, "Could not create watcher for Pods associated with %s %q",
+				sa.kind, sa.config.currentInputs.GetName())
+		}
+		podAggregator, err := NewPodAggregator(*sa.podOwner, sa.config.clientSet, podWatcher)
+		if err != nil {
+			podWatcher.Stop()
+			return errors.Wrapf(err, "Could not create PodAggregator for %s", sa.podOwner.GVKString())
+		}
+		defer podAggregator.Stop()
+		podMessages = podAggregator.ResultChan()
+	}
+
+	timeout := metadata.TimeoutDuration(sa.config.timeout, sa.config.defaultTimeouts,
+		sa.config.currentInputs, sa.timeoutSeconds)
+	return sa.await(objWatcher, podMessages, time.After(timeout))
+}
+
+// Read checks whether the live object is ready, without waiting.
+func (sa *stateAwaiter) Read() error {
+	client, err := clients.ResourceClient(sa.kind, sa.config.currentInputs.GetNamespace(), sa.config.clientSet)
+	if err != nil {
+		return errors.Wrapf(err, "Could not make client to get %s %q",
+			sa.kind, sa.config.currentInputs.GetName())
+	}
+
+	// Get live version of the object.
+	obj, err := client.Get(context.TODO(), sa.config.currentInputs.GetName(), metav1.GetOptions{})
+	if err != nil {
+		// IMPORTANT: Do not wrap this error! If this is a 404, the provider need to know so that it
+		// can mark the object as having been deleted.
+		return err
+	}
+
+	//
+	// In contrast to the case of the object itself, an error getting the related Pods does not
+	// indicate that this resource was deleted, so report any Pod errors we're able to find and move on.
+	//
+
+	var podMessages logging.Messages
+	if sa.podOwner != nil {
+		podAggregator, err := NewPodAggregator(*sa.podOwner, sa.config.clientSet, nil)
+		if err != nil {
+			logger.V(3).Infof("Error creating PodAggregator for %s %q: %v", sa.kind, obj.GetName(), err)
+		} else {
+			podMessages = podAggregator.Read()
+			podAggregator.Stop()
+		}
+	}
+
+	return sa.read(obj, podMessages)
+}
+
+// read is a helper companion to `Read` designed to make it easy to test this module.
+func (sa *stateAwaiter) read(obj *unstructured.Unstructured, podMessages logging.Messages) error {
+	sa.processObjectEvent(watchAddedEvent(obj))
+	sa.processPodMessages(podMessages)
+
+	if sa.checker.Ready() {
+		return nil
+	}
+
+	return &initializationError{
+		subErrors: sa.errorMessages(),
+		object:    obj,
+	}
+}
+
+// await is a helper companion to `Await` designed to make it easy to test this module.
+func (sa *stateAwaiter) await(
+	objWatcher watch.Interface, podMessages <-chan logging.Messages, timeout <-chan time.Time,
+) error {
+	loop := awaitLoop{
+		ctx:           sa.config.ctx,
+		ready:         sa.checker.Ready,
+		object:        func() *unstructured.Unstructured { return sa.object },
+		errorMessages: sa.errorMessages,
+	}
+	return loop.run(timeout,
+		watchEvents(objWatcher, sa.processObjectEvent),
+		aggregatorMessages(podMessages, sa.processPodMessages))
+}
+
+func (sa *stateAwaiter) processObjectEvent(event watch.Event) {
+	obj, isUnstructured := event.Object.(*unstructured.Unstructured)
+	if !isUnstructured {
+		logger.V(3).Infof("%s watch received unknown object type %q", sa.kind, reflect.TypeOf(event.Object))
+		return
+	}
+
+	// Do nothing if this is not the object we're waiting for.
+	if obj.GetName() != sa.config.currentInputs.GetName() {
+		return
+	}
+
+	// Start over if the object is deleted. The last known version is kept for error reporting.
+	sa.deleted = event.Type == watch.Deleted
+	if sa.deleted {
+		sa.checker = sa.newChecker()
+		return
+	}
+
+	sa.object = obj
+	typed, err := clients.FromUnstructured(obj)
+	if err != nil {
+		logger.V(3).Infof("Failed to unmarshal %s event: %v", sa.kind, err)
+		return
+	}
+
+	for _, message := range sa.checker.Update(typed) {
+		sa.config.logMessage(message)
+	}
+}
+
+func (sa *stateAwaiter) processPodMessages(messages logging.Messages) {
+	for _, message := range messages {
+		sa.podErrors.Add(message)
+
+		// Unready Pods are a normal part of a rollout, so don't print this as a warning. If the rollout
+		// fails to complete, this warning will be included in the subErrors.
+		if strings.Contains(message.S, "containers with unready status") {
+			continue
+		}
+		sa.config.logMessage(message)
+	}
+}
+
+func (sa *stateAwaiter) errorMessages() []string {
+	messages := make([]string, 0)
+	if sa.deleted {
+		messages = append(messages, fmt.Sprintf("%s %q was deleted", sa.kind, sa.config.currentInputs.GetName()))
+	}
+	messages = append(messages, sa.checker.Failures()...)
+	for _, message := range sa.podErrors.Messages {
+		messages = append(messages, message.S)
+	}
+
+	return messages
+}
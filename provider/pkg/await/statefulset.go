@@ -2,20 +2,15 @@ package await
 
 import (
 	"context"
-	"fmt"
 	"reflect"
 	"time"
 
-	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
-
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/states"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
 	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
-	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/openapi"
-	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
 	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -39,12 +34,22 @@ import (
 // the user). When the new application Pods becomes "live" (as specified by the liveness and
 // readiness probes), the old Pods are killed and deleted.
 //
-// The success conditions are somewhat complex:
+// The success conditions are somewhat complex, and are expressed by `states.NewStatefulSetChecker`:
 //
 //   1. `.status.replicas`, `.status.currentReplicas` and `.status.readyReplicas` match the
 //      value of `.spec.replicas`.
 //   2. `.status.updateRevision` matches `.status.currentRevision`.
 //
+// Staged updates are handled differently:
+//
+//   * With a partitioned `RollingUpdate` (`.spec.updateStrategy.rollingUpdate.partition` > 0), only
+//     the Pods with an ordinal greater than or equal to the partition are updated, so the update is
+//     complete once every replica is ready and `.status.updatedReplicas` reaches the number of Pods
+//     at or above the partition.
+//   * With the `OnDelete` strategy, Pods are only updated when they are deleted, so an update is
+//     complete once the controller observes the new spec (`.status.observedGeneration`). The number
+//     of Pods still running the previous revision is reported.
+//
 // ------
 // The following table illustrates the timeline of status updates:
 //
@@ -106,27 +111,17 @@ const (
 )
 
 type statefulsetInitAwaiter struct {
-	config            updateAwaitConfig
-	revisionReady     bool
-	replicasReady     bool
-	currentGeneration int64
-
-	statefulset     *unstructured.Unstructured
-	pods            map[string]*unstructured.Unstructured
-	currentReplicas int64
-	targetReplicas  int64
-	currentRevision string
-	targetRevision  string
+	config  updateAwaitConfig
+	checker *states.StateChecker
+
+	statefulset *unstructured.Unstructured
+	pods        map[string]*unstructured.Unstructured
 }
 
 func makeStatefulSetInitAwaiter(c updateAwaitConfig) *statefulsetInitAwaiter {
 	return &statefulsetInitAwaiter{
-		config:        c,
-		revisionReady: false,
-		replicasReady: false,
-
-		// NOTE: Generation 0 is invalid, so this is a good sentinel value.
-		currentGeneration: 0,
+		config:  c,
+		checker: states.NewStatefulSetChecker(),
 
 		statefulset: c.currentOutputs,
 		pods:        map[string]*unstructured.Unstructured{},
@@ -141,14 +136,11 @@ func makeStatefulSetInitAwaiter(c updateAwaitConfig) *statefulsetInitAwaiter {
 //      and `.status.readyReplicas`.
 //   2. The value of `.status.updateRevision` matches `.status.currentRevision`.
 func (sia *statefulsetInitAwaiter) Await() error {
+	namespace := sia.config.currentInputs.GetNamespace()
 
-	statefulSetClient, podClient, err := sia.makeClients()
-	if err != nil {
-		return err
-	}
-
-	// Create Deployment watcher.
-	statefulSetWatcher, err := statefulSetClient.Watch(context.TODO(), metav1.ListOptions{})
+	// Create StatefulSet watcher.
+	statefulSetWatcher, err := sia.config.watchKind(kinds.StatefulSet, namespace,
+		clients.ObjectNamed(sia.config.currentInputs.GetName()))
 	if err != nil {
 		return errors.Wrapf(err, "Could not set up watch for StatefulSet object %q",
 			sia.config.currentInputs.GetName())
@@ -156,7 +148,7 @@ func (sia *statefulsetInitAwaiter) Await() error {
 	defer statefulSetWatcher.Stop()
 
 	// Create Pod watcher.
-	podWatcher, err := podClient.Watch(context.TODO(), metav1.ListOptions{})
+	podWatcher, err := sia.config.watchKind(kinds.Pod, namespace, nil)
 	if err != nil {
 		return errors.Wrapf(err,
 			"Could not create watcher for Pods objects associated with StatefulSet %q",
@@ -167,7 +159,8 @@ func (sia *statefulsetInitAwaiter) Await() error {
 	aggregateErrorTicker := time.NewTicker(10 * time.Second)
 	defer aggregateErrorTicker.Stop()
 
-	timeout := metadata.TimeoutDuration(sia.config.timeout, sia.config.currentInputs, DefaultStatefulSetTimeoutMins*60)
+	timeout := metadata.TimeoutDuration(sia.config.timeout, sia.config.defaultTimeouts,
+		sia.config.currentInputs, DefaultStatefulSetTimeoutMins*60)
 	return sia.await(statefulSetWatcher, podWatcher, time.After(timeout), aggregateErrorTicker.C)
 }
 
@@ -219,7 +212,7 @@ func (sia *statefulsetInitAwaiter) read(
 			statefulset.GetName(), err)
 	}
 
-	if sia.checkAndLogStatus() {
+	if sia.checker.Ready() {
 		return nil
 	}
 
@@ -234,63 +227,21 @@ func (sia *statefulsetInitAwaiter) await(
 	statefulsetWatcher, podWatcher watch.Interface,
 	timeout, aggregateErrorTicker <-chan time.Time,
 ) error {
-	for {
-		if sia.checkAndLogStatus() {
-			return nil
-		}
-
-		// Else, wait for updates.
-		select {
-		case <-sia.config.ctx.Done():
-			return &cancellationError{
-				object:    sia.statefulset,
-				subErrors: sia.errorMessages(),
-			}
-		case <-timeout:
-			return &timeoutError{
-				object:    sia.statefulset,
-				subErrors: sia.errorMessages(),
-			}
-		case <-aggregateErrorTicker:
+	loop := awaitLoop{
+		ctx:           sia.config.ctx,
+		ready:         sia.checker.Ready,
+		object:        func() *unstructured.Unstructured { return sia.statefulset },
+		errorMessages: sia.errorMessages,
+	}
+	return loop.run(timeout,
+		ticks(aggregateErrorTicker, func() {
 			messages := sia.aggregatePodErrors()
 			for _, message := range messages {
 				sia.config.logMessage(message)
 			}
-		case event := <-statefulsetWatcher.ResultChan():
-			sia.processStatefulSetEvent(event)
-		case event := <-podWatcher.ResultChan():
-			sia.processPodEvent(event)
-		}
-	}
-}
-
-// checkAndLogStatus checks whether we've succeeded, and logs the result as a status message to
-// the provider.
-func (sia *statefulsetInitAwaiter) checkAndLogStatus() bool {
-	if sia.replicasReady && sia.revisionReady {
-		sia.config.logStatus(diag.Info,
-			fmt.Sprintf("%sStatefulSet initialization complete", cmdutil.EmojiOr("✅ ", "")))
-		return true
-	}
-
-	isInitialDeployment := sia.currentGeneration <= 1
-
-	// For initial generation, the revision doesn't need to be updated, so skip that step in the log.
-	if isInitialDeployment {
-		sia.config.logStatus(diag.Info, fmt.Sprintf("[1/2] Waiting for StatefulSet to create Pods (%d/%d Pods ready)",
-			sia.currentReplicas, sia.targetReplicas))
-	} else {
-		switch {
-		case !sia.replicasReady:
-			sia.config.logStatus(diag.Info, fmt.Sprintf("[1/3] Waiting for StatefulSet update to roll out (%d/%d Pods ready)",
-				sia.currentReplicas, sia.targetReplicas))
-		case !sia.revisionReady:
-			sia.config.logStatus(diag.Info,
-				"[2/3] Waiting for StatefulSet to update .status.currentRevision")
-		}
-	}
-
-	return false
+		}),
+		watchEvents(statefulsetWatcher, sia.processStatefulSetEvent),
+		watchEvents(podWatcher, sia.processPodEvent))
 }
 
 func (sia *statefulsetInitAwaiter) processStatefulSetEvent(event watch.Event) {
@@ -303,79 +254,26 @@ func (sia *statefulsetInitAwaiter) processStatefulSetEvent(event watch.Event) {
 		return
 	}
 
-	// Start over, prove that rollout is complete.
-	sia.revisionReady = false
-	sia.replicasReady = false
-
 	// Do nothing if this is not the StatefulSet we're waiting for.
 	if statefulset.GetName() != inputStatefulSetName {
 		return
 	}
 
-	// Mark the rollout as incomplete if it's deleted.
+	// Start over if the StatefulSet is deleted.
 	if event.Type == watch.Deleted {
+		sia.checker = states.NewStatefulSetChecker()
 		return
 	}
 
 	sia.statefulset = statefulset
 
-	// Get current generation of the StatefulSet.
-	sia.currentGeneration = statefulset.GetGeneration()
-
-	if sia.currentGeneration == 0 {
-		// No current generation, StatefulSet controller has not yet created a StatefulSet.
-		// Do nothing.
+	obj, err := clients.FromUnstructured(statefulset)
+	if err != nil {
+		logger.V(3).Infof("Failed to unmarshal StatefulSet event: %v", err)
 		return
 	}
-
-	// Check if current revision matches update revision.
-	var currentRevision, updateRevision string
-	if rawCurrentRevision, ok := openapi.Pluck(statefulset.Object, "status", "currentRevision"); ok {
-		currentRevision = rawCurrentRevision.(string)
-	}
-	if rawUpdateRevision, ok := openapi.Pluck(statefulset.Object, "status", "updateRevision"); ok {
-		updateRevision = rawUpdateRevision.(string)
-	}
-	sia.revisionReady = (currentRevision != "") && (currentRevision == updateRevision)
-	sia.currentRevision, sia.targetRevision = currentRevision, updateRevision
-
-	// Check if all expected replicas are ready.
-	var replicas, statusReplicas, statusReadyReplicas, statusCurrentReplicas, statusUpdatedReplicas int64
-	if rawReplicas, ok := openapi.Pluck(statefulset.Object, "spec", "replicas"); ok {
-		replicas = rawReplicas.(int64)
-	}
-	if rawStatusReplicas, ok := openapi.Pluck(statefulset.Object, "status", "replicas"); ok {
-		statusReplicas = rawStatusReplicas.(int64)
-	}
-	if rawStatusReadyReplicas, ok := openapi.Pluck(statefulset.Object, "status", "readyReplicas"); ok {
-		statusReadyReplicas = rawStatusReadyReplicas.(int64)
-	}
-	if rawStatusCurrentReplicas, ok := openapi.Pluck(statefulset.Object, "status", "currentReplicas"); ok {
-		statusCurrentReplicas = rawStatusCurrentReplicas.(int64)
-	}
-	if rawStatusUpdatedReplicas, ok := openapi.Pluck(statefulset.Object, "status", "updatedReplicas"); ok {
-		statusUpdatedReplicas = rawStatusUpdatedReplicas.(int64)
-	} else {
-		statusUpdatedReplicas = 0
-	}
-	sia.replicasReady = (replicas == statusReplicas) &&
-		(replicas == statusReadyReplicas) &&
-		(replicas == statusCurrentReplicas)
-
-	// Set current and target replica counts for logging purposes.
-	sia.targetReplicas = replicas
-	// For the initial rollout, .status.readyReplicas is an accurate gauge of progress.
-	// For subsequent updates, we must also account for .status.updatedReplicas.
-	if sia.revisionReady {
-		sia.currentReplicas = statusReadyReplicas
-	} else {
-		// During a rollout, the number of "ready" replicas can include instances of the previous revision,
-		// so don't count those towards the target count.
-		if statusReadyReplicas > statusUpdatedReplicas {
-			sia.currentReplicas = statusUpdatedReplicas
-		} else {
-			sia.currentReplicas = statusReadyReplicas
-		}
+	for _, message := range sia.checker.Update(obj) {
+		sia.config.logMessage(message)
 	}
 }
 
@@ -425,17 +323,7 @@ func (sia *statefulsetInitAwaiter) aggregatePodErrors() logging.Messages {
 
 func (sia *statefulsetInitAwaiter) errorMessages() []string {
 	messages := make([]string, 0)
-
-	if !sia.replicasReady {
-		messages = append(messages,
-			fmt.Sprintf(
-				"%v out of %v replicas succeeded readiness checks", sia.currentReplicas, sia.targetReplicas))
-	}
-	if !sia.revisionReady {
-		messages = append(messages,
-			fmt.Sprintf("StatefulSet controller failed to advance from revision %q to revision %q",
-				sia.currentRevision, sia.targetRevision))
-	}
+	messages = append(messages, sia.checker.Failures()...)
 
 	errorMessages := sia.aggregatePodErrors()
 	for _, message := range errorMessages {
@@ -1564,3 +1564,25 @@ func statefulsetSucceedAfterFailed() *unstructured.Unstructured {
 	}
 	return obj
 }
+
+func Test_Apps_StatefulSet_Replay(t *testing.T) {
+	awaiter := makeStatefulSetInitAwaiter(
+		updateAwaitConfig{
+			createAwaitConfig: mockAwaitConfig(statefulsetInput("default", "foo", "foo")),
+		})
+
+	r := newReplay("StatefulSet", "Pod")
+	err := replayEvents(r, "statefulset/foo-update-20200514T181502.000Z.json", func() error {
+		return awaiter.await(r.watcher("StatefulSet"), r.watcher("Pod"), r.timeout, nil)
+	})
+	if assert.IsType(t, &timeoutError{}, err) {
+		assert.Equal(t, []string{
+			"1 out of 2 replicas succeeded readiness checks",
+			"StatefulSet controller failed to advance from revision \"foo-7b5cf87b78\" to revision \"foo-789c4b994f\"",
+		}, err.(*timeoutError).SubErrors())
+	}
+	assert.Equal(t, []string{
+		"[1/2] Waiting for StatefulSet \"foo\" to roll out (2/2 Pods ready)",
+		"[1/2] Waiting for StatefulSet \"foo\" to roll out (1/2 Pods ready)",
+	}, statusMessages(awaiter.config.logger))
+}
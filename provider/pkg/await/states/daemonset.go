@@ -0,0 +1,121 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func NewDaemonSetChecker() *StateChecker {
+	return &StateChecker{
+		conditions: []Condition{daemonSetGenerationObserved, daemonSetPodsUpdated, daemonSetPodsAvailable},
+		readyMsg:   cmdutil.EmojiOr("✅ DaemonSet initialization complete", "DaemonSet initialization complete"),
+	}
+}
+
+//
+// Conditions
+//
+
+func daemonSetGenerationObserved(obj metav1.Object) Result {
+	ds := toDaemonSet(obj)
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for DaemonSet controller to observe the new spec of %q", fqName(ds))}
+
+	if daemonSetObserved(ds) {
+		result.Ok = true
+	} else {
+		result.Failure = fmt.Sprintf("DaemonSet controller has not observed generation %d (observed generation: %d)",
+			ds.Generation, ds.Status.ObservedGeneration)
+	}
+
+	return result
+}
+
+func daemonSetPodsUpdated(obj metav1.Object) Result {
+	ds := toDaemonSet(obj)
+	desired, updated := ds.Status.DesiredNumberScheduled, ds.Status.UpdatedNumberScheduled
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for DaemonSet %q to update Pods (%d/%d Pods updated)", fqName(ds), updated, desired)}
+
+	switch {
+	case !daemonSetObserved(ds):
+		// The status is stale until the controller observes the current spec.
+	case daemonSetOnDeleteUpdate(ds):
+		result.Ok = true
+		if updated < desired {
+			result.Message = logging.StatusMessage(fmt.Sprintf(
+				"DaemonSet uses the OnDelete update strategy; %d/%d Pods are still running the previous "+
+					"template and will be updated when they are deleted", desired-updated, desired))
+		}
+	case updated >= desired:
+		result.Ok = true
+	default:
+		result.Failure = fmt.Sprintf("%d out of %d Pods were updated to the current template", updated, desired)
+	}
+
+	return result
+}
+
+func daemonSetPodsAvailable(obj metav1.Object) Result {
+	ds := toDaemonSet(obj)
+	desired, available := ds.Status.DesiredNumberScheduled, ds.Status.NumberAvailable
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for DaemonSet %q Pods to become available (%d/%d Pods available)", fqName(ds), available, desired)}
+
+	switch {
+	case !daemonSetObserved(ds):
+		// The status is stale until the controller observes the current spec.
+	case daemonSetOnDeleteUpdate(ds):
+		result.Ok = true
+	case available >= desired:
+		result.Ok = true
+		if desired == 0 {
+			result.Message = logging.WarningMessage(fmt.Sprintf(
+				"DaemonSet %q does not match any Nodes, so no Pods were scheduled", fqName(ds)))
+		}
+	default:
+		result.Failure = fmt.Sprintf("%d out of %d Pods succeeded readiness checks", available, desired)
+	}
+
+	return result
+}
+
+//
+// Helpers
+//
+
+func toDaemonSet(obj metav1.Object) *appsv1.DaemonSet {
+	return obj.(*appsv1.DaemonSet)
+}
+
+// daemonSetObserved returns true if the DaemonSet controller has observed the current spec. The status fields are
+// only meaningful once it has.
+func daemonSetObserved(ds *appsv1.DaemonSet) bool {
+	return ds.Generation != 0 && ds.Status.ObservedGeneration >= ds.Generation
+}
+
+// daemonSetOnDeleteUpdate returns true if the DaemonSet is being updated with the OnDelete strategy. With this
+// strategy, the controller only creates Pods from the new template for Nodes that are missing one, so an update is
+// complete as soon as the new spec has been observed. On initial creation, every Pod is created from the current
+// template, so the rollout is awaited as usual.
+func daemonSetOnDeleteUpdate(ds *appsv1.DaemonSet) bool {
+	return ds.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType && ds.Generation > 1
+}
@@ -0,0 +1,230 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/recordings"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_daemonSetGenerationObserved(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"DaemonSet added",
+			args{daemonSetAddedState()},
+			false,
+		},
+		{
+			"DaemonSet rolling out",
+			args{daemonSetRollingOutState()},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daemonSetGenerationObserved(tt.args.obj); got.Ok != tt.want {
+				t.Errorf("daemonSetGenerationObserved() = %v, want %v", got.Ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_daemonSetPodsUpdated(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"DaemonSet added",
+			args{daemonSetAddedState()},
+			false,
+		},
+		{
+			"DaemonSet updating",
+			args{daemonSetUpdatingState()},
+			false,
+		},
+		{
+			"DaemonSet with OnDelete strategy updated",
+			args{daemonSetOnDeleteUpdatedState()},
+			true,
+		},
+		{
+			"DaemonSet ready",
+			args{daemonSetReadyState()},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daemonSetPodsUpdated(tt.args.obj); got.Ok != tt.want {
+				t.Errorf("daemonSetPodsUpdated() = %v, want %v", got.Ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_daemonSetPodsAvailable(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name        string
+		args        args
+		want        bool
+		wantMessage bool
+	}{
+		{
+			"DaemonSet rolling out",
+			args{daemonSetRollingOutState()},
+			false,
+			false,
+		},
+		{
+			"DaemonSet ready",
+			args{daemonSetReadyState()},
+			true,
+			false,
+		},
+		{
+			"DaemonSet matches no Nodes",
+			args{daemonSetNoNodesState()},
+			true,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := daemonSetPodsAvailable(tt.args.obj)
+			if got.Ok != tt.want {
+				t.Errorf("daemonSetPodsAvailable() = %v, want %v", got.Ok, tt.want)
+			}
+			if got.Message.Empty() == tt.wantMessage {
+				t.Errorf("daemonSetPodsAvailable() message = %q, want message: %t", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func Test_DaemonSet_Checker(t *testing.T) {
+	workflow := func(name string) string {
+		return workflowPath("daemonset", name)
+	}
+	const (
+		created       = "created"
+		createStalled = "createStalled"
+		updated       = "updated"
+		updateStalled = "updateStalled"
+	)
+
+	tests := []struct {
+		name           string
+		recordingPaths []string
+		expectReady    bool
+	}{
+		{
+			name:           "DaemonSet created",
+			recordingPaths: []string{workflow(created)},
+			expectReady:    true,
+		},
+		{
+			name:           "DaemonSet creation stalled",
+			recordingPaths: []string{workflow(createStalled)},
+			expectReady:    false,
+		},
+		{
+			name:           "DaemonSet updated",
+			recordingPaths: []string{workflow(updated)},
+			expectReady:    true,
+		},
+		{
+			name:           "DaemonSet update stalled",
+			recordingPaths: []string{workflow(updateStalled)},
+			expectReady:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewDaemonSetChecker()
+
+			ready, messages := mustCheckIfRecordingsReady(tt.recordingPaths, checker)
+			if ready != tt.expectReady {
+				t.Errorf("Ready() = %t, want %t\nMessages: %s", ready, tt.expectReady, messages)
+			}
+		})
+	}
+}
+
+//
+// Helpers
+//
+
+func daemonSetStatePath(name string) string {
+	return statePath("daemonset", name)
+}
+
+func mustLoadDaemonSetRecording(path string) *appsv1.DaemonSet {
+	obj, err := clients.FromUnstructured(recordings.MustLoadState(path))
+	if err != nil {
+		panic(err)
+	}
+	return obj.(*appsv1.DaemonSet)
+}
+
+// daemonSetAddedState returns a DaemonSet that has not been observed by the DaemonSet controller.
+func daemonSetAddedState() *appsv1.DaemonSet {
+	return mustLoadDaemonSetRecording(daemonSetStatePath("added"))
+}
+
+// daemonSetRollingOutState returns a DaemonSet whose Pods are not all available yet.
+func daemonSetRollingOutState() *appsv1.DaemonSet {
+	return mustLoadDaemonSetRecording(daemonSetStatePath("rollingOut"))
+}
+
+// daemonSetReadyState returns a DaemonSet that passes every await Condition.
+func daemonSetReadyState() *appsv1.DaemonSet {
+	return mustLoadDaemonSetRecording(daemonSetStatePath("ready"))
+}
+
+// daemonSetUpdatingState returns a DaemonSet whose Pods are being updated to a new template.
+func daemonSetUpdatingState() *appsv1.DaemonSet {
+	return mustLoadDaemonSetRecording(daemonSetStatePath("updating"))
+}
+
+// daemonSetOnDeleteUpdatedState returns an updated DaemonSet with the OnDelete strategy, whose Pods still run the
+// previous template.
+func daemonSetOnDeleteUpdatedState() *appsv1.DaemonSet {
+	return mustLoadDaemonSetRecording(daemonSetStatePath("onDeleteUpdated"))
+}
+
+// daemonSetNoNodesState returns a DaemonSet that does not match any Nodes.
+func daemonSetNoNodesState() *appsv1.DaemonSet {
+	return mustLoadDaemonSetRecording(daemonSetStatePath("noNodes"))
+}
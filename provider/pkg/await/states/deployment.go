@@ -0,0 +1,246 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// progressDeadlineExceeded is the reason set on the Progressing condition when a rollout stalls.
+const progressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+// DeploymentState is a Deployment along with what is known about the rollout of its current revision, which is
+// gathered from the conditions of the Deployment, its ReplicaSets and its PersistentVolumeClaims. The Deployment
+// checker expects its Conditions to be evaluated against a *DeploymentState.
+type DeploymentState struct {
+	*appsv1.Deployment
+
+	Rollout                bool     // True if the current revision replaces an earlier one, rather than being the first.
+	Available              bool     // True if the Deployment reports the Available condition.
+	NewReplicaSetAvailable bool     // True if the Deployment reports the ReplicaSet of the current revision available.
+	UpdatedReplicaSetReady bool     // True if the ReplicaSet of the current revision has its desired number of ready Pods.
+	ReadyReplicas          int64    // The number of ready Pods of the ReplicaSet of the current revision.
+	DesiredReplicas        int64    // The desired number of Pods of the ReplicaSet of the current revision.
+	UnboundClaims          []string // Names of the PersistentVolumeClaims used by the Deployment that are not Bound.
+}
+
+// NewDeploymentChecker returns a checker that decides whether the current revision of a Deployment has rolled out:
+// its PersistentVolumeClaims are bound, the Deployment is available, and the ReplicaSet of the current revision is
+// marked available by the Deployment controller and has its desired number of ready Pods.
+func NewDeploymentChecker() *StateChecker {
+	return &StateChecker{
+		conditions: []Condition{
+			deploymentClaimsBound,
+			deploymentAvailable,
+			deploymentNewReplicaSetAvailable,
+			deploymentUpdatedReplicaSetReady,
+		},
+		readyMsg: cmdutil.EmojiOr("✅ Deployment initialization complete", "Deployment initialization complete"),
+	}
+}
+
+// NewDeploymentReplicasChecker returns a checker that follows the semantics of `kubectl rollout status`: the
+// Deployment is ready once the controller has observed the current spec, every replica has been updated to the
+// current template, the old replicas have terminated, and every updated replica is available. It is used for
+// Deployments that do not report the Progressing condition.
+func NewDeploymentReplicasChecker() *StateChecker {
+	return &StateChecker{
+		conditions: []Condition{
+			deploymentGenerationObserved,
+			deploymentReplicasUpdated,
+			deploymentOldReplicasTerminated,
+			deploymentReplicasAvailable,
+		},
+		readyMsg: cmdutil.EmojiOr("✅ Deployment initialization complete", "Deployment initialization complete"),
+	}
+}
+
+//
+// Conditions
+//
+
+func deploymentClaimsBound(obj metav1.Object) Result {
+	state := toDeploymentState(obj)
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for the PersistentVolumeClaims of Deployment %q to be bound", fqName(state))}
+
+	if len(state.UnboundClaims) == 0 {
+		result.Ok = true
+	} else {
+		result.Failure = fmt.Sprintf("Failed to bind PersistentVolumeClaim(s): %q", strings.Join(state.UnboundClaims, ","))
+	}
+
+	return result
+}
+
+func deploymentAvailable(obj metav1.Object) Result {
+	state := toDeploymentState(obj)
+	result := Result{Description: fmt.Sprintf("Waiting for Deployment %q to become available", fqName(state))}
+
+	if state.Available {
+		result.Ok = true
+	} else {
+		result.Failure = "Minimum number of live Pods was not attained"
+	}
+
+	return result
+}
+
+func deploymentNewReplicaSetAvailable(obj metav1.Object) Result {
+	state := toDeploymentState(obj)
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for the new ReplicaSet of Deployment %q to be marked available", fqName(state))}
+
+	// The first revision of a Deployment is created rather than rolled out, so there is no rollout for the
+	// controller to mark as complete.
+	if !state.Rollout || state.NewReplicaSetAvailable {
+		result.Ok = true
+	} else if state.Available {
+		// Only the first unmet availability requirement is reported.
+		result.Failure = "Minimum number of Pods to consider the application live was not attained"
+	}
+
+	return result
+}
+
+func deploymentUpdatedReplicaSetReady(obj metav1.Object) Result {
+	state := toDeploymentState(obj)
+	result := Result{Description: fmt.Sprintf("Waiting for app ReplicaSet to be marked available (%d/%d Pods available)",
+		state.ReadyReplicas, state.DesiredReplicas)}
+
+	switch {
+	case state.UpdatedReplicaSetReady:
+		result.Ok = true
+	case !state.Available:
+		// Reported by deploymentAvailable.
+	case !state.Rollout:
+		result.Failure = "Minimum number of Pods to consider the application live was not attained"
+	case state.NewReplicaSetAvailable:
+		result.Failure = "Attempted to roll forward to new ReplicaSet, but minimum number of Pods did not become live"
+	}
+
+	return result
+}
+
+func deploymentGenerationObserved(obj metav1.Object) Result {
+	d := toDeployment(obj)
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for Deployment controller to observe the new spec of %q", fqName(d))}
+
+	if deploymentObserved(d) {
+		result.Ok = true
+	} else {
+		result.Failure = fmt.Sprintf("Deployment controller has not observed generation %d (observed generation: %d)",
+			d.Generation, d.Status.ObservedGeneration)
+	}
+
+	return result
+}
+
+func deploymentReplicasUpdated(obj metav1.Object) Result {
+	d := toDeployment(obj)
+	updated, target := d.Status.UpdatedReplicas, deploymentTarget(d)
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for Deployment %q to update replicas (%d/%d replicas updated)", fqName(d), updated, target)}
+
+	if !deploymentObserved(d) {
+		// The status is stale until the controller observes the current spec.
+		return result
+	}
+
+	for _, condition := range d.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == progressDeadlineExceeded {
+			result.Message = logging.ErrorMessage(fmt.Sprintf("Deployment %q exceeded its progress deadline: %s",
+				fqName(d), condition.Message))
+			result.Failure = fmt.Sprintf("Deployment exceeded its progress deadline: %s", condition.Message)
+			return result
+		}
+	}
+
+	if updated >= target {
+		result.Ok = true
+	} else {
+		result.Failure = fmt.Sprintf("%d out of %d replicas were updated to the current template", updated, target)
+	}
+
+	return result
+}
+
+func deploymentOldReplicasTerminated(obj metav1.Object) Result {
+	d := toDeployment(obj)
+	old := d.Status.Replicas - d.Status.UpdatedReplicas
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for Deployment %q to terminate old replicas (%d pending termination)", fqName(d), old)}
+
+	if !deploymentObserved(d) {
+		return result
+	}
+	if old <= 0 {
+		result.Ok = true
+	} else {
+		result.Failure = fmt.Sprintf("%d old replicas are pending termination", old)
+	}
+
+	return result
+}
+
+func deploymentReplicasAvailable(obj metav1.Object) Result {
+	d := toDeployment(obj)
+	available, updated := d.Status.AvailableReplicas, d.Status.UpdatedReplicas
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for Deployment %q replicas to become available (%d/%d updated replicas available)",
+		fqName(d), available, updated)}
+
+	if !deploymentObserved(d) {
+		return result
+	}
+	if available >= updated {
+		result.Ok = true
+	} else {
+		result.Failure = fmt.Sprintf("%d out of %d updated replicas are available", available, updated)
+	}
+
+	return result
+}
+
+//
+// Helpers
+//
+
+func toDeployment(obj metav1.Object) *appsv1.Deployment {
+	return obj.(*appsv1.Deployment)
+}
+
+func toDeploymentState(obj metav1.Object) *DeploymentState {
+	return obj.(*DeploymentState)
+}
+
+// deploymentObserved returns true if the Deployment controller has observed the current spec.
+func deploymentObserved(d *appsv1.Deployment) bool {
+	return d.Generation != 0 && d.Status.ObservedGeneration >= d.Generation
+}
+
+func deploymentTarget(d *appsv1.Deployment) int32 {
+	if d.Spec.Replicas == nil {
+		return 1
+	}
+	return *d.Spec.Replicas
+}
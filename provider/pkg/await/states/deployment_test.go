@@ -0,0 +1,264 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/recordings"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_deploymentReplicasUpdated(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      bool
+		wantError bool
+	}{
+		{
+			"Deployment added",
+			args{deploymentAddedState()},
+			false,
+			false,
+		},
+		{
+			"Deployment progressing",
+			args{deploymentProgressingState()},
+			true,
+			false,
+		},
+		{
+			"Deployment exceeded progress deadline",
+			args{deploymentDeadlineExceededState()},
+			false,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deploymentReplicasUpdated(tt.args.obj)
+			if got.Ok != tt.want {
+				t.Errorf("deploymentReplicasUpdated() = %v, want %v", got.Ok, tt.want)
+			}
+			if isError := got.Message.Severity == diag.Error; isError != tt.wantError {
+				t.Errorf("deploymentReplicasUpdated() message = %q, want error: %t", got.Message, tt.wantError)
+			}
+		})
+	}
+}
+
+func Test_deploymentOldReplicasTerminated(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"Deployment old replicas pending termination",
+			args{deploymentOldReplicasPendingState()},
+			false,
+		},
+		{
+			"Deployment ready",
+			args{deploymentReadyState()},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deploymentOldReplicasTerminated(tt.args.obj); got.Ok != tt.want {
+				t.Errorf("deploymentOldReplicasTerminated() = %v, want %v", got.Ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_deploymentReplicasAvailable(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"Deployment progressing",
+			args{deploymentProgressingState()},
+			false,
+		},
+		{
+			"Deployment ready",
+			args{deploymentReadyState()},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deploymentReplicasAvailable(tt.args.obj); got.Ok != tt.want {
+				t.Errorf("deploymentReplicasAvailable() = %v, want %v", got.Ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Deployment_State_Checker(t *testing.T) {
+	tests := []struct {
+		name         string
+		state        DeploymentState
+		wantReady    bool
+		wantFailures []string
+	}{
+		{
+			name:      "First revision with ready ReplicaSet",
+			state:     DeploymentState{Available: true, UpdatedReplicaSetReady: true},
+			wantReady: true,
+		},
+		{
+			name:         "First revision without ready ReplicaSet",
+			state:        DeploymentState{Available: true},
+			wantFailures: []string{"Minimum number of Pods to consider the application live was not attained"},
+		},
+		{
+			name:         "Rollout to ReplicaSet not yet marked available",
+			state:        DeploymentState{Rollout: true, Available: true, UpdatedReplicaSetReady: true},
+			wantFailures: []string{"Minimum number of Pods to consider the application live was not attained"},
+		},
+		{
+			name:  "Rollout to ReplicaSet without ready Pods",
+			state: DeploymentState{Rollout: true, Available: true, NewReplicaSetAvailable: true},
+			wantFailures: []string{
+				"Attempted to roll forward to new ReplicaSet, but minimum number of Pods did not become live"},
+		},
+		{
+			name:  "Unavailable Deployment with unbound claims",
+			state: DeploymentState{Rollout: true, UnboundClaims: []string{"data"}},
+			wantFailures: []string{
+				`Failed to bind PersistentVolumeClaim(s): "data"`,
+				"Minimum number of live Pods was not attained",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := tt.state
+			state.Deployment = deploymentProgressingState()
+
+			checker := NewDeploymentChecker()
+			checker.Update(&state)
+			if checker.Ready() != tt.wantReady {
+				t.Errorf("Ready() = %t, want %t", checker.Ready(), tt.wantReady)
+			}
+			if got := checker.Failures(); !reflect.DeepEqual(got, tt.wantFailures) {
+				t.Errorf("Failures() = %q, want %q", got, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func Test_Deployment_Checker(t *testing.T) {
+	workflow := func(name string) string {
+		return workflowPath("deployment", name)
+	}
+	const (
+		created          = "created"
+		updated          = "updated"
+		deadlineExceeded = "deadlineExceeded"
+	)
+
+	tests := []struct {
+		name           string
+		recordingPaths []string
+		expectReady    bool
+	}{
+		{
+			name:           "Deployment created",
+			recordingPaths: []string{workflow(created)},
+			expectReady:    true,
+		},
+		{
+			name:           "Deployment updated",
+			recordingPaths: []string{workflow(updated)},
+			expectReady:    true,
+		},
+		{
+			name:           "Deployment exceeded progress deadline",
+			recordingPaths: []string{workflow(deadlineExceeded)},
+			expectReady:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewDeploymentReplicasChecker()
+
+			ready, messages := mustCheckIfRecordingsReady(tt.recordingPaths, checker)
+			if ready != tt.expectReady {
+				t.Errorf("Ready() = %t, want %t\nMessages: %s", ready, tt.expectReady, messages)
+			}
+		})
+	}
+}
+
+//
+// Helpers
+//
+
+func deploymentStatePath(name string) string {
+	return statePath("deployment", name)
+}
+
+func mustLoadDeploymentRecording(path string) *appsv1.Deployment {
+	obj, err := clients.FromUnstructured(recordings.MustLoadState(path))
+	if err != nil {
+		panic(err)
+	}
+	return obj.(*appsv1.Deployment)
+}
+
+// deploymentAddedState returns a Deployment that has not been observed by the Deployment controller.
+func deploymentAddedState() *appsv1.Deployment {
+	return mustLoadDeploymentRecording(deploymentStatePath("added"))
+}
+
+// deploymentProgressingState returns a Deployment whose updated replicas are not all available yet.
+func deploymentProgressingState() *appsv1.Deployment {
+	return mustLoadDeploymentRecording(deploymentStatePath("progressing"))
+}
+
+// deploymentReadyState returns a Deployment that passes every await Condition.
+func deploymentReadyState() *appsv1.Deployment {
+	return mustLoadDeploymentRecording(deploymentStatePath("ready"))
+}
+
+// deploymentOldReplicasPendingState returns an updated Deployment whose old replicas have not terminated yet.
+func deploymentOldReplicasPendingState() *appsv1.Deployment {
+	return mustLoadDeploymentRecording(deploymentStatePath("oldReplicasPending"))
+}
+
+// deploymentDeadlineExceededState returns a Deployment whose rollout exceeded its progress deadline.
+func deploymentDeadlineExceededState() *appsv1.Deployment {
+	return mustLoadDeploymentRecording(deploymentStatePath("deadlineExceeded"))
+}
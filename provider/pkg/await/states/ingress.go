@@ -0,0 +1,174 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// IngressState is an Ingress along with the Endpoints and Services in its namespace, which are needed to decide
+// whether the backends of the Ingress target an active Service. Ingresses of every supported apiVersion are
+// represented in the networking.k8s.io/v1 shape. The Ingress checker expects its Conditions to be evaluated against
+// an *IngressState.
+type IngressState struct {
+	*networkingv1.Ingress
+
+	Endpoints            sets.String // Names of the Endpoints objects in the namespace of the Ingress.
+	ExternalNameServices sets.String // Names of the Services of type ExternalName in the namespace of the Ingress.
+}
+
+func NewIngressChecker() *StateChecker {
+	return &StateChecker{
+		conditions: []Condition{ingressTargetsServices, ingressLoadBalancerReady},
+		readyMsg:   cmdutil.EmojiOr("✅ Ingress initialization complete", "Ingress initialization complete"),
+	}
+}
+
+//
+// Conditions
+//
+
+func ingressTargetsServices(obj metav1.Object) Result {
+	state := toIngressState(obj)
+	result := Result{Description: fmt.Sprintf("Finding a matching Service for each path of Ingress %q", fqName(state))}
+
+	serviceNameField := ".spec.rules[].http.paths[].backend.serviceName"
+	if state.APIVersion == string(kinds.NetworkingV1) {
+		serviceNameField = ".spec.rules[].http.paths[].backend.service.name"
+	}
+	result.Failure = "Ingress has at least one rule that does not target any Service. " +
+		fmt.Sprintf("Field '%s' may not match any active Service", serviceNameField)
+
+	if backend := state.Spec.DefaultBackend; backend != nil && !state.backendReady(*backend) {
+		result.Message = logging.StatusMessage(fmt.Sprintf(
+			"No matching service found for ingress default backend: %s", expectedIngressPath("", "", backend.Service)))
+		return result
+	}
+
+	for _, rule := range state.Spec.Rules {
+		if rule.HTTP == nil {
+			result.Message = logging.ErrorMessage(fmt.Sprintf(
+				"expected value %q is unset for ingress: %s", ".spec.rules[*].http", state.Name))
+			return result
+		}
+		for _, path := range rule.HTTP.Paths {
+			if !state.backendReady(path.Backend) {
+				result.Message = logging.StatusMessage(fmt.Sprintf(
+					"No matching service found for ingress rule: %s",
+					expectedIngressPath(rule.Host, path.Path, path.Backend.Service)))
+				return result
+			}
+		}
+	}
+
+	result.Ok = true
+	result.Failure = ""
+	return result
+}
+
+func ingressLoadBalancerReady(obj metav1.Object) Result {
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for Ingress %q to update .status.loadBalancer with hostname/IP", fqName(obj))}
+
+	if len(ingressLoadBalancer(obj)) > 0 {
+		result.Ok = true
+	} else {
+		result.Failure = "Ingress .status.loadBalancer field was not updated with a hostname/IP address. " +
+			"\n    for more information about this error, see https://pulumi.io/xdv72s"
+	}
+
+	return result
+}
+
+//
+// Helpers
+//
+
+// ingressLoadBalancer returns the load balancer ingress points of an Ingress. Both the networking.k8s.io/v1 and
+// networking.k8s.io/v1beta1 representations are accepted, since the status is identical.
+func ingressLoadBalancer(obj metav1.Object) []v1.LoadBalancerIngress {
+	switch ingress := obj.(type) {
+	case *IngressState:
+		return ingress.Status.LoadBalancer.Ingress
+	case *networkingv1.Ingress:
+		return ingress.Status.LoadBalancer.Ingress
+	case *networkingv1beta1.Ingress:
+		return ingress.Status.LoadBalancer.Ingress
+	default:
+		panic(fmt.Sprintf("unexpected Ingress type %T", obj))
+	}
+}
+
+// backendReady returns true if the backend is not a Service backend, targets an ExternalName Service, or has a
+// matching Endpoints object.
+func (s *IngressState) backendReady(backend networkingv1.IngressBackend) bool {
+	// Resource backends (e.g., a storage bucket handled by the ingress controller) have no Endpoints.
+	if backend.Service == nil {
+		return true
+	}
+
+	// Ignore ExternalName services
+	if s.ExternalNameServices.Has(backend.Service.Name) {
+		return true
+	}
+
+	return s.Endpoints.Has(backend.Service.Name)
+}
+
+// expectedIngressPath is a helper to print a useful error message.
+func expectedIngressPath(host, path string, service *networkingv1.IngressServiceBackend) string {
+	rulePath := path
+	if host != "" {
+		rulePath = host + path
+	}
+
+	// It is valid for a user not to specify either a host or path [1]. In this case, any traffic not
+	// matching another rule is routed to the specified Service for this rule. Print
+	// `"" (default path)` to make this expectation clear to users.
+	//
+	// [1] https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.13/#httpingresspath-v1beta1-extensions
+	if rulePath == "" {
+		rulePath = `"" (default path)`
+	} else {
+		rulePath = fmt.Sprintf("%q", rulePath)
+	}
+
+	var target string
+	if service != nil {
+		target = service.Name
+		switch {
+		case service.Port.Name != "":
+			target = fmt.Sprintf("%s:%s", target, service.Port.Name)
+		case service.Port.Number != 0:
+			target = fmt.Sprintf("%s:%d", target, service.Port.Number)
+		}
+	}
+
+	// [host][path] -> serviceName[:port]
+	return fmt.Sprintf("%s -> %q", rulePath, target)
+}
+
+func toIngressState(obj metav1.Object) *IngressState {
+	return obj.(*IngressState)
+}
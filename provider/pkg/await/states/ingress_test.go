@@ -0,0 +1,177 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/recordings"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func Test_ingressTargetsServices(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"No Endpoints for the backend Service",
+			args{ingressTargeting("foo", sets.NewString(), sets.NewString())},
+			false,
+		},
+		{
+			"Endpoints exist for the backend Service",
+			args{ingressTargeting("foo", sets.NewString("foo"), sets.NewString())},
+			true,
+		},
+		{
+			"Backend Service is of type ExternalName",
+			args{ingressTargeting("foo", sets.NewString(), sets.NewString("foo"))},
+			true,
+		},
+		{
+			"Rule without an HTTP value",
+			args{&IngressState{
+				Ingress: &networkingv1.Ingress{Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{{Host: "example.com"}},
+				}},
+				Endpoints:            sets.NewString(),
+				ExternalNameServices: sets.NewString(),
+			}},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ingressTargetsServices(tt.args.obj); got.Ok != tt.want {
+				t.Errorf("ingressTargetsServices() = %v, want %v", got.Ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ingressLoadBalancerReady(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"Ingress initialized",
+			args{ingressInitializedState()},
+			false,
+		},
+		{
+			"Ingress ready",
+			args{ingressReadyState()},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ingressLoadBalancerReady(tt.args.obj); got.Ok != tt.want {
+				t.Errorf("ingressLoadBalancerReady() = %v, want %v", got.Ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_expectedIngressPath(t *testing.T) {
+	type args struct {
+		host    string
+		path    string
+		service *networkingv1.IngressServiceBackend
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{name: "host + path", args: args{host: "foo", path: "/bar", service: serviceBackend("baz", 0, "")}, want: `"foo/bar" -> "baz"`},
+		{name: "host only", args: args{host: "foo", service: serviceBackend("baz", 0, "")}, want: `"foo" -> "baz"`},
+		{name: "path only", args: args{path: "/bar", service: serviceBackend("baz", 0, "")}, want: `"/bar" -> "baz"`},
+		{name: "empty", args: args{service: serviceBackend("baz", 0, "")}, want: `"" (default path) -> "baz"`},
+		{name: "port number", args: args{path: "/bar", service: serviceBackend("baz", 80, "")}, want: `"/bar" -> "baz:80"`},
+		{name: "port name", args: args{path: "/bar", service: serviceBackend("baz", 0, "http")}, want: `"/bar" -> "baz:http"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expectedIngressPath(tt.args.host, tt.args.path, tt.args.service); got != tt.want {
+				t.Errorf("expectedIngressPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+//
+// Helpers
+//
+
+func ingressStatePath(name string) string {
+	return statePath("ingress", name)
+}
+
+func mustLoadIngressRecording(path string) metav1.Object {
+	obj, err := clients.FromUnstructured(recordings.MustLoadState(path))
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// ingressInitializedState returns an Ingress whose .status.loadBalancer has not been updated.
+func ingressInitializedState() metav1.Object {
+	return mustLoadIngressRecording(ingressStatePath("initialized"))
+}
+
+// ingressReadyState returns an Ingress that passes the ingressLoadBalancerReady await Condition.
+func ingressReadyState() metav1.Object {
+	return mustLoadIngressRecording(ingressStatePath("ready"))
+}
+
+func serviceBackend(name string, portNumber int32, portName string) *networkingv1.IngressServiceBackend {
+	return &networkingv1.IngressServiceBackend{
+		Name: name,
+		Port: networkingv1.ServiceBackendPort{Number: portNumber, Name: portName},
+	}
+}
+
+// ingressTargeting returns the state of an Ingress with a single path targeting the named Service.
+func ingressTargeting(service string, endpoints, externalNameServices sets.String) *IngressState {
+	return &IngressState{
+		Ingress: &networkingv1.Ingress{Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:    "/",
+						Backend: networkingv1.IngressBackend{Service: serviceBackend(service, 80, "")},
+					}},
+				}},
+			}},
+		}},
+		Endpoints:            endpoints,
+		ExternalNameServices: externalNameServices,
+	}
+}
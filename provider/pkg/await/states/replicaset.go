@@ -0,0 +1,101 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func NewReplicaSetChecker() *StateChecker {
+	return &StateChecker{
+		conditions: []Condition{replicaSetGenerationObserved, replicaSetReplicasAvailable},
+		readyMsg:   cmdutil.EmojiOr("✅ ReplicaSet initialization complete", "ReplicaSet initialization complete"),
+	}
+}
+
+//
+// Conditions
+//
+
+func replicaSetGenerationObserved(obj metav1.Object) Result {
+	rs := toReplicaSet(obj)
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for ReplicaSet controller to observe the new spec of %q", fqName(rs))}
+
+	if replicaSetObserved(rs) {
+		result.Ok = true
+	} else {
+		result.Failure = fmt.Sprintf("ReplicaSet controller has not observed generation %d (observed generation: %d)",
+			rs.Generation, rs.Status.ObservedGeneration)
+	}
+
+	return result
+}
+
+func replicaSetReplicasAvailable(obj metav1.Object) Result {
+	rs := toReplicaSet(obj)
+	available, target := rs.Status.AvailableReplicas, replicaSetTarget(rs)
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for ReplicaSet %q Pods to become available (%d/%d Pods available)", fqName(rs), available, target)}
+
+	if !replicaSetObserved(rs) {
+		// The status is stale until the controller observes the current spec.
+		return result
+	}
+	if available >= target {
+		result.Ok = true
+		return result
+	}
+
+	result.Failure = fmt.Sprintf("%d out of %d replicas succeeded readiness checks", available, target)
+
+	// The ReplicaFailure condition is set when Pods cannot be created, e.g., because of a quota.
+	for _, condition := range rs.Status.Conditions {
+		if condition.Type == appsv1.ReplicaSetReplicaFailure && condition.Status == v1.ConditionTrue {
+			failure := fmt.Sprintf("[%s] %s", condition.Reason, condition.Message)
+			result.Message = logging.WarningMessage(fmt.Sprintf("ReplicaSet %q failed to create Pods: %s",
+				fqName(rs), failure))
+			result.Failure = fmt.Sprintf("%s; failed to create Pods: %s", result.Failure, failure)
+		}
+	}
+
+	return result
+}
+
+//
+// Helpers
+//
+
+func toReplicaSet(obj metav1.Object) *appsv1.ReplicaSet {
+	return obj.(*appsv1.ReplicaSet)
+}
+
+// replicaSetObserved returns true if the ReplicaSet controller has observed the current spec.
+func replicaSetObserved(rs *appsv1.ReplicaSet) bool {
+	return rs.Generation != 0 && rs.Status.ObservedGeneration >= rs.Generation
+}
+
+func replicaSetTarget(rs *appsv1.ReplicaSet) int32 {
+	if rs.Spec.Replicas == nil {
+		return 1
+	}
+	return *rs.Spec.Replicas
+}
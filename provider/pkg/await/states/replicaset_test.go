@@ -0,0 +1,169 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/recordings"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_replicaSetGenerationObserved(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"ReplicaSet added",
+			args{replicaSetAddedState()},
+			false,
+		},
+		{
+			"ReplicaSet progressing",
+			args{replicaSetProgressingState()},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replicaSetGenerationObserved(tt.args.obj); got.Ok != tt.want {
+				t.Errorf("replicaSetGenerationObserved() = %v, want %v", got.Ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_replicaSetReplicasAvailable(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name        string
+		args        args
+		want        bool
+		wantFailure string
+	}{
+		{
+			"ReplicaSet progressing",
+			args{replicaSetProgressingState()},
+			false,
+			"1 out of 3 replicas succeeded readiness checks",
+		},
+		{
+			"ReplicaSet ready",
+			args{replicaSetReadyState()},
+			true,
+			"",
+		},
+		{
+			"ReplicaSet failed to create Pods",
+			args{replicaSetReplicaFailureState()},
+			false,
+			"1 out of 3 replicas succeeded readiness checks; failed to create Pods: [FailedCreate]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replicaSetReplicasAvailable(tt.args.obj)
+			if got.Ok != tt.want {
+				t.Errorf("replicaSetReplicasAvailable() = %v, want %v", got.Ok, tt.want)
+			}
+			if !strings.HasPrefix(got.Failure, tt.wantFailure) || (tt.wantFailure == "") != (got.Failure == "") {
+				t.Errorf("replicaSetReplicasAvailable() failure = %q, want %q", got.Failure, tt.wantFailure)
+			}
+		})
+	}
+}
+
+func Test_ReplicaSet_Checker(t *testing.T) {
+	workflow := func(name string) string {
+		return workflowPath("replicaset", name)
+	}
+	const (
+		created        = "created"
+		replicaFailure = "replicaFailure"
+	)
+
+	tests := []struct {
+		name           string
+		recordingPaths []string
+		expectReady    bool
+	}{
+		{
+			name:           "ReplicaSet created",
+			recordingPaths: []string{workflow(created)},
+			expectReady:    true,
+		},
+		{
+			name:           "ReplicaSet failed to create Pods",
+			recordingPaths: []string{workflow(replicaFailure)},
+			expectReady:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewReplicaSetChecker()
+
+			ready, messages := mustCheckIfRecordingsReady(tt.recordingPaths, checker)
+			if ready != tt.expectReady {
+				t.Errorf("Ready() = %t, want %t\nMessages: %s", ready, tt.expectReady, messages)
+			}
+		})
+	}
+}
+
+//
+// Helpers
+//
+
+func replicaSetStatePath(name string) string {
+	return statePath("replicaset", name)
+}
+
+func mustLoadReplicaSetRecording(path string) *appsv1.ReplicaSet {
+	obj, err := clients.FromUnstructured(recordings.MustLoadState(path))
+	if err != nil {
+		panic(err)
+	}
+	return obj.(*appsv1.ReplicaSet)
+}
+
+// replicaSetAddedState returns a ReplicaSet that has not been observed by the ReplicaSet controller.
+func replicaSetAddedState() *appsv1.ReplicaSet {
+	return mustLoadReplicaSetRecording(replicaSetStatePath("added"))
+}
+
+// replicaSetProgressingState returns a ReplicaSet whose replicas are not all available yet.
+func replicaSetProgressingState() *appsv1.ReplicaSet {
+	return mustLoadReplicaSetRecording(replicaSetStatePath("progressing"))
+}
+
+// replicaSetReadyState returns a ReplicaSet that passes every await Condition.
+func replicaSetReadyState() *appsv1.ReplicaSet {
+	return mustLoadReplicaSetRecording(replicaSetStatePath("ready"))
+}
+
+// replicaSetReplicaFailureState returns a ReplicaSet that failed to create Pods because of a ResourceQuota.
+func replicaSetReplicaFailureState() *appsv1.ReplicaSet {
+	return mustLoadReplicaSetRecording(replicaSetStatePath("replicaFailure"))
+}
@@ -0,0 +1,84 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceState is a Service along with the state of its Endpoints, which is needed to decide whether the Service is
+// Ready. The Service checker expects its Conditions to be evaluated against a *ServiceState.
+type ServiceState struct {
+	*v1.Service
+
+	EndpointsReady   bool // True if the Endpoints of the Service target at least one Pod.
+	EndpointsSettled bool // True if the Endpoints have not changed for long enough to be considered stable.
+	SkipEndpoints    bool // True if the Service is not expected to target any Pods, e.g., an ExternalName Service.
+}
+
+func NewServiceChecker() *StateChecker {
+	return &StateChecker{
+		conditions: []Condition{serviceTargetsPods, serviceLoadBalancerReady},
+		readyMsg:   cmdutil.EmojiOr("✅ Service initialization complete", "Service initialization complete"),
+	}
+}
+
+//
+// Conditions
+//
+
+func serviceTargetsPods(obj metav1.Object) Result {
+	state := toServiceState(obj)
+	result := Result{Description: fmt.Sprintf("Finding Pods for Service %q to direct traffic to", fqName(state))}
+
+	switch {
+	case state.SkipEndpoints:
+		result.Ok = true
+	case !state.EndpointsReady:
+		result.Failure = "Service does not target any Pods. Selected Pods may not be ready, or " +
+			"field '.spec.selector' may not match labels on any Pods"
+	default:
+		// The Endpoints target some Pods, but may still be changing.
+		result.Ok = state.EndpointsSettled
+	}
+
+	return result
+}
+
+func serviceLoadBalancerReady(obj metav1.Object) Result {
+	svc := toServiceState(obj).Service
+	result := Result{Description: fmt.Sprintf("Waiting for Service %q to be allocated an IP address", fqName(svc))}
+
+	// Only Services of type LoadBalancer are allocated an external IP address.
+	if svc.Spec.Type != v1.ServiceTypeLoadBalancer || len(svc.Status.LoadBalancer.Ingress) > 0 {
+		result.Ok = true
+	} else {
+		result.Failure = "Service was not allocated an IP address; does your cloud provider support this?"
+	}
+
+	return result
+}
+
+//
+// Helpers
+//
+
+func toServiceState(obj metav1.Object) *ServiceState {
+	return obj.(*ServiceState)
+}
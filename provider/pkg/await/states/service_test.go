@@ -0,0 +1,137 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/recordings"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_serviceTargetsPods(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name        string
+		args        args
+		want        bool
+		wantFailure bool
+	}{
+		{
+			"Endpoints do not target any Pods",
+			args{&ServiceState{Service: serviceClusterIPState(), EndpointsSettled: true}},
+			false,
+			true,
+		},
+		{
+			"Endpoints target Pods but have not settled",
+			args{&ServiceState{Service: serviceClusterIPState(), EndpointsReady: true}},
+			false,
+			false,
+		},
+		{
+			"Endpoints target Pods and have settled",
+			args{&ServiceState{Service: serviceClusterIPState(), EndpointsReady: true, EndpointsSettled: true}},
+			true,
+			false,
+		},
+		{
+			"Service is not expected to target Pods",
+			args{&ServiceState{Service: serviceClusterIPState(), SkipEndpoints: true}},
+			true,
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := serviceTargetsPods(tt.args.obj)
+			if got.Ok != tt.want {
+				t.Errorf("serviceTargetsPods() = %v, want %v", got.Ok, tt.want)
+			}
+			if (got.Failure != "") != tt.wantFailure {
+				t.Errorf("serviceTargetsPods() failure = %q, want failure %v", got.Failure, tt.wantFailure)
+			}
+		})
+	}
+}
+
+func Test_serviceLoadBalancerReady(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"Service of type ClusterIP",
+			args{&ServiceState{Service: serviceClusterIPState()}},
+			true,
+		},
+		{
+			"Service of type LoadBalancer without an IP address",
+			args{&ServiceState{Service: serviceLoadBalancerPendingState()}},
+			false,
+		},
+		{
+			"Service of type LoadBalancer with an IP address",
+			args{&ServiceState{Service: serviceLoadBalancerReadyState()}},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serviceLoadBalancerReady(tt.args.obj); got.Ok != tt.want {
+				t.Errorf("serviceLoadBalancerReady() = %v, want %v", got.Ok, tt.want)
+			}
+		})
+	}
+}
+
+//
+// Helpers
+//
+
+func serviceStatePath(name string) string {
+	return statePath("service", name)
+}
+
+func mustLoadServiceRecording(path string) *v1.Service {
+	obj, err := clients.FromUnstructured(recordings.MustLoadState(path))
+	if err != nil {
+		panic(err)
+	}
+	return obj.(*v1.Service)
+}
+
+// serviceClusterIPState returns a Service of type ClusterIP.
+func serviceClusterIPState() *v1.Service {
+	return mustLoadServiceRecording(serviceStatePath("clusterIP"))
+}
+
+// serviceLoadBalancerPendingState returns a Service of type LoadBalancer that has not been allocated an IP address.
+func serviceLoadBalancerPendingState() *v1.Service {
+	return mustLoadServiceRecording(serviceStatePath("loadBalancerPending"))
+}
+
+// serviceLoadBalancerReadyState returns a Service of type LoadBalancer that has been allocated an IP address.
+func serviceLoadBalancerReadyState() *v1.Service {
+	return mustLoadServiceRecording(serviceStatePath("loadBalancerReady"))
+}
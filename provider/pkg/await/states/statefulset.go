@@ -0,0 +1,175 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func NewStatefulSetChecker() *StateChecker {
+	return &StateChecker{
+		conditions: []Condition{statefulSetReplicasReady, statefulSetRevisionUpdated},
+		readyMsg:   cmdutil.EmojiOr("✅ StatefulSet initialization complete", "StatefulSet initialization complete"),
+	}
+}
+
+//
+// Conditions
+//
+
+func statefulSetReplicasReady(obj metav1.Object) Result {
+	ss := toStatefulSet(obj)
+	ready, target := statefulSetProgress(ss)
+	result := Result{Description: fmt.Sprintf(
+		"Waiting for StatefulSet %q to roll out (%d/%d Pods ready)", fqName(ss), ready, target)}
+
+	switch {
+	case !statefulSetObserved(ss):
+		// The status is stale until the controller observes the current spec.
+		result.Failure = fmt.Sprintf("%v out of %v replicas succeeded readiness checks", ready, target)
+	case statefulSetOnDeleteUpdate(ss):
+		// Pods are only replaced when they are deleted, so there is no rollout to wait for.
+		result.Ok = true
+	case statefulSetPartition(ss) > 0:
+		// Pods with ordinals below the partition keep running the previous revision, so they are not counted
+		// in `.status.currentReplicas`.
+		if target == ss.Status.Replicas && target == ss.Status.ReadyReplicas {
+			result.Ok = true
+		} else {
+			result.Failure = fmt.Sprintf("%v out of %v replicas succeeded readiness checks", ready, target)
+		}
+	case target == ss.Status.Replicas && target == ss.Status.ReadyReplicas && target == ss.Status.CurrentReplicas:
+		result.Ok = true
+	default:
+		result.Failure = fmt.Sprintf("%v out of %v replicas succeeded readiness checks", ready, target)
+	}
+
+	return result
+}
+
+func statefulSetRevisionUpdated(obj metav1.Object) Result {
+	ss := toStatefulSet(obj)
+	current, update := ss.Status.CurrentRevision, ss.Status.UpdateRevision
+	_, target := statefulSetProgress(ss)
+	partition := statefulSetPartition(ss)
+
+	var result Result
+	switch {
+	case statefulSetOnDeleteUpdate(ss):
+		result.Description = fmt.Sprintf(
+			"Waiting for StatefulSet controller to observe the new spec of %q", fqName(ss))
+		if statefulSetObserved(ss) {
+			result.Ok = true
+			if old := target - ss.Status.UpdatedReplicas; old > 0 && current != update {
+				result.Message = logging.StatusMessage(fmt.Sprintf(
+					"StatefulSet uses the OnDelete update strategy; %d/%d Pods are still running the previous "+
+						"revision and will be updated when they are deleted", old, target))
+			}
+		} else {
+			result.Failure = fmt.Sprintf("StatefulSet controller has not observed generation %d", ss.Generation)
+		}
+	case partition > 0:
+		want := target - partition
+		if want < 0 {
+			want = 0
+		}
+		result.Description = fmt.Sprintf(
+			"Waiting for StatefulSet %q to update Pods with ordinals >= %d (%d/%d Pods updated)",
+			fqName(ss), partition, ss.Status.UpdatedReplicas, want)
+		if statefulSetObserved(ss) && current != "" && (current == update || ss.Status.UpdatedReplicas >= want) {
+			result.Ok = true
+			if current != update && partition < target {
+				result.Message = logging.StatusMessage(fmt.Sprintf(
+					"StatefulSet %q has a partitioned rolling update; Pods with ordinals < %d are still running "+
+						"revision %q", fqName(ss), partition, current))
+			}
+		} else {
+			result.Failure = fmt.Sprintf("%d out of %d Pods with ordinals >= %d were updated to revision %q",
+				ss.Status.UpdatedReplicas, want, partition, update)
+		}
+	default:
+		result.Description = fmt.Sprintf(
+			"Waiting for StatefulSet %q to update .status.currentRevision", fqName(ss))
+		if statefulSetObserved(ss) && current != "" && current == update {
+			result.Ok = true
+		} else {
+			result.Failure = fmt.Sprintf("StatefulSet controller failed to advance from revision %q to revision %q",
+				current, update)
+		}
+	}
+
+	return result
+}
+
+//
+// Helpers
+//
+
+func toStatefulSet(obj metav1.Object) *appsv1.StatefulSet {
+	return obj.(*appsv1.StatefulSet)
+}
+
+// statefulSetObserved returns true if the StatefulSet controller has observed the current spec. Until it has, the
+// status describes the previous spec.
+func statefulSetObserved(ss *appsv1.StatefulSet) bool {
+	// Generation 0 is invalid; the StatefulSet controller has not yet processed the object.
+	return ss.Generation != 0 && ss.Status.ObservedGeneration >= ss.Generation
+}
+
+// statefulSetProgress returns the number of ready replicas that count towards the rollout, and the target number of
+// replicas.
+func statefulSetProgress(ss *appsv1.StatefulSet) (int32, int32) {
+	target := int32(1)
+	if ss.Spec.Replicas != nil {
+		target = *ss.Spec.Replicas
+	}
+
+	// For the initial rollout, .status.readyReplicas is an accurate gauge of progress. During an update, the number
+	// of "ready" replicas can include instances of the previous revision, so don't count those towards the target.
+	// With a partitioned or OnDelete update, Pods of the previous revision are expected to keep running, so count
+	// those too.
+	ready := ss.Status.ReadyReplicas
+	revisionReady := ss.Status.CurrentRevision != "" && ss.Status.CurrentRevision == ss.Status.UpdateRevision
+	stagedUpdate := statefulSetPartition(ss) > 0 || statefulSetOnDeleteUpdate(ss)
+	if !revisionReady && !stagedUpdate && ss.Status.UpdatedReplicas < ready {
+		ready = ss.Status.UpdatedReplicas
+	}
+	return ready, target
+}
+
+// statefulSetOnDeleteUpdate returns true if an existing StatefulSet is updated with the OnDelete strategy. The
+// controller then records the new template as `.status.updateRevision`, but leaves the running Pods and
+// `.status.currentRevision` alone; a Pod is only recreated at the update revision after it is deleted by hand.
+// Waiting for the revisions to converge would never finish, so the update is complete once the new spec has been
+// observed. A new StatefulSet (generation 1) has no previous revision, so its Pods are awaited as usual.
+func statefulSetOnDeleteUpdate(ss *appsv1.StatefulSet) bool {
+	return ss.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType && ss.Generation > 1
+}
+
+// statefulSetPartition returns the partition of a RollingUpdate, or 0 if the update is not partitioned. Only Pods
+// with an ordinal greater than or equal to the partition are updated.
+func statefulSetPartition(ss *appsv1.StatefulSet) int32 {
+	rollingUpdate := ss.Spec.UpdateStrategy.RollingUpdate
+	if ss.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType ||
+		rollingUpdate == nil || rollingUpdate.Partition == nil {
+		return 0
+	}
+	return *rollingUpdate.Partition
+}
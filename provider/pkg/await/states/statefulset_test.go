@@ -0,0 +1,301 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package states
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await/recordings"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_statefulSetReplicasReady(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"StatefulSet added",
+			args{statefulSetAddedState()},
+			false,
+		},
+		{
+			"StatefulSet creating",
+			args{statefulSetCreatingState()},
+			false,
+		},
+		{
+			"StatefulSet ready",
+			args{statefulSetReadyState()},
+			true,
+		},
+		{
+			"StatefulSet updating",
+			args{statefulSetUpdatingState()},
+			false,
+		},
+		{
+			"StatefulSet partitioned update with unready Pod",
+			args{statefulSetPartitionUpdatingState()},
+			false,
+		},
+		{
+			"StatefulSet partitioned update with ready Pods",
+			args{statefulSetPartitionUpdatedState()},
+			true,
+		},
+		{
+			"StatefulSet OnDelete update observed",
+			args{statefulSetOnDeleteUpdatedState()},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statefulSetReplicasReady(tt.args.obj); got.Ok != tt.want {
+				t.Errorf("statefulSetReplicasReady() = %v, want %v", got.Ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_statefulSetRevisionUpdated(t *testing.T) {
+	type args struct {
+		obj metav1.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			"StatefulSet added",
+			args{statefulSetAddedState()},
+			false,
+		},
+		{
+			"StatefulSet updating",
+			args{statefulSetUpdatingState()},
+			false,
+		},
+		{
+			"StatefulSet updated",
+			args{statefulSetUpdatedState()},
+			true,
+		},
+		{
+			"StatefulSet partitioned update in progress",
+			args{statefulSetPartitionUpdatingState()},
+			false,
+		},
+		{
+			"StatefulSet partitioned update complete",
+			args{statefulSetPartitionUpdatedState()},
+			true,
+		},
+		{
+			"StatefulSet OnDelete update observed",
+			args{statefulSetOnDeleteUpdatedState()},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statefulSetRevisionUpdated(tt.args.obj); got.Ok != tt.want {
+				t.Errorf("statefulSetRevisionUpdated() = %v, want %v", got.Ok, tt.want)
+			}
+		})
+	}
+}
+
+func Test_statefulSetRevisionUpdated_Messages(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  *appsv1.StatefulSet
+		want string
+	}{
+		{
+			"StatefulSet partitioned update complete",
+			statefulSetPartitionUpdatedState(),
+			"StatefulSet \"foo\" has a partitioned rolling update; Pods with ordinals < 1 are still running " +
+				"revision \"foo-7b5cf87b78\"",
+		},
+		{
+			"StatefulSet OnDelete update observed",
+			statefulSetOnDeleteUpdatedState(),
+			"StatefulSet uses the OnDelete update strategy; 3/3 Pods are still running the previous revision and " +
+				"will be updated when they are deleted",
+		},
+		{
+			"StatefulSet updated",
+			statefulSetUpdatedState(),
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statefulSetRevisionUpdated(tt.obj); got.Message.S != tt.want {
+				t.Errorf("statefulSetRevisionUpdated().Message = %q, want %q", got.Message.S, tt.want)
+			}
+		})
+	}
+}
+
+func Test_StatefulSet_Checker(t *testing.T) {
+	workflow := func(name string) string {
+		return workflowPath("statefulset", name)
+	}
+	const (
+		created       = "created"
+		createStalled = "createStalled"
+		updated       = "updated"
+		updateStalled = "updateStalled"
+
+		partitionUpdated       = "partitionUpdated"
+		partitionUpdateStalled = "partitionUpdateStalled"
+		onDeleteUpdated        = "onDeleteUpdated"
+	)
+
+	tests := []struct {
+		name           string
+		recordingPaths []string
+		expectReady    bool
+		expectFailures []string
+	}{
+		{
+			name:           "StatefulSet created",
+			recordingPaths: []string{workflow(created)},
+			expectReady:    true,
+		},
+		{
+			name:           "StatefulSet creation stalled",
+			recordingPaths: []string{workflow(createStalled)},
+			expectReady:    false,
+			expectFailures: []string{"0 out of 2 replicas succeeded readiness checks"},
+		},
+		{
+			name:           "StatefulSet updated",
+			recordingPaths: []string{workflow(updated)},
+			expectReady:    true,
+		},
+		{
+			name:           "StatefulSet update stalled",
+			recordingPaths: []string{workflow(updateStalled)},
+			expectReady:    false,
+			expectFailures: []string{
+				"1 out of 2 replicas succeeded readiness checks",
+				"StatefulSet controller failed to advance from revision \"foo-7b5cf87b78\" to revision \"foo-789c4b994f\"",
+			},
+		},
+		{
+			name:           "StatefulSet partitioned update",
+			recordingPaths: []string{workflow(partitionUpdated)},
+			expectReady:    true,
+		},
+		{
+			name:           "StatefulSet partitioned update stalled",
+			recordingPaths: []string{workflow(partitionUpdateStalled)},
+			expectReady:    false,
+			expectFailures: []string{
+				"2 out of 3 replicas succeeded readiness checks",
+				"1 out of 2 Pods with ordinals >= 1 were updated to revision \"foo-789c4b994f\"",
+			},
+		},
+		{
+			name:           "StatefulSet OnDelete update",
+			recordingPaths: []string{workflow(onDeleteUpdated)},
+			expectReady:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewStatefulSetChecker()
+
+			ready, messages := mustCheckIfRecordingsReady(tt.recordingPaths, checker)
+			if ready != tt.expectReady {
+				t.Errorf("Ready() = %t, want %t\nMessages: %s", ready, tt.expectReady, messages)
+			}
+			if failures := checker.Failures(); !reflect.DeepEqual(failures, tt.expectFailures) {
+				t.Errorf("Failures() = %q, want %q", failures, tt.expectFailures)
+			}
+		})
+	}
+}
+
+//
+// Helpers
+//
+
+func statefulSetStatePath(name string) string {
+	return statePath("statefulset", name)
+}
+
+func mustLoadStatefulSetRecording(path string) *appsv1.StatefulSet {
+	obj, err := clients.FromUnstructured(recordings.MustLoadState(path))
+	if err != nil {
+		panic(err)
+	}
+	return obj.(*appsv1.StatefulSet)
+}
+
+// statefulSetAddedState returns a StatefulSet that has not been processed by the StatefulSet controller.
+func statefulSetAddedState() *appsv1.StatefulSet {
+	return mustLoadStatefulSetRecording(statefulSetStatePath("added"))
+}
+
+// statefulSetCreatingState returns a StatefulSet whose Pods are being created.
+func statefulSetCreatingState() *appsv1.StatefulSet {
+	return mustLoadStatefulSetRecording(statefulSetStatePath("creating"))
+}
+
+// statefulSetReadyState returns a StatefulSet that passes every await Condition.
+func statefulSetReadyState() *appsv1.StatefulSet {
+	return mustLoadStatefulSetRecording(statefulSetStatePath("ready"))
+}
+
+// statefulSetUpdatingState returns a StatefulSet that is rolling out a new revision.
+func statefulSetUpdatingState() *appsv1.StatefulSet {
+	return mustLoadStatefulSetRecording(statefulSetStatePath("updating"))
+}
+
+// statefulSetUpdatedState returns a StatefulSet that has finished rolling out a new revision.
+func statefulSetUpdatedState() *appsv1.StatefulSet {
+	return mustLoadStatefulSetRecording(statefulSetStatePath("updated"))
+}
+
+// statefulSetPartitionUpdatingState returns a StatefulSet that is rolling out a new revision to the Pods with
+// ordinals greater than or equal to its partition.
+func statefulSetPartitionUpdatingState() *appsv1.StatefulSet {
+	return mustLoadStatefulSetRecording(statefulSetStatePath("partitionUpdating"))
+}
+
+// statefulSetPartitionUpdatedState returns a StatefulSet that has finished rolling out a new revision to the Pods
+// with ordinals greater than or equal to its partition.
+func statefulSetPartitionUpdatedState() *appsv1.StatefulSet {
+	return mustLoadStatefulSetRecording(statefulSetStatePath("partitionUpdated"))
+}
+
+// statefulSetOnDeleteUpdatedState returns a StatefulSet with the OnDelete update strategy whose new spec has been
+// observed, but whose Pods are still running the previous revision.
+func statefulSetOnDeleteUpdatedState() *appsv1.StatefulSet {
+	return mustLoadStatefulSetRecording(statefulSetStatePath("onDeleteUpdated"))
+}
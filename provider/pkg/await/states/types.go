@@ -26,6 +26,7 @@ type Result struct {
 	Ok          bool            // True if the Condition is true, false otherwise.
 	Description string          // A human-readable description of the associated Condition.
 	Message     logging.Message // The message to be logged after evaluating the Condition.
+	Failure     string          // The error to report if the Condition is still false when awaiting fails.
 }
 
 func (r Result) String() string {
@@ -43,9 +44,10 @@ type Condition func(s metav1.Object) Result
 
 // StateChecker holds the data required to generically implement await logic.
 type StateChecker struct {
-	ready      bool        // True if all of the conditions evaluated to true on the most recent Update.
-	conditions []Condition // Conditions that must be true for the state to be Ready.
-	readyMsg   string      // Status message to show if the state is Ready.
+	ready      bool          // True if all of the conditions evaluated to true on the most recent Update.
+	conditions []Condition   // Conditions that must be true for the state to be Ready.
+	readyMsg   string        // Status message to show if the state is Ready.
+	obj        metav1.Object // The object passed to the most recent Update.
 }
 
 // Ready is true if all of the Conditions associated with this checker are true. Ready will always return false prior
@@ -59,18 +61,19 @@ func (s *StateChecker) Ready() bool {
 // status is set to true, otherwise, the ready condition is set to false.
 func (s *StateChecker) Update(obj metav1.Object) logging.Messages {
 	s.ready = false
+	s.obj = obj
 
 	var messages logging.Messages
 	for i, condition := range s.conditions {
-		prefix := fmt.Sprintf("[%d/%d]", i, len(s.conditions))
+		prefix := fmt.Sprintf("[%d/%d]", i+1, len(s.conditions))
 
 		result := condition(obj)
 		messages = append(messages, logging.StatusMessage(fmt.Sprintf("%s %s", prefix, result.Description)))
+		if !result.Message.Empty() {
+			messages = append(messages, result.Message)
+		}
 
 		if !result.Ok {
-			if !result.Message.Empty() {
-				messages = append(messages, result.Message)
-			}
 			return messages
 		}
 	}
@@ -79,3 +82,20 @@ func (s *StateChecker) Update(obj metav1.Object) logging.Messages {
 	messages = append(messages, logging.StatusMessage(s.readyMsg))
 	return messages
 }
+
+// Failures runs every Condition against the object passed to the most recent Update, and returns the Failure of
+// each Condition that is false. Unlike Update, evaluation does not stop at the first false Condition, so that every
+// reason the object is not Ready can be reported.
+func (s *StateChecker) Failures() []string {
+	if s.obj == nil {
+		return nil
+	}
+
+	var failures []string
+	for _, condition := range s.conditions {
+		if result := condition(s.obj); !result.Ok && result.Failure != "" {
+			failures = append(failures, result.Failure)
+		}
+	}
+	return failures
+}
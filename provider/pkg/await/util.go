@@ -16,8 +16,10 @@ package await
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
 	"k8s.io/api/core/v1"
@@ -65,9 +67,21 @@ func watchAddedEvent(obj runtime.Object) watch.Event {
 	}
 }
 
-// nolint
+// getLastWarningsForObject returns the `limit` most recent Warning events for the specified object,
+// deduplicated by message.
 func getLastWarningsForObject(
 	clientForEvents dynamic.ResourceInterface, namespace, name, kind string, limit int,
+) ([]v1.Event, error) {
+	events, err := getEventsForObject(clientForEvents, namespace, name, kind)
+	if err != nil {
+		return nil, err
+	}
+	return lastWarnings(events, limit), nil
+}
+
+// getEventsForObject returns the events for the specified object.
+func getEventsForObject(
+	clientForEvents dynamic.ResourceInterface, namespace, name, kind string,
 ) ([]v1.Event, error) {
 	m := map[string]string{
 		"involvedObject.name": name,
@@ -86,41 +100,36 @@ func getLastWarningsForObject(
 		return nil, err
 	}
 
-	items := out.Items
 	var events []v1.Event
-	for _, item := range items {
-		// Round trip conversion from `Unstructured` to `v1.Event`. There doesn't seem to be a good way
-		// to do this conversion in client-go, and this is not a performance-critical section. When we
-		// upgrade to client-go v7, we can replace it with `runtime.DefaultUnstructuredConverter`.
-		var warning v1.Event
-		str, err := item.MarshalJSON()
+	for _, item := range out.Items {
+		var event v1.Event
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &event)
 		if err != nil {
-			log.Fatal(err)
+			logger.V(3).Infof("Failed to convert Event %q: %v", item.GetName(), err)
+			continue
 		}
+		events = append(events, event)
+	}
 
-		err = warning.Unmarshal(str)
-		if err != nil {
-			log.Fatal(err)
-		}
+	logger.V(9).Infof("Received '%d' events for %s/%s (%s)",
+		len(events), namespace, name, kind)
 
-		events = append(events, warning)
-	}
+	return events, nil
+}
 
+// lastWarnings returns the `limit` most recent Warning events, deduplicated by message.
+func lastWarnings(events []v1.Event, limit int) []v1.Event {
 	// Bring latest events to the top, for easy access
-	sort.Slice(events, func(i, j int) bool {
-		return events[i].LastTimestamp.After(events[j].LastTimestamp.Time)
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).After(eventTime(events[j]))
 	})
 
-	logger.V(9).Infof("Received '%d' events for %s/%s (%s)",
-		len(events), namespace, name, kind)
-
 	// It would be better to sort & filter on the server-side
 	// but API doesn't seem to support it
 	var warnings []v1.Event
-	warnCount := 0
 	uniqueWarnings := make(map[string]v1.Event)
 	for _, e := range events {
-		if warnCount >= limit {
+		if len(warnings) >= limit {
 			break
 		}
 
@@ -131,11 +140,34 @@ func getLastWarningsForObject(
 			}
 			warnings = append(warnings, e)
 			uniqueWarnings[e.Message] = e
-			warnCount++
 		}
 	}
 
-	return warnings, nil
+	return warnings
+}
+
+// eventTime returns the time an event was last observed. Events reported through the
+// events.k8s.io API set `eventTime` (and `series`) rather than `lastTimestamp`.
+func eventTime(e v1.Event) time.Time {
+	switch {
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.FirstTimestamp.Time
+	}
+}
+
+// formatWarning formats a Warning event as a human-readable message.
+func formatWarning(e v1.Event) string {
+	message := fmt.Sprintf("[%s] %s", e.Reason, strings.TrimSpace(e.Message))
+	if e.Count > 1 {
+		message = fmt.Sprintf("%s (x%d)", message, e.Count)
+	}
+	return message
 }
 
 // --------------------------------------------------------------------------
@@ -40,3 +40,29 @@ func canonicalizeStatefulSetAPIVersion(ver string) string {
 		return ver
 	}
 }
+
+// canonicalizeDaemonSetAPIVersion unifies the various pre-release apiVersion values for a
+// DaemonSet into "apps/v1".
+func canonicalizeDaemonSetAPIVersion(ver string) string {
+	switch ver {
+	case "extensions/v1beta1", "apps/v1beta2", "apps/v1":
+		// Canonicalize all of these to "apps/v1".
+		return "apps/v1"
+	default:
+		// If the input version was not a version we understand, just return it as-is.
+		return ver
+	}
+}
+
+// canonicalizeReplicaSetAPIVersion unifies the various pre-release apiVersion values for a
+// ReplicaSet into "apps/v1".
+func canonicalizeReplicaSetAPIVersion(ver string) string {
+	switch ver {
+	case "extensions/v1beta1", "apps/v1beta2", "apps/v1":
+		// Canonicalize all of these to "apps/v1".
+		return "apps/v1"
+	default:
+		// If the input version was not a version we understand, just return it as-is.
+		return ver
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package await
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/clients"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/logging"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/util/cmdutil"
+	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// ------------------------------------------------------------------------------------------------
+
+// Generic await logic for resources annotated with `pulumi.com/waitFor`.
+//
+// Most custom resources (e.g., cert-manager Certificates, Crossplane claims) have no built-in await
+// logic, and are considered ready as soon as the API server accepts them. The `pulumi.com/waitFor`
+// annotation lets users specify a readiness predicate for any resource, which takes precedence over
+// the built-in await logic for that resource type. The predicate is one of:
+//
+//   1. `condition=<type>[=<status>]`: the `.status.conditions` entry of the given type must have
+//      the given status (default "True").
+//   2. `jsonpath={<expression>}[=<value>]`: the JSONPath expression evaluated against the live
+//      object must equal the given value (or be non-empty, if no value is given).
+//
+// While waiting, the `reason` and `message` of the awaited condition are reported as status
+// messages. If the object reports a terminal failure (a `Failed` or `Stalled` condition with status
+// "True"), the awaiter fails immediately rather than waiting for the timeout.
+//
+// The event loop depends on the following channels:
+//
+//   1. The object channel, to which the Kubernetes API server will push every change to the object.
+//   2. A timeout channel, which fires after some minutes.
+//   3. A cancellation channel, with which the user can signal cancellation (e.g., using SIGINT).
+
+// ------------------------------------------------------------------------------------------------
+
+const (
+	DefaultWaitForTimeoutMins = 10
+)
+
+// terminalConditionTypes are condition types that, when "True", indicate that the object will not
+// become ready without intervention.
+var terminalConditionTypes = []string{"Failed", "Stalled"}
+
+type waitForAwaiter struct {
+	config  createAwaitConfig
+	waitFor *metadata.WaitFor
+	object  *unstructured.Unstructured
+	ready   bool
+	failure string
+	status  string
+}
+
+func makeWaitForAwaiter(c createAwaitConfig, waitFor *metadata.WaitFor) *waitForAwaiter {
+	return &waitForAwaiter{
+		config:  c,
+		waitFor: waitFor,
+		object:  c.currentOutputs,
+	}
+}
+
+var waitForAwaitSpec = awaitSpec{
+	awaitCreation: awaitWaitFor,
+	awaitUpdate: func(u updateAwaitConfig) error {
+		return awaitWaitFor(u.createAwaitConfig)
+	},
+	awaitRead: awaitWaitForRead,
+}
+
+func awaitWaitFor(c createAwaitConfig) error {
+	waitFor, err := metadata.GetWaitFor(c.currentInputs)
+	if err != nil {
+		return err
+	}
+	return makeWaitForAwaiter(c, waitFor).Await()
+}
+
+func awaitWaitForRead(c createAwaitConfig) error {
+	waitFor, err := metadata.GetWaitFor(c.currentInputs)
+	if err != nil {
+		return err
+	}
+	return makeWaitForAwaiter(c, waitFor).Read()
+}
+
+func (wfa *waitForAwaiter) Await() error {
+	objWatcher, err := wfa.config.watch(wfa.config.currentInputs.GroupVersionKind(),
+		wfa.config.currentInputs.GetNamespace(), clients.ObjectNamed(wfa.config.currentInputs.GetName()))
+	if err != nil {
+		return errors.Wrapf(err, "Could not set up watch for %q", wfa.config.currentInputs.GetName())
+	}
+	defer objWatcher.Stop()
+
+	// The watch only reports changes, so check the latest version of the object first.
+	if wfa.object != nil {
+		wfa.processObjectEvent(watchAddedEvent(wfa.object))
+	}
+
+	timeout := metadata.TimeoutDuration(wfa.config.timeout, wfa.config.defaultTimeouts,
+		wfa.config.currentInputs, DefaultWaitForTimeoutMins*60)
+	return wfa.await(objWatcher, time.After(timeout))
+}
+
+func (wfa *waitForAwaiter) Read() error {
+	client, err := wfa.config.clientSet.ResourceClientForObject(wfa.config.currentInputs)
+	if err != nil {
+		return errors.Wrapf(err, "Could not make client to get %q", wfa.config.currentInputs.GetName())
+	}
+
+	obj, err := client.Get(context.TODO(), wfa.config.currentInputs.GetName(), metav1.GetOptions{})
+	if err != nil {
+		// IMPORTANT: Do not wrap this error! If this is a 404, the provider need to know so that it
+		// can mark the resource as having been deleted.
+		return err
+	}
+
+	return wfa.read(obj)
+}
+
+// read is a helper companion to `Read` designed to make it easy to test this module.
+func (wfa *waitForAwaiter) read(obj *unstructured.Unstructured) error {
+	wfa.processObjectEvent(watchAddedEvent(obj))
+	if wfa.ready {
+		return nil
+	}
+
+	return &initializationError{
+		subErrors: wfa.errorMessages(),
+		object:    obj,
+	}
+}
+
+// await is a helper companion to `Await` designed to make it easy to test this module.
+func (wfa *waitForAwaiter) await(objWatcher watch.Interface, timeout <-chan time.Time) error {
+	for {
+		if wfa.ready {
+			wfa.config.logStatus(diag.Info,
+				fmt.Sprintf("%sResource is ready (%s)", cmdutil.EmojiOr("✅ ", ""), wfa.waitFor))
+			return nil
+		}
+		if wfa.failure != "" {
+			return &initializationError{
+				subErrors: wfa.errorMessages(),
+				object:    wfa.object,
+			}
+		}
+
+		// Else, wait for updates.
+		select {
+		case <-wfa.config.ctx.Done():
+			return &cancellationError{
+				object:    wfa.object,
+				subErrors: wfa.errorMessages(),
+			}
+		case <-timeout:
+			return &timeoutError{
+				object:    wfa.object,
+				subErrors: wfa.errorMessages(),
+			}
+		case event := <-objWatcher.ResultChan():
+			wfa.processObjectEvent(event)
+		}
+	}
+}
+
+func (wfa *waitForAwaiter) processObjectEvent(event watch.Event) {
+	obj, isUnstructured := event.Object.(*unstructured.Unstructured)
+	if !isUnstructured {
+		logger.V(3).Infof("Watch received unknown object type %q", reflect.TypeOf(obj))
+		return
+	}
+
+	// Do nothing if this is not the object we're waiting for.
+	if obj.GetName() != wfa.config.currentInputs.GetName() {
+		return
+	}
+
+	// Start over, prove that the object is ready.
+	wfa.ready = false
+	wfa.failure = ""
+
+	// Mark the object as not ready if it's deleted.
+	if event.Type == watch.Deleted {
+		return
+	}
+
+	wfa.object = obj
+
+	for _, conditionType := range terminalConditionTypes {
+		if condition := findCondition(obj, conditionType); condition != nil && condition["status"] == trueStatus {
+			wfa.failure = conditionMessage(condition)
+			wfa.config.logStatus(diag.Error, wfa.failure)
+			return
+		}
+	}
+
+	if wfa.waitFor.JSONPath != "" {
+		wfa.processJSONPath(obj)
+	} else {
+		wfa.processCondition(obj)
+	}
+}
+
+func (wfa *waitForAwaiter) processCondition(obj *unstructured.Unstructured) {
+	condition := findCondition(obj, wfa.waitFor.ConditionType)
+	if condition == nil {
+		wfa.status = fmt.Sprintf("Waiting for condition %q to be reported", wfa.waitFor.ConditionType)
+		wfa.config.logStatus(diag.Info, wfa.status)
+		return
+	}
+
+	wfa.ready = condition["status"] == wfa.waitFor.ConditionStatus
+	if !wfa.ready {
+		wfa.status = conditionMessage(condition)
+		wfa.config.logMessage(logging.StatusMessage(wfa.status))
+	}
+}
+
+func (wfa *waitForAwaiter) processJSONPath(obj *unstructured.Unstructured) {
+	value, err := evaluateJSONPath(wfa.waitFor.JSONPath, obj)
+	if err != nil {
+		wfa.status = fmt.Sprintf("Waiting for %s: %v", wfa.waitFor, err)
+		wfa.config.logStatus(diag.Info, wfa.status)
+		return
+	}
+
+	if wfa.waitFor.Value == "" {
+		wfa.ready = value != ""
+	} else {
+		wfa.ready = value == wfa.waitFor.Value
+	}
+	if !wfa.ready {
+		wfa.status = fmt.Sprintf("Waiting for %s (currently %q)", wfa.waitFor, value)
+		wfa.config.logStatus(diag.Info, wfa.status)
+	}
+}
+
+func (wfa *waitForAwaiter) errorMessages() []string {
+	messages := make([]string, 0)
+	if wfa.failure != "" {
+		messages = append(messages, wfa.failure)
+	} else if !wfa.ready {
+		messages = append(messages, fmt.Sprintf("Resource did not satisfy %s", wfa.waitFor))
+		if wfa.status != "" {
+			messages = append(messages, wfa.status)
+		}
+	}
+	return messages
+}
+
+// evaluateJSONPath evaluates a JSONPath expression against an object, and returns the result as a
+// string. Missing fields evaluate to the empty string.
+func evaluateJSONPath(expr string, obj *unstructured.Unstructured) (string, error) {
+	jp := jsonpath.New("waitFor").AllowMissingKeys(true)
+	if err := jp.Parse(expr); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, obj.Object); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// conditionMessage formats a `.status.conditions` entry as a human-readable message.
+func conditionMessage(condition map[string]interface{}) string {
+	message := fmt.Sprintf("%v=%v", condition["type"], condition["status"])
+	if reason, ok := condition["reason"]; ok && reason != "" {
+		message = fmt.Sprintf("[%v] %s", reason, message)
+	}
+	if msg, ok := condition["message"]; ok && msg != "" {
+		message = fmt.Sprintf("%s: %v", message, msg)
+	}
+	return message
+}
@@ -0,0 +1,236 @@
+// nolint: goconst
+package await
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func Test_WaitFor(t *testing.T) {
+	tests := []struct {
+		description   string
+		waitFor       string
+		do            func(objects chan watch.Event, timeout chan time.Time)
+		expectedError error
+	}{
+		{
+			description: "Should succeed when condition becomes True",
+			waitFor:     "condition=Ready",
+			do: func(objects chan watch.Event, timeout chan time.Time) {
+				objects <- watchAddedEvent(certificateWithCondition("Ready", "False", "Issuing", "Issuing certificate"))
+				objects <- watchAddedEvent(certificateWithCondition("Ready", "True", "Ready", "Certificate is up to date"))
+			},
+		},
+		{
+			description: "Should succeed when condition reaches the expected status",
+			waitFor:     "condition=Synced=False",
+			do: func(objects chan watch.Event, timeout chan time.Time) {
+				objects <- watchAddedEvent(certificateWithCondition("Synced", "False", "", ""))
+			},
+		},
+		{
+			description: "Should succeed when JSONPath matches the expected value",
+			waitFor:     "jsonpath={.status.phase}=Running",
+			do: func(objects chan watch.Event, timeout chan time.Time) {
+				objects <- watchAddedEvent(certificateWithPhase("Pending"))
+				objects <- watchAddedEvent(certificateWithPhase("Running"))
+			},
+		},
+		{
+			description: "Should succeed when JSONPath evaluates to a non-empty value",
+			waitFor:     "jsonpath={.status.phase}",
+			do: func(objects chan watch.Event, timeout chan time.Time) {
+				objects <- watchAddedEvent(certificateWithCondition("Ready", "False", "", ""))
+				objects <- watchAddedEvent(certificateWithPhase("Pending"))
+			},
+		},
+		{
+			description: "Should fail fast when a terminal condition is reported",
+			waitFor:     "condition=Ready",
+			do: func(objects chan watch.Event, timeout chan time.Time) {
+				objects <- watchAddedEvent(certificateWithCondition("Failed", "True", "InvalidIssuer", "Issuer not found"))
+			},
+			expectedError: &initializationError{
+				object:    certificateWithCondition("Failed", "True", "InvalidIssuer", "Issuer not found"),
+				subErrors: []string{"[InvalidIssuer] Failed=True: Issuer not found"},
+			},
+		},
+		{
+			description: "Should fail with condition reason if timeout occurs",
+			waitFor:     "condition=Ready",
+			do: func(objects chan watch.Event, timeout chan time.Time) {
+				objects <- watchAddedEvent(certificateWithCondition("Ready", "False", "Issuing", "Issuing certificate"))
+
+				// Timeout. Failure.
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{
+				object: certificateWithCondition("Ready", "False", "Issuing", "Issuing certificate"),
+				subErrors: []string{
+					"Resource did not satisfy condition Ready=True",
+					"[Issuing] Ready=False: Issuing certificate",
+				}},
+		},
+		{
+			description: "Should fail with current value if JSONPath does not match before timeout",
+			waitFor:     "jsonpath={.status.phase}=Running",
+			do: func(objects chan watch.Event, timeout chan time.Time) {
+				objects <- watchAddedEvent(certificateWithPhase("Pending"))
+
+				// Timeout. Failure.
+				timeout <- time.Now()
+			},
+			expectedError: &timeoutError{
+				object: certificateWithPhase("Pending"),
+				subErrors: []string{
+					"Resource did not satisfy {.status.phase}=Running",
+					`Waiting for {.status.phase}=Running (currently "Pending")`,
+				}},
+		},
+	}
+
+	for _, test := range tests {
+		inputs := certificateInput(test.waitFor)
+		waitFor, err := metadata.GetWaitFor(inputs)
+		assert.NoError(t, err, test.description)
+
+		awaiter := makeWaitForAwaiter(mockAwaitConfig(inputs), waitFor)
+		objects := make(chan watch.Event)
+
+		timeout := make(chan time.Time)
+		go test.do(objects, timeout)
+
+		err = awaiter.await(&chanWatcher{results: objects}, timeout)
+		assert.Equal(t, test.expectedError, err, test.description)
+	}
+}
+
+func Test_WaitForRead(t *testing.T) {
+	tests := []struct {
+		description       string
+		waitFor           string
+		object            *unstructured.Unstructured
+		expectedSubErrors []string
+	}{
+		{
+			description: "Read should fail if condition is not reported",
+			waitFor:     "condition=Ready",
+			object:      certificateWithPhase("Pending"),
+			expectedSubErrors: []string{
+				"Resource did not satisfy condition Ready=True",
+				`Waiting for condition "Ready" to be reported`,
+			},
+		},
+		{
+			description: "Read should succeed if condition is satisfied",
+			waitFor:     "condition=Ready",
+			object:      certificateWithCondition("Ready", "True", "", ""),
+		},
+	}
+
+	for _, test := range tests {
+		inputs := certificateInput(test.waitFor)
+		waitFor, err := metadata.GetWaitFor(inputs)
+		assert.NoError(t, err, test.description)
+
+		awaiter := makeWaitForAwaiter(mockAwaitConfig(inputs), waitFor)
+		err = awaiter.read(test.object)
+		if test.expectedSubErrors != nil {
+			assert.Equal(t, test.expectedSubErrors, err.(*initializationError).SubErrors(), test.description)
+		} else {
+			assert.Nil(t, err, test.description)
+		}
+	}
+}
+
+func Test_awaiterFor(t *testing.T) {
+	withWaitFor := certificateInput("condition=Ready")
+	_, exists := awaiterFor("cert-manager.io/v1/Certificate", withWaitFor)
+	assert.True(t, exists, "waitFor annotation should apply to types without built-in await logic")
+
+	withoutWaitFor := certificateInput("")
+	_, exists = awaiterFor("cert-manager.io/v1/Certificate", withoutWaitFor)
+	assert.False(t, exists, "types without built-in await logic should not be awaited")
+}
+
+// --------------------------------------------------------------------------
+
+// Custom resource objects.
+
+// --------------------------------------------------------------------------
+
+func certificateInput(waitFor string) *unstructured.Unstructured {
+	obj, err := decodeUnstructured(fmt.Sprintf(`{
+    "apiVersion": "cert-manager.io/v1",
+    "kind": "Certificate",
+    "metadata": {
+        "name": "foo",
+        "namespace": "default",
+        "annotations": {
+            "pulumi.com/waitFor": %q
+        }
+    },
+    "spec": {
+        "secretName": "foo-tls"
+    }
+}`, waitFor))
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+func certificateWithCondition(conditionType, status, reason, message string) *unstructured.Unstructured {
+	obj, err := decodeUnstructured(fmt.Sprintf(`{
+    "apiVersion": "cert-manager.io/v1",
+    "kind": "Certificate",
+    "metadata": {
+        "name": "foo",
+        "namespace": "default"
+    },
+    "spec": {
+        "secretName": "foo-tls"
+    },
+    "status": {
+        "conditions": [
+            {
+                "type": %q,
+                "status": %q,
+                "reason": %q,
+                "message": %q
+            }
+        ]
+    }
+}`, conditionType, status, reason, message))
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+func certificateWithPhase(phase string) *unstructured.Unstructured {
+	obj, err := decodeUnstructured(fmt.Sprintf(`{
+    "apiVersion": "cert-manager.io/v1",
+    "kind": "Certificate",
+    "metadata": {
+        "name": "foo",
+        "namespace": "default"
+    },
+    "spec": {
+        "secretName": "foo-tls"
+    },
+    "status": {
+        "phase": %q
+    }
+}`, phase))
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
@@ -27,10 +27,53 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 )
 
+// watch opens a watch on the objects of the specified GVK in the namespace that match the filter. A
+// nil filter selects every object. The watch is shared with other awaiters if the provider has a watch
+// multiplexer, and its events are recorded if recording is enabled.
+func (cac *createAwaitConfig) watch(
+	gvk schema.GroupVersionKind, namespace string, filter clients.WatchFilter,
+) (watch.Interface, error) {
+	if cac.watches != nil {
+		return cac.recorder.watch(cac.watches.Watch(gvk, namespace, filter))
+	}
+	client, err := cac.clientSet.ResourceClient(gvk, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return cac.recorder.watch(watchDirectly(client, filter))
+}
+
+// watchKind is like watch, but uses the preferred version of the specified kind.
+func (cac *createAwaitConfig) watchKind(
+	kind kinds.Kind, namespace string, filter clients.WatchFilter,
+) (watch.Interface, error) {
+	if cac.watches != nil {
+		return cac.recorder.watch(cac.watches.WatchKind(kind, namespace, filter))
+	}
+	client, err := clients.ResourceClient(kind, namespace, cac.clientSet)
+	if err != nil {
+		return nil, err
+	}
+	return cac.recorder.watch(watchDirectly(client, filter))
+}
+
+// watchDirectly opens a dedicated watch, and drops the events for objects that do not match the filter.
+func watchDirectly(client dynamic.ResourceInterface, filter clients.WatchFilter) (watch.Interface, error) {
+	w, err := client.Watch(context.TODO(), metav1.ListOptions{})
+	if err != nil || filter == nil {
+		return w, err
+	}
+	return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+		obj, isUnstructured := event.Object.(*unstructured.Unstructured)
+		return event, !isUnstructured || filter(obj)
+	}), nil
+}
+
 // PodAggregator tracks status for any Pods related to the owner resource, and writes
 // warning/error messages to a channel that can be consumed by a resource awaiter.
 type PodAggregator struct {
@@ -52,27 +95,27 @@ type PodAggregator struct {
 	messages chan logging.Messages
 }
 
-// NewPodAggregator returns an initialized PodAggregator.
-func NewPodAggregator(owner ResourceID, clientset *clients.DynamicClientSet) (*PodAggregator, error) {
+// NewPodAggregator returns an initialized PodAggregator, which tracks the Pods received from
+// `podWatcher`. If `podWatcher` is nil, the PodAggregator can only be used to Read the existing Pods.
+func NewPodAggregator(
+	owner ResourceID, clientset *clients.DynamicClientSet, podWatcher watch.Interface,
+) (*PodAggregator, error) {
 	client, err := clients.ResourceClient(kinds.Pod, owner.Namespace, clientset)
 	if err != nil {
 		return nil, err
 	}
 
-	watcher, err := client.Watch(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
 	pa := &PodAggregator{
 		stopped:  false,
 		owner:    owner,
 		checker:  states.NewPodChecker(),
 		client:   client,
-		watcher:  watcher,
+		watcher:  podWatcher,
 		messages: make(chan logging.Messages),
 	}
-	go pa.run()
+	if podWatcher != nil {
+		go pa.run()
+	}
 
 	return pa, nil
 }
@@ -96,18 +139,7 @@ func (pa *PodAggregator) run() {
 		}
 	}
 
-	// Get existing Pods.
-	pods, err := pa.client.List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		logger.V(3).Infof("Failed to list existing Pods: %v", err)
-	} else {
-		// Log errors and move on.
-		_ = pods.EachListItem(func(object runtime.Object) error {
-			checkPod(object)
-			return nil
-		})
-	}
-
+	// The watch starts with an `ADDED` event for each existing Pod.
 	for {
 		if pa.stopping() {
 			return
@@ -155,7 +187,9 @@ func (pa *PodAggregator) Stop() {
 	defer pa.Unlock()
 	if !pa.stopped {
 		pa.stopped = true
-		pa.watcher.Stop()
+		if pa.watcher != nil {
+			pa.watcher.Stop()
+		}
 	}
 }
 
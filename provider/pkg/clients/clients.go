@@ -210,6 +210,18 @@ func (lc *LogClient) Logs(namespace, name string) (io.ReadCloser, error) {
 	return req.Stream(context.TODO())
 }
 
+// TailLogs returns the last `lines` lines logged by a container in the specified Pod. If `previous` is true, the logs
+// of the previous instance of the container (e.g., before a crash and restart) are returned instead.
+func (lc *LogClient) TailLogs(namespace, name, container string, previous bool, lines int64) (string, error) {
+	podLogOpts := corev1.PodLogOptions{Container: container, Previous: previous, TailLines: &lines}
+	req := lc.clientset.CoreV1().Pods(namespace).GetLogs(name, &podLogOpts)
+	logs, err := req.DoRaw(context.TODO())
+	if err != nil {
+		return "", err
+	}
+	return string(logs), nil
+}
+
 type NoNamespaceInfoErr struct {
 	gvk schema.GroupVersionKind
 }
@@ -35,6 +35,8 @@ func FromUnstructured(obj *unstructured.Unstructured) (metav1.Object, error) {
 
 	var output metav1.Object
 	switch kinds.Kind(obj.GetKind()) {
+	case kinds.DaemonSet:
+		output = new(appsv1.DaemonSet)
 	case kinds.Deployment:
 		output = new(appsv1.Deployment)
 	case kinds.Job:
@@ -49,6 +51,8 @@ func FromUnstructured(obj *unstructured.Unstructured) (metav1.Object, error) {
 		output = new(corev1.Pod)
 	case kinds.ReplicaSet:
 		output = new(appsv1.ReplicaSet)
+	case kinds.Service:
+		output = new(corev1.Service)
 	case kinds.StatefulSet:
 		output = new(appsv1.StatefulSet)
 	default:
@@ -0,0 +1,429 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
+	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// watchRetryPeriod is the time to wait before re-establishing a watch or re-listing after a failure.
+const watchRetryPeriod = time.Second
+
+// WatchFilter selects the objects whose events are delivered to a subscriber of a WatchMultiplexer.
+type WatchFilter func(obj *unstructured.Unstructured) bool
+
+// ObjectNamed returns a WatchFilter that selects the object with the specified name.
+func ObjectNamed(name string) WatchFilter {
+	return func(obj *unstructured.Unstructured) bool {
+		return obj.GetName() == name
+	}
+}
+
+// WatchMultiplexer shares watches between awaiters, so that awaiting many resources at once (e.g., the
+// resources of a large Helm chart) does not open a watch connection per resource. Watches are keyed by
+// GVK and namespace, and are reference-counted: the first subscriber to a key lists the existing objects
+// and starts a watch, and the watch is stopped once the last subscriber stops.
+//
+// Like a new watch, a subscription starts with an `ADDED` event for every existing object that matches
+// its filter, followed by the events of the shared watch. If the server closes the shared watch, it is
+// resumed from the last resource version; if that version has expired (`410 Gone`), the objects are
+// re-listed, and the differences are delivered to subscribers as `ADDED`, `MODIFIED` and `DELETED`
+// events.
+type WatchMultiplexer struct {
+	clientSet *DynamicClientSet
+
+	// resourceClient returns the client used to list and watch the objects of a key.
+	resourceClient func(gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error)
+
+	mu      sync.Mutex
+	watches map[watchKey]*sharedWatch
+}
+
+// NewWatchMultiplexer returns an initialized WatchMultiplexer.
+func NewWatchMultiplexer(clientSet *DynamicClientSet) *WatchMultiplexer {
+	return &WatchMultiplexer{
+		clientSet:      clientSet,
+		resourceClient: clientSet.ResourceClient,
+		watches:        map[watchKey]*sharedWatch{},
+	}
+}
+
+type watchKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+}
+
+// Watch subscribes to the events for the objects of the specified GVK in the namespace that match the
+// filter. A nil filter selects every object. The subscription must be stopped when it is no longer
+// needed.
+func (m *WatchMultiplexer) Watch(
+	gvk schema.GroupVersionKind, namespace string, filter WatchFilter,
+) (watch.Interface, error) {
+	key := watchKey{gvk: gvk, namespace: NamespaceOrDefault(namespace)}
+	if known, namespaced := kinds.Kind(gvk.Kind).Namespaced(); known && !namespaced {
+		key.namespace = ""
+	}
+
+	m.mu.Lock()
+	sw, exists := m.watches[key]
+	if !exists {
+		sw = &sharedWatch{
+			multiplexer: m,
+			key:         key,
+			objects:     map[string]*unstructured.Unstructured{},
+			subscribers: map[*subscription]struct{}{},
+			ready:       make(chan struct{}),
+		}
+		sw.ctx, sw.cancel = context.WithCancel(context.Background())
+		m.watches[key] = sw
+		go sw.start()
+	}
+	sw.refs++
+	m.mu.Unlock()
+
+	<-sw.ready
+	if sw.err != nil {
+		m.release(sw)
+		return nil, sw.err
+	}
+	return sw.subscribe(filter), nil
+}
+
+// WatchKind is like Watch, but uses the preferred version of the specified kind.
+func (m *WatchMultiplexer) WatchKind(kind kinds.Kind, namespace string, filter WatchFilter) (watch.Interface, error) {
+	gvk, err := m.clientSet.gvkForKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	return m.Watch(*gvk, namespace, filter)
+}
+
+// release drops a reference to the shared watch, and stops it if it is no longer referenced.
+func (m *WatchMultiplexer) release(sw *sharedWatch) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sw.refs--
+	if sw.refs > 0 {
+		return
+	}
+	if m.watches[sw.key] == sw {
+		delete(m.watches, sw.key)
+	}
+	sw.cancel()
+}
+
+// forget removes a shared watch whose initial list failed, so that later subscribers try again.
+func (m *WatchMultiplexer) forget(sw *sharedWatch) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.watches[sw.key] == sw {
+		delete(m.watches, sw.key)
+	}
+}
+
+// sharedWatch is the watch shared by the subscribers of a key.
+type sharedWatch struct {
+	multiplexer *WatchMultiplexer
+	key         watchKey
+	client      dynamic.ResourceInterface
+	ctx         context.Context
+	cancel      context.CancelFunc
+
+	// refs is the number of subscribers, and is guarded by the multiplexer's lock.
+	refs int
+
+	// ready is closed once the initial list completes; err is set if it failed.
+	ready chan struct{}
+	err   error
+
+	mu              sync.Mutex
+	resourceVersion string
+	objects         map[string]*unstructured.Unstructured
+	subscribers     map[*subscription]struct{}
+}
+
+func (sw *sharedWatch) start() {
+	sw.client, sw.err = sw.multiplexer.resourceClient(sw.key.gvk, sw.key.namespace)
+	if sw.err == nil {
+		sw.err = sw.list()
+	}
+	if sw.err != nil {
+		sw.multiplexer.forget(sw)
+		close(sw.ready)
+		return
+	}
+	close(sw.ready)
+
+	sw.run()
+}
+
+// run watches the objects until the shared watch is stopped, resuming the watch when the server closes
+// it, and re-listing the objects when the resource version has expired.
+func (sw *sharedWatch) run() {
+	relist := false
+	for {
+		if relist {
+			if err := sw.list(); err != nil {
+				logger.V(3).Infof("Failed to re-list %s: %v", sw.key.gvk.Kind, err)
+				if !sw.sleep() {
+					return
+				}
+				continue
+			}
+			relist = false
+		}
+
+		sw.mu.Lock()
+		resourceVersion := sw.resourceVersion
+		sw.mu.Unlock()
+
+		w, err := sw.client.Watch(sw.ctx, metav1.ListOptions{
+			ResourceVersion:     resourceVersion,
+			AllowWatchBookmarks: true,
+		})
+		if err != nil {
+			if sw.ctx.Err() != nil {
+				return
+			}
+			logger.V(3).Infof("Failed to watch %s: %v", sw.key.gvk.Kind, err)
+			relist = isExpired(err)
+			if !sw.sleep() {
+				return
+			}
+			continue
+		}
+
+		relist = sw.consume(w)
+		w.Stop()
+		if sw.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// consume processes the events of a watch until it is closed, and returns true if the objects must be
+// re-listed.
+func (sw *sharedWatch) consume(w watch.Interface) bool {
+	for {
+		select {
+		case <-sw.ctx.Done():
+			return false
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return false
+			}
+			switch event.Type {
+			case watch.Error:
+				err := errors.FromObject(event.Object)
+				logger.V(3).Infof("Watch of %s failed: %v", sw.key.gvk.Kind, err)
+				return isExpired(err)
+			case watch.Bookmark:
+				if obj, isUnstructured := event.Object.(*unstructured.Unstructured); isUnstructured {
+					sw.mu.Lock()
+					sw.resourceVersion = obj.GetResourceVersion()
+					sw.mu.Unlock()
+				}
+			default:
+				obj, isUnstructured := event.Object.(*unstructured.Unstructured)
+				if !isUnstructured {
+					continue
+				}
+				sw.mu.Lock()
+				sw.resourceVersion = obj.GetResourceVersion()
+				if event.Type == watch.Deleted {
+					delete(sw.objects, objectKey(obj))
+				} else {
+					sw.objects[objectKey(obj)] = obj
+				}
+				sw.broadcast(event.Type, obj)
+				sw.mu.Unlock()
+			}
+		}
+	}
+}
+
+// list lists the objects, and delivers the differences from the cached objects to the subscribers.
+func (sw *sharedWatch) list() error {
+	list, err := sw.client.List(sw.ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	listed := map[string]*unstructured.Unstructured{}
+	for i := range list.Items {
+		obj := &list.Items[i]
+		key := objectKey(obj)
+		listed[key] = obj
+
+		if cached, exists := sw.objects[key]; !exists {
+			sw.broadcast(watch.Added, obj)
+		} else if cached.GetResourceVersion() != obj.GetResourceVersion() {
+			sw.broadcast(watch.Modified, obj)
+		}
+	}
+	for key, cached := range sw.objects {
+		if _, exists := listed[key]; !exists {
+			sw.broadcast(watch.Deleted, cached)
+		}
+	}
+
+	sw.objects = listed
+	sw.resourceVersion = list.GetResourceVersion()
+	return nil
+}
+
+// sleep waits before retrying, and returns false if the shared watch was stopped in the meantime.
+func (sw *sharedWatch) sleep() bool {
+	select {
+	case <-sw.ctx.Done():
+		return false
+	case <-time.After(watchRetryPeriod):
+		return true
+	}
+}
+
+// subscribe adds a subscriber, which first receives the cached objects that match its filter.
+func (sw *sharedWatch) subscribe(filter WatchFilter) *subscription {
+	s := &subscription{
+		filter:  filter,
+		results: make(chan watch.Event),
+		done:    make(chan struct{}),
+		unsubscribe: func(s *subscription) {
+			sw.mu.Lock()
+			delete(sw.subscribers, s)
+			sw.mu.Unlock()
+			sw.multiplexer.release(sw)
+		},
+	}
+	s.cond = sync.NewCond(&s.mu)
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	keys := make([]string, 0, len(sw.objects))
+	for key := range sw.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s.push(watch.Added, sw.objects[key])
+	}
+	sw.subscribers[s] = struct{}{}
+
+	go s.forward()
+	return s
+}
+
+// broadcast delivers an event to the subscribers. The caller must hold the lock.
+func (sw *sharedWatch) broadcast(eventType watch.EventType, obj *unstructured.Unstructured) {
+	for s := range sw.subscribers {
+		s.push(eventType, obj)
+	}
+}
+
+// subscription is a `watch.Interface` that receives the events of a shared watch. Events are queued, so
+// that a slow subscriber does not hold up the others.
+type subscription struct {
+	filter      WatchFilter
+	unsubscribe func(s *subscription)
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []watch.Event
+	stopped bool
+
+	results  chan watch.Event
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+var _ watch.Interface = (*subscription)(nil)
+
+func (s *subscription) push(eventType watch.EventType, obj *unstructured.Unstructured) {
+	if s.filter != nil && !s.filter(obj) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Copy the object, since every subscriber owns the objects it receives.
+	s.pending = append(s.pending, watch.Event{Type: eventType, Object: obj.DeepCopy()})
+	s.cond.Signal()
+}
+
+func (s *subscription) forward() {
+	defer close(s.results)
+	for {
+		s.mu.Lock()
+		for len(s.pending) == 0 && !s.stopped {
+			s.cond.Wait()
+		}
+		if s.stopped {
+			s.mu.Unlock()
+			return
+		}
+		event := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.results <- event:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *subscription) Stop() {
+	s.stopOnce.Do(func() {
+		s.mu.Lock()
+		s.stopped = true
+		s.cond.Broadcast()
+		s.mu.Unlock()
+		close(s.done)
+		s.unsubscribe(s)
+	})
+}
+
+func (s *subscription) ResultChan() <-chan watch.Event {
+	return s.results
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// isExpired returns true if the error means that the requested resource version is too old.
+func isExpired(err error) bool {
+	return errors.IsResourceExpired(err) || errors.IsGone(err)
+}
@@ -0,0 +1,236 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+var configMapGVK = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+// fakeResourceClient serves List from a fixed set of objects, and hands every watch it opens to the
+// test through the `watches` channel.
+type fakeResourceClient struct {
+	dynamic.ResourceInterface
+
+	mu              sync.Mutex
+	objects         []unstructured.Unstructured
+	resourceVersion string
+	listErr         error
+	lists           int
+	watchVersions   []string
+
+	watches chan *watch.FakeWatcher
+}
+
+func newFakeResourceClient(resourceVersion string, objects ...*unstructured.Unstructured) *fakeResourceClient {
+	c := &fakeResourceClient{watches: make(chan *watch.FakeWatcher, 10)}
+	c.setObjects(resourceVersion, objects...)
+	return c
+}
+
+func (c *fakeResourceClient) setObjects(resourceVersion string, objects ...*unstructured.Unstructured) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.resourceVersion = resourceVersion
+	c.objects = nil
+	for _, obj := range objects {
+		c.objects = append(c.objects, *obj)
+	}
+}
+
+func (c *fakeResourceClient) List(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lists++
+	if c.listErr != nil {
+		return nil, c.listErr
+	}
+	list := &unstructured.UnstructuredList{Items: append([]unstructured.Unstructured{}, c.objects...)}
+	list.SetResourceVersion(c.resourceVersion)
+	return list, nil
+}
+
+func (c *fakeResourceClient) Watch(_ context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	c.mu.Lock()
+	c.watchVersions = append(c.watchVersions, opts.ResourceVersion)
+	c.mu.Unlock()
+
+	w := watch.NewFake()
+	c.watches <- w
+	return w, nil
+}
+
+func newTestMultiplexer(client dynamic.ResourceInterface) *WatchMultiplexer {
+	return &WatchMultiplexer{
+		resourceClient: func(schema.GroupVersionKind, string) (dynamic.ResourceInterface, error) {
+			return client, nil
+		},
+		watches: map[watchKey]*sharedWatch{},
+	}
+}
+
+func configMap(name, resourceVersion string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetResourceVersion(resourceVersion)
+	return obj
+}
+
+func nextWatch(t *testing.T, c *fakeResourceClient) *watch.FakeWatcher {
+	select {
+	case w := <-c.watches:
+		return w
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for watch")
+		return nil
+	}
+}
+
+// nextEvent returns a summary of the next event received by the subscription.
+func nextEvent(t *testing.T, w watch.Interface) string {
+	select {
+	case event := <-w.ResultChan():
+		obj := event.Object.(*unstructured.Unstructured)
+		return fmt.Sprintf("%s %s@%s", event.Type, obj.GetName(), obj.GetResourceVersion())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return ""
+	}
+}
+
+func TestWatchMultiplexer_SharesWatches(t *testing.T) {
+	client := newFakeResourceClient("10", configMap("foo", "1"), configMap("bar", "2"))
+	m := newTestMultiplexer(client)
+
+	all, err := m.Watch(configMapGVK, "default", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	foo, err := m.Watch(configMapGVK, "", ObjectNamed("foo"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	w := nextWatch(t, client)
+
+	// Subscriptions start with the existing objects.
+	assert.Equal(t, "ADDED bar@2", nextEvent(t, all))
+	assert.Equal(t, "ADDED foo@1", nextEvent(t, all))
+	assert.Equal(t, "ADDED foo@1", nextEvent(t, foo))
+
+	w.Modify(configMap("bar", "11"))
+	w.Modify(configMap("foo", "12"))
+	assert.Equal(t, "MODIFIED bar@11", nextEvent(t, all))
+	assert.Equal(t, "MODIFIED foo@12", nextEvent(t, all))
+	assert.Equal(t, "MODIFIED foo@12", nextEvent(t, foo))
+
+	// Both subscribers share a single list and watch.
+	assert.Equal(t, 1, client.lists)
+	assert.Equal(t, []string{"10"}, client.watchVersions)
+
+	// The shared watch is stopped once the last subscriber stops.
+	all.Stop()
+	assert.Len(t, m.watches, 1)
+	foo.Stop()
+	assert.Len(t, m.watches, 0)
+}
+
+func TestWatchMultiplexer_Resumes(t *testing.T) {
+	client := newFakeResourceClient("10", configMap("foo", "1"))
+	m := newTestMultiplexer(client)
+
+	foo, err := m.Watch(configMapGVK, "default", ObjectNamed("foo"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer foo.Stop()
+	assert.Equal(t, "ADDED foo@1", nextEvent(t, foo))
+
+	// The server closes the watch. It is resumed from the last resource version.
+	w := nextWatch(t, client)
+	w.Modify(configMap("foo", "11"))
+	assert.Equal(t, "MODIFIED foo@11", nextEvent(t, foo))
+	w.Stop()
+
+	w = nextWatch(t, client)
+	w.Delete(configMap("foo", "12"))
+	assert.Equal(t, "DELETED foo@12", nextEvent(t, foo))
+
+	assert.Equal(t, 1, client.lists)
+	assert.Equal(t, []string{"10", "11"}, client.watchVersions)
+}
+
+func TestWatchMultiplexer_RelistsWhenGone(t *testing.T) {
+	client := newFakeResourceClient("10", configMap("foo", "1"), configMap("bar", "2"))
+	m := newTestMultiplexer(client)
+
+	all, err := m.Watch(configMapGVK, "default", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer all.Stop()
+	assert.Equal(t, "ADDED bar@2", nextEvent(t, all))
+	assert.Equal(t, "ADDED foo@1", nextEvent(t, all))
+
+	// While the watch was down, "foo" changed, "bar" was deleted, and "baz" was created.
+	client.setObjects("20", configMap("foo", "15"), configMap("baz", "18"))
+	w := nextWatch(t, client)
+	gone := errors.NewResourceExpired("too old resource version: 10 (15)")
+	w.Error(&gone.ErrStatus)
+
+	// The objects are re-listed, and the differences are delivered.
+	assert.Equal(t, "MODIFIED foo@15", nextEvent(t, all))
+	assert.Equal(t, "ADDED baz@18", nextEvent(t, all))
+	assert.Equal(t, "DELETED bar@2", nextEvent(t, all))
+
+	nextWatch(t, client)
+	assert.Equal(t, 2, client.lists)
+	assert.Equal(t, []string{"10", "20"}, client.watchVersions)
+}
+
+func TestWatchMultiplexer_ListError(t *testing.T) {
+	client := newFakeResourceClient("10")
+	client.listErr = errors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "", fmt.Errorf("denied"))
+	m := newTestMultiplexer(client)
+
+	_, err := m.Watch(configMapGVK, "default", nil)
+	assert.True(t, errors.IsForbidden(err))
+	assert.Len(t, m.watches, 0)
+
+	// Later subscribers try again.
+	client.listErr = nil
+	w, err := m.Watch(configMapGVK, "default", nil)
+	if assert.NoError(t, err) {
+		w.Stop()
+	}
+	assert.Equal(t, 2, client.lists)
+}
@@ -70,6 +70,42 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 					Description: "If present and set to true, suppress apiVersion deprecation warnings from the CLI.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `suppressDeprecationWarnings` parameter.\n2. The `PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS` environment variable.",
 					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
 				},
+				"rollbackOnFailure": {
+					Description: "If present and set to true, roll back Deployments whose update fails to become ready by re-applying\ntheir previous configuration. The update is still reported as failed.\n\nThis config can be overridden for a resource with the `pulumi.com/rollbackOnFailure` annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `rollbackOnFailure` parameter.\n2. The `PULUMI_K8S_ROLLBACK_ON_FAILURE` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"recordAwaitEventsToDirectory": {
+					Description: "If present, the watch events received while awaiting resources are recorded as JSON files in this\ndirectory, so that they can be replayed in tests of the await logic.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `recordAwaitEventsToDirectory` parameter.\n2. The `PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"streamEvents": {
+					Description: "If present and set to true, Warning Events reported for resources while they are awaited (and for the Pods and\nReplicaSets they own) are shown as status messages as they arrive, e.g., a Pod that cannot be scheduled.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `streamEvents` parameter.\n2. The `PULUMI_K8S_STREAM_EVENTS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"defaultTimeouts": {
+					Description: "A JSON object that overrides the default await timeouts, in seconds, for the kinds of resources it lists, e.g.,\n`{\"apps/v1/Deployment\": 1200, \"Job\": 3600, \"multiplier\": 2}`. Keys are kinds, optionally qualified with their\napiVersion, which take precedence. The optional `multiplier` entry scales every default timeout. Timeouts specified for\na resource with `customTimeouts` or the `pulumi.com/timeoutSeconds` annotation are not affected.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `defaultTimeouts` parameter.\n2. The `PULUMI_K8S_DEFAULT_TIMEOUTS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"enableServerSideApply": {
+					Description: "If present and set to true, create and update resources with server-side apply, rather than with patches computed by\nthe provider. Fields set by other controllers are left as they are, and the\n`kubectl.kubernetes.io/last-applied-configuration` annotation is not written. Applies that set fields owned by other\nfield managers fail, unless the resource has the `pulumi.com/patchForce` annotation. Creating an object that\nalready exists also fails, unless it has the annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `enableServerSideApply` parameter.\n2. The `PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"fieldManager": {
+					Description: "The name of the field manager used for server-side apply. Defaults to `pulumi-kubernetes`.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `fieldManager` parameter.\n2. The `PULUMI_K8S_FIELD_MANAGER` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"deletePropagationPolicy": {
+					Description: "If present, the propagation policy of the delete requests for resources: `Foreground`, `Background` or `Orphan`. The default depends on the version of the cluster, and is `Background` for current versions. This can be overridden for a resource with the `pulumi.com/deletePropagationPolicy` annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `deletePropagationPolicy` parameter.\n2. The `PULUMI_K8S_DELETE_PROPAGATION_POLICY` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"deleteGracePeriodSeconds": {
+					Description: "If present, the grace period in seconds of the delete requests for resources. The default is the grace period of the resource, e.g., `terminationGracePeriodSeconds` for Pods. This can be overridden for a resource with the `pulumi.com/deleteGracePeriodSeconds` annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `deleteGracePeriodSeconds` parameter.\n2. The `PULUMI_K8S_DELETE_GRACE_PERIOD_SECONDS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "integer"},
+				},
+				"ignoreFields": {
+					Description: "If present, the fields of resources that are managed by someone else, e.g., `spec.replicas` of Deployments that are scaled by a HorizontalPodAutoscaler. This is a JSON object that maps a GVK (e.g., `apps/v1/Deployment`) or a kind (e.g., `MutatingWebhookConfiguration`) to a list of field paths (e.g., `[\"webhooks[*].clientConfig.caBundle\"]`). Changes to these fields are not shown in diffs, and updates preserve their live values, or leave them out of the applied object with server-side apply. Fields can also be ignored for a resource with the `pulumi.com/ignoreFields` annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `ignoreFields` parameter.\n2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
 			},
 		},
 
@@ -128,6 +164,82 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 					Description: "If present and set to true, suppress apiVersion deprecation warnings from the CLI.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `suppressDeprecationWarnings` parameter.\n2. The `PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS` environment variable.",
 					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
 				},
+				"rollbackOnFailure": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_ROLLBACK_ON_FAILURE",
+						},
+					},
+					Description: "If present and set to true, roll back Deployments whose update fails to become ready by re-applying\ntheir previous configuration. The update is still reported as failed.\n\nThis config can be overridden for a resource with the `pulumi.com/rollbackOnFailure` annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `rollbackOnFailure` parameter.\n2. The `PULUMI_K8S_ROLLBACK_ON_FAILURE` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"recordAwaitEventsToDirectory": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY",
+						},
+					},
+					Description: "If present, the watch events received while awaiting resources are recorded as JSON files in this\ndirectory, so that they can be replayed in tests of the await logic.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `recordAwaitEventsToDirectory` parameter.\n2. The `PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"streamEvents": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_STREAM_EVENTS",
+						},
+					},
+					Description: "If present and set to true, Warning Events reported for resources while they are awaited (and for the Pods and\nReplicaSets they own) are shown as status messages as they arrive, e.g., a Pod that cannot be scheduled.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `streamEvents` parameter.\n2. The `PULUMI_K8S_STREAM_EVENTS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"defaultTimeouts": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_DEFAULT_TIMEOUTS",
+						},
+					},
+					Description: "A JSON object that overrides the default await timeouts, in seconds, for the kinds of resources it lists, e.g.,\n`{\"apps/v1/Deployment\": 1200, \"Job\": 3600, \"multiplier\": 2}`. Keys are kinds, optionally qualified with their\napiVersion, which take precedence. The optional `multiplier` entry scales every default timeout. Timeouts specified for\na resource with `customTimeouts` or the `pulumi.com/timeoutSeconds` annotation are not affected.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `defaultTimeouts` parameter.\n2. The `PULUMI_K8S_DEFAULT_TIMEOUTS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"enableServerSideApply": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY",
+						},
+					},
+					Description: "If present and set to true, create and update resources with server-side apply, rather than with patches computed by\nthe provider. Fields set by other controllers are left as they are, and the\n`kubectl.kubernetes.io/last-applied-configuration` annotation is not written. Applies that set fields owned by other\nfield managers fail, unless the resource has the `pulumi.com/patchForce` annotation. Creating an object that\nalready exists also fails, unless it has the annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `enableServerSideApply` parameter.\n2. The `PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+				},
+				"fieldManager": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_FIELD_MANAGER",
+						},
+					},
+					Description: "The name of the field manager used for server-side apply. Defaults to `pulumi-kubernetes`.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `fieldManager` parameter.\n2. The `PULUMI_K8S_FIELD_MANAGER` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"deletePropagationPolicy": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_DELETE_PROPAGATION_POLICY",
+						},
+					},
+					Description: "If present, the propagation policy of the delete requests for resources: `Foreground`, `Background` or `Orphan`. The default depends on the version of the cluster, and is `Background` for current versions. This can be overridden for a resource with the `pulumi.com/deletePropagationPolicy` annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `deletePropagationPolicy` parameter.\n2. The `PULUMI_K8S_DELETE_PROPAGATION_POLICY` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
+				"deleteGracePeriodSeconds": {
+					Description: "If present, the grace period in seconds of the delete requests for resources. The default is the grace period of the resource, e.g., `terminationGracePeriodSeconds` for Pods. This can be overridden for a resource with the `pulumi.com/deleteGracePeriodSeconds` annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `deleteGracePeriodSeconds` parameter.\n2. The `PULUMI_K8S_DELETE_GRACE_PERIOD_SECONDS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "integer"},
+				},
+				"ignoreFields": {
+					DefaultInfo: &pschema.DefaultSpec{
+						Environment: []string{
+							"PULUMI_K8S_IGNORE_FIELDS",
+						},
+					},
+					Description: "If present, the fields of resources that are managed by someone else, e.g., `spec.replicas` of Deployments that are scaled by a HorizontalPodAutoscaler. This is a JSON object that maps a GVK (e.g., `apps/v1/Deployment`) or a kind (e.g., `MutatingWebhookConfiguration`) to a list of field paths (e.g., `[\"webhooks[*].clientConfig.caBundle\"]`). Changes to these fields are not shown in diffs, and updates preserve their live values, or leave them out of the applied object with server-side apply. Fields can also be ignored for a resource with the `pulumi.com/ignoreFields` annotation.\n\nThis config can be specified in the following ways, using this precedence:\n1. This `ignoreFields` parameter.\n2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.",
+					TypeSpec:    pschema.TypeSpec{Type: "string"},
+				},
 			},
 		},
 
@@ -206,6 +318,11 @@ func PulumiSchema(swagger map[string]interface{}) pschema.PackageSpec {
 		}
 	}
 
+	// Patch resources manage some of the fields of an object that was created by someone else.
+	pkg.Resources[PatchResourceToken] = patchResourceSpec()
+	csharpNamespaces["core"] = "Core"
+	modToPkg["core"] = "core"
+
 	// Compatibility mode for Kubernetes 2.0 SDK
 	const kubernetes20 = "kubernetes20"
 
@@ -285,6 +402,68 @@ If this is your first time using this package, these two resources may be helpfu
 	return pkg
 }
 
+// PatchResourceToken is the token of the Patch resource. It is not a Kubernetes kind, so it is excluded from the SDK
+// overlays that handle Kubernetes objects (e.g., YAML), and from the generated kinds.
+const PatchResourceToken = "kubernetes:core:Patch"
+
+// patchResourceSpec returns the schema of the Patch resource, which applies the specified fields to an existing object
+// with server-side apply, rather than managing the whole object.
+func patchResourceSpec() pschema.ResourceSpec {
+	properties := map[string]pschema.PropertySpec{
+		"apiVersion": {
+			Description: "The apiVersion of the object to patch, e.g., `apps/v1`.",
+			TypeSpec:    pschema.TypeSpec{Type: "string"},
+		},
+		"kind": {
+			Description: "The kind of the object to patch, e.g., `DaemonSet`.",
+			TypeSpec:    pschema.TypeSpec{Type: "string"},
+		},
+		"metadata": {
+			Description: "The name and namespace of the object to patch, and the labels and annotations to set on it.",
+			TypeSpec:    pschema.TypeSpec{Ref: "#/types/kubernetes:meta/v1:ObjectMeta"},
+		},
+		"fields": {
+			Description: "The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, " +
+				"e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch " +
+				"are removed from the object.",
+			TypeSpec: pschema.TypeSpec{
+				Type:                 "object",
+				AdditionalProperties: &pschema.TypeSpec{Ref: "pulumi.json#/Any"},
+			},
+		},
+		"force": {
+			Description: "If true, the Patch takes ownership of the fields that it sets, even if other field " +
+				"managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, " +
+				"applying a field that another field manager owns fails with a conflict. The " +
+				"`pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the " +
+				"patched object.",
+			TypeSpec: pschema.TypeSpec{Type: "boolean"},
+		},
+		"retainOnDelete": {
+			Description: "If true, the patched fields are left in place when the Patch is deleted. By default, " +
+				"they are removed from the object, unless another field manager also sets them.",
+			TypeSpec: pschema.TypeSpec{Type: "boolean"},
+		},
+	}
+	required := []string{"apiVersion", "kind", "metadata"}
+
+	return pschema.ResourceSpec{
+		ObjectTypeSpec: pschema.ObjectTypeSpec{
+			Description: "Patch applies some of the fields of an existing object, e.g., a label on the " +
+				"`kube-system` Namespace, or an annotation on the Pod template of a DaemonSet that is managed by " +
+				"someone else. The fields are applied with server-side apply, so that fields set by other field " +
+				"managers are left as they are. Deleting the Patch removes its fields from the object, unless " +
+				"`retainOnDelete` is set. Atomic lists, e.g., `tolerations`, are replaced as a whole, so a Patch " +
+				"that sets one must set all of its items.",
+			Type:       "object",
+			Properties: properties,
+			Required:   required,
+		},
+		InputProperties: properties,
+		RequiredInputs:  required,
+	}
+}
+
 func genPropertySpec(p Property, resourceGV string, resourceKind string) pschema.PropertySpec {
 	var typ pschema.TypeSpec
 	err := json.Unmarshal([]byte(p.SchemaType()), &typ)
@@ -31,6 +31,20 @@ const (
 	AnnotationSkipAwait         = AnnotationPrefix + "skipAwait"
 	AnnotationTimeoutSeconds    = AnnotationPrefix + "timeoutSeconds"
 	AnnotationInitialAPIVersion = AnnotationPrefix + "initialApiVersion"
+	AnnotationWaitFor           = AnnotationPrefix + "waitFor"
+	AnnotationRollbackOnFailure = AnnotationPrefix + "rollbackOnFailure"
+	AnnotationFailureLogLines   = AnnotationPrefix + "failureLogLines"
+	AnnotationPatchForce        = AnnotationPrefix + "patchForce"
+	AnnotationIgnoreFields      = AnnotationPrefix + "ignoreFields"
+
+	AnnotationWaitForDefaultServiceAccount = AnnotationPrefix + "waitForDefaultServiceAccount"
+
+	AnnotationRemoveFinalizers             = AnnotationPrefix + "removeFinalizers"
+	AnnotationRemoveFinalizersAfterSeconds = AnnotationPrefix + "removeFinalizersAfterSeconds"
+
+	AnnotationDeletionPolicy           = AnnotationPrefix + "deletionPolicy"
+	AnnotationDeletePropagationPolicy  = AnnotationPrefix + "deletePropagationPolicy"
+	AnnotationDeleteGracePeriodSeconds = AnnotationPrefix + "deleteGracePeriodSeconds"
 )
 
 // Annotations for internal Pulumi use only.
@@ -0,0 +1,220 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v2/go/common/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// fieldPathWildcard matches every element of a list in a field path, e.g., `webhooks[*].clientConfig.caBundle`.
+const fieldPathWildcard = "[*]"
+
+// anyIndex is the element of a FieldPath that matches every element of a list.
+type anyIndex struct{}
+
+// FieldPath is the path of a field of an object. Its elements are field names, list indices, or wildcards that match
+// every element of a list.
+type FieldPath []interface{}
+
+// ParseFieldPath parses the path of a field, using the syntax of Pulumi property paths (e.g., `spec.replicas` or
+// `metadata.annotations["sidecar.istio.io/status"]`), extended with `[*]` to match every element of a list.
+func ParseFieldPath(s string) (FieldPath, error) {
+	var path FieldPath
+	for i, segment := range strings.Split(s, fieldPathWildcard) {
+		if i > 0 {
+			path = append(path, anyIndex{})
+		}
+		if segment == "" {
+			continue
+		}
+		elements, err := resource.ParsePropertyPath(segment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field path %q: %v", s, err)
+		}
+		path = append(path, elements...)
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("invalid field path %q: path is empty", s)
+	}
+	if _, isField := path[0].(string); !isField {
+		return nil, fmt.Errorf("invalid field path %q: path must start with a field name", s)
+	}
+	return path, nil
+}
+
+// IgnoreFieldsRules maps a GVK (e.g., "apps/v1/Deployment") or a kind (e.g., "Deployment") to the paths of the fields
+// to ignore for objects of that type, as set with the `ignoreFields` provider config.
+type IgnoreFieldsRules map[string][]FieldPath
+
+// ParseIgnoreFieldsRules parses the value of the `ignoreFields` provider config, a JSON object that maps GVKs or kinds
+// to lists of field paths, e.g., `{"apps/v1/Deployment": ["spec.replicas"]}`.
+func ParseIgnoreFieldsRules(s string) (IgnoreFieldsRules, error) {
+	var entries map[string][]string
+	if err := json.Unmarshal([]byte(s), &entries); err != nil {
+		return nil, fmt.Errorf("ignoreFields must be a JSON object mapping kinds to lists of field paths: %v", err)
+	}
+
+	rules := IgnoreFieldsRules{}
+	for key, paths := range entries {
+		for _, p := range paths {
+			path, err := ParseFieldPath(p)
+			if err != nil {
+				return nil, fmt.Errorf("ignoreFields entry %q: %v", key, err)
+			}
+			rules[key] = append(rules[key], path)
+		}
+	}
+	return rules, nil
+}
+
+// IgnoredFields returns the paths of the fields of the object that are managed by someone else: the comma-separated
+// paths of the `pulumi.com/ignoreFields` annotation, and the paths of the provider rules for its GVK and kind.
+func IgnoredFields(obj *unstructured.Unstructured, rules IgnoreFieldsRules) ([]FieldPath, error) {
+	var paths []FieldPath
+	for _, p := range splitFieldPaths(GetAnnotationValue(obj, AnnotationIgnoreFields)) {
+		path, err := ParseFieldPath(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %v", AnnotationIgnoreFields, err)
+		}
+		paths = append(paths, path)
+	}
+
+	paths = append(paths, rules[obj.GetAPIVersion()+"/"+obj.GetKind()]...)
+	paths = append(paths, rules[obj.GetKind()]...)
+	return paths, nil
+}
+
+// splitFieldPaths splits a comma-separated list of field paths, ignoring commas inside quoted field names.
+func splitFieldPaths(s string) []string {
+	var paths []string
+	start, quoted := 0, false
+	for i := 0; i <= len(s); i++ {
+		switch {
+		case i < len(s) && s[i] == '\\' && quoted:
+			i++
+		case i < len(s) && s[i] == '"':
+			quoted = !quoted
+		case i == len(s) || (s[i] == ',' && !quoted):
+			if p := strings.TrimSpace(s[start:i]); p != "" {
+				paths = append(paths, p)
+			}
+			start = i + 1
+		}
+	}
+	return paths
+}
+
+// PreserveIgnoredFields sets the ignored fields of inputs to their values in live, so that a patch computed from the
+// inputs leaves them as they are. Fields that are not set in both inputs and live are left unchanged, so fields that
+// are only set by someone else are not added to the inputs. The inputs are modified in place.
+func PreserveIgnoredFields(inputs, live *unstructured.Unstructured, paths []FieldPath) {
+	if inputs == nil || live == nil {
+		return
+	}
+	for _, path := range paths {
+		copyField(inputs.Object, live.Object, path)
+	}
+}
+
+// RemoveIgnoredFields removes the ignored fields from inputs, so that a server-side apply of the inputs leaves them to
+// the field managers that set them. Lists are applied as a whole, so paths that end with a list element can't be left
+// out; these elements are set to their values in live instead, as with PreserveIgnoredFields. The inputs are
+// modified in place.
+func RemoveIgnoredFields(inputs, live *unstructured.Unstructured, paths []FieldPath) {
+	if inputs == nil {
+		return
+	}
+	for _, path := range paths {
+		if _, isField := path[len(path)-1].(string); isField {
+			removeField(inputs.Object, path)
+		} else if live != nil {
+			copyField(inputs.Object, live.Object, path)
+		}
+	}
+}
+
+// removeField removes the value at path from obj, which is the parent of the first element of the path. The last
+// element of the path is a field name.
+func removeField(obj interface{}, path FieldPath) {
+	switch key := path[0].(type) {
+	case string:
+		objMap, isMap := obj.(map[string]interface{})
+		if !isMap {
+			return
+		}
+		if len(path) == 1 {
+			delete(objMap, key)
+			return
+		}
+		if value, inObj := objMap[key]; inObj {
+			removeField(value, path[1:])
+		}
+	case int, anyIndex:
+		objList, isList := obj.([]interface{})
+		if !isList {
+			return
+		}
+		for i := range objList {
+			if index, isIndex := key.(int); isIndex && i != index {
+				continue
+			}
+			removeField(objList[i], path[1:])
+		}
+	}
+}
+
+// copyField copies the value at path from src to dst, where both are the parents of the first element of the path.
+func copyField(dst, src interface{}, path FieldPath) {
+	switch key := path[0].(type) {
+	case string:
+		dstMap, isDstMap := dst.(map[string]interface{})
+		srcMap, isSrcMap := src.(map[string]interface{})
+		if !isDstMap || !isSrcMap {
+			return
+		}
+		dstValue, inDst := dstMap[key]
+		srcValue, inSrc := srcMap[key]
+		if !inDst || !inSrc {
+			return
+		}
+		if len(path) == 1 {
+			dstMap[key] = runtime.DeepCopyJSONValue(srcValue)
+			return
+		}
+		copyField(dstValue, srcValue, path[1:])
+	case int, anyIndex:
+		dstList, isDstList := dst.([]interface{})
+		srcList, isSrcList := src.([]interface{})
+		if !isDstList || !isSrcList {
+			return
+		}
+		for i := range dstList {
+			if index, isIndex := key.(int); (isIndex && i != index) || i >= len(srcList) {
+				continue
+			}
+			if len(path) == 1 {
+				dstList[i] = runtime.DeepCopyJSONValue(srcList[i])
+				continue
+			}
+			copyField(dstList[i], srcList[i], path[1:])
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseFieldPath(t *testing.T) {
+	tests := []struct {
+		path      string
+		want      FieldPath
+		expectErr bool
+	}{
+		{path: "spec.replicas", want: FieldPath{"spec", "replicas"}},
+		{path: `metadata.annotations["sidecar.istio.io/status"]`,
+			want: FieldPath{"metadata", "annotations", "sidecar.istio.io/status"}},
+		{path: "spec.template.spec.containers[0].image",
+			want: FieldPath{"spec", "template", "spec", "containers", 0, "image"}},
+		{path: "webhooks[*].clientConfig.caBundle",
+			want: FieldPath{"webhooks", anyIndex{}, "clientConfig", "caBundle"}},
+		{path: "", expectErr: true},
+		{path: "[0].spec", expectErr: true},
+		{path: `metadata.annotations["unterminated`, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParseFieldPath(tt.path)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseIgnoreFieldsRules(t *testing.T) {
+	rules, err := ParseIgnoreFieldsRules(`{"apps/v1/Deployment": ["spec.replicas"], ` +
+		`"MutatingWebhookConfiguration": ["webhooks[*].clientConfig.caBundle"]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, IgnoreFieldsRules{
+		"apps/v1/Deployment":           {{"spec", "replicas"}},
+		"MutatingWebhookConfiguration": {{"webhooks", anyIndex{}, "clientConfig", "caBundle"}},
+	}, rules)
+
+	_, err = ParseIgnoreFieldsRules(`{"Deployment": "spec.replicas"}`)
+	assert.Error(t, err)
+	_, err = ParseIgnoreFieldsRules(`{"Deployment": ["[0]"]}`)
+	assert.Error(t, err)
+}
+
+func TestIgnoredFields(t *testing.T) {
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetAnnotations(map[string]string{
+		AnnotationIgnoreFields: `spec.replicas, metadata.annotations["a,b"]`,
+	})
+	rules := IgnoreFieldsRules{
+		"apps/v1/Deployment": {{"spec", "template", "metadata", "annotations"}},
+		"Deployment":         {{"spec", "paused"}},
+		"StatefulSet":        {{"spec", "replicas"}},
+	}
+
+	paths, err := IgnoredFields(deployment, rules)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldPath{
+		{"spec", "replicas"},
+		{"metadata", "annotations", "a,b"},
+		{"spec", "template", "metadata", "annotations"},
+		{"spec", "paused"},
+	}, paths)
+
+	deployment.SetAnnotations(map[string]string{AnnotationIgnoreFields: "spec.replicas,[0]"})
+	_, err = IgnoredFields(deployment, rules)
+	assert.Error(t, err)
+}
+
+func TestPreserveIgnoredFields(t *testing.T) {
+	inputs := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "foo"},
+		"spec":     map[string]interface{}{"replicas": float64(1), "paused": false},
+		"webhooks": []interface{}{
+			map[string]interface{}{"name": "a", "clientConfig": map[string]interface{}{"caBundle": ""}},
+			map[string]interface{}{"name": "b", "clientConfig": map[string]interface{}{"caBundle": ""}},
+		},
+	}}
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "foo",
+			"annotations": map[string]interface{}{"sidecar.istio.io/status": "injected"},
+		},
+		"spec": map[string]interface{}{"replicas": int64(5)},
+		"webhooks": []interface{}{
+			map[string]interface{}{"name": "a", "clientConfig": map[string]interface{}{"caBundle": "Y2E="}},
+		},
+	}}
+
+	PreserveIgnoredFields(inputs, live, []FieldPath{
+		{"spec", "replicas"},
+		{"spec", "paused"},
+		{"metadata", "annotations", "sidecar.istio.io/status"},
+		{"webhooks", anyIndex{}, "clientConfig", "caBundle"},
+	})
+	assert.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "foo"},
+		"spec":     map[string]interface{}{"replicas": int64(5), "paused": false},
+		"webhooks": []interface{}{
+			map[string]interface{}{"name": "a", "clientConfig": map[string]interface{}{"caBundle": "Y2E="}},
+			map[string]interface{}{"name": "b", "clientConfig": map[string]interface{}{"caBundle": ""}},
+		},
+	}, inputs.Object)
+}
+
+func TestRemoveIgnoredFields(t *testing.T) {
+	inputs := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "foo",
+			"annotations": map[string]interface{}{"sidecar.istio.io/status": "", "owner": "team-a"},
+		},
+		"spec": map[string]interface{}{"replicas": float64(1), "paused": false},
+		"webhooks": []interface{}{
+			map[string]interface{}{"name": "a", "clientConfig": map[string]interface{}{"caBundle": ""}},
+			map[string]interface{}{"name": "b", "clientConfig": map[string]interface{}{"caBundle": ""}},
+		},
+		"args": []interface{}{"--v=1", "--port=80"},
+	}}
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "foo"},
+		"spec":     map[string]interface{}{"replicas": int64(5)},
+		"args":     []interface{}{"--v=1", "--port=8080"},
+	}}
+
+	RemoveIgnoredFields(inputs, live, []FieldPath{
+		{"spec", "replicas"},
+		{"spec", "minReadySeconds"},
+		{"metadata", "annotations", "sidecar.istio.io/status"},
+		{"webhooks", anyIndex{}, "clientConfig", "caBundle"},
+		{"args", 1},
+	})
+	assert.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "foo",
+			"annotations": map[string]interface{}{"owner": "team-a"},
+		},
+		"spec": map[string]interface{}{"paused": false},
+		"webhooks": []interface{}{
+			map[string]interface{}{"name": "a", "clientConfig": map[string]interface{}{}},
+			map[string]interface{}{"name": "b", "clientConfig": map[string]interface{}{}},
+		},
+		"args": []interface{}{"--v=1", "--port=8080"},
+	}, inputs.Object)
+}
@@ -15,24 +15,120 @@
 package metadata
 
 import (
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+// PatchForce returns true if the `pulumi.com/patchForce` annotation is "true", false otherwise. With server-side
+// apply, this takes ownership of fields that are managed by other field managers, rather than failing the apply, and
+// allows a create to adopt an object that already exists.
+func PatchForce(obj *unstructured.Unstructured) bool {
+	return IsAnnotationTrue(obj, AnnotationPatchForce)
+}
+
 // SkipAwaitLogic returns true if the `pulumi.com/skipAwait` annotation is "true", false otherwise.
 func SkipAwaitLogic(obj *unstructured.Unstructured) bool {
 	return IsAnnotationTrue(obj, AnnotationSkipAwait)
 }
 
+// WaitForDefaultServiceAccount returns true if the `pulumi.com/waitForDefaultServiceAccount` annotation is "true",
+// false otherwise. If set, a Namespace is not considered ready until its `default` ServiceAccount exists.
+func WaitForDefaultServiceAccount(obj *unstructured.Unstructured) bool {
+	return IsAnnotationTrue(obj, AnnotationWaitForDefaultServiceAccount)
+}
+
+// RollbackOnFailure returns true if a failed update of the object should be rolled back to its previous
+// configuration. The `pulumi.com/rollbackOnFailure` annotation, if set to "true" or "false", overrides the
+// provider-level default.
+func RollbackOnFailure(obj *unstructured.Unstructured, providerDefault bool) bool {
+	switch GetAnnotationValue(obj, AnnotationRollbackOnFailure) {
+	case AnnotationTrue:
+		return true
+	case AnnotationFalse:
+		return false
+	default:
+		return providerDefault
+	}
+}
+
+// DefaultTimeouts holds the default await timeouts set with the `defaultTimeouts` provider config. These replace the
+// built-in defaults of the awaiters, but not the timeouts specified for a particular resource.
+type DefaultTimeouts struct {
+	// Seconds maps a GVK (e.g., "apps/v1/Deployment") or a kind (e.g., "Deployment") to a timeout in seconds.
+	Seconds map[string]int
+	// Multiplier scales every default timeout, including the built-in defaults of the awaiters.
+	Multiplier float64
+}
+
+// defaultTimeoutsMultiplierKey is the key of the global multiplier in the `defaultTimeouts` provider config.
+const defaultTimeoutsMultiplierKey = "multiplier"
+
+// ParseDefaultTimeouts parses the value of the `defaultTimeouts` provider config, a JSON object that maps GVKs or
+// kinds to timeouts in seconds, e.g., `{"apps/v1/Deployment": 1200, "Job": 3600, "multiplier": 2}`. The optional
+// "multiplier" entry scales every default timeout.
+func ParseDefaultTimeouts(s string) (*DefaultTimeouts, error) {
+	var entries map[string]float64
+	if err := json.Unmarshal([]byte(s), &entries); err != nil {
+		return nil, fmt.Errorf("defaultTimeouts must be a JSON object mapping kinds to seconds: %v", err)
+	}
+
+	defaults := &DefaultTimeouts{Seconds: map[string]int{}, Multiplier: 1}
+	for key, value := range entries {
+		if key == defaultTimeoutsMultiplierKey {
+			if value <= 0 {
+				return nil, fmt.Errorf("defaultTimeouts multiplier must be positive, got %v", value)
+			}
+			defaults.Multiplier = value
+			continue
+		}
+		if value <= 0 || value != float64(int(value)) {
+			return nil, fmt.Errorf("defaultTimeouts entry %q must be a positive number of seconds, got %v", key, value)
+		}
+		defaults.Seconds[key] = int(value)
+	}
+	return defaults, nil
+}
+
+// seconds returns the default timeout of the object in seconds, preferring an entry for its GVK over an entry for its
+// kind, and falling back to the built-in default of the awaiter.
+func (d *DefaultTimeouts) seconds(obj *unstructured.Unstructured, defaultSeconds int) int {
+	if d == nil {
+		return defaultSeconds
+	}
+
+	timeout := defaultSeconds
+	if obj != nil {
+		if val, ok := d.Seconds[obj.GetAPIVersion()+"/"+obj.GetKind()]; ok {
+			timeout = val
+		} else if val, ok := d.Seconds[obj.GetKind()]; ok {
+			timeout = val
+		}
+	}
+	if d.Multiplier > 0 && timeout > 0 {
+		// A small multiplier must not scale a timeout down to zero, which would time out immediately.
+		timeout = int(float64(timeout) * d.Multiplier)
+		if timeout < 1 {
+			timeout = 1
+		}
+	}
+	return timeout
+}
+
 // TimeoutDuration returns the resource timeout duration. There are a number of things it can do here in this order
 // 1. Return the timeout as specified in the customResource options
 // 2. Return the timeout as specified in `pulumi.com/timeoutSeconds` annotation,
-// 3. Return a defaultSeconds value
-// if the annotation is unset/invalid.
-func TimeoutDuration(resourceTimeoutSeconds float64, obj *unstructured.Unstructured, defaultSeconds int) time.Duration {
-	timeout := defaultSeconds
+// 3. Return the timeout for the GVK or kind of the resource from the `defaultTimeouts` provider config,
+// 4. Return a defaultSeconds value
+// if the annotation is unset/invalid. The multiplier of the `defaultTimeouts` provider config applies to 3 and 4.
+func TimeoutDuration(resourceTimeoutSeconds float64, defaults *DefaultTimeouts, obj *unstructured.Unstructured,
+	defaultSeconds int) time.Duration {
+	timeout := defaults.seconds(obj, defaultSeconds)
 
 	if resourceTimeoutSeconds != 0 {
 		timeout = int(resourceTimeoutSeconds)
@@ -45,3 +141,218 @@ func TimeoutDuration(resourceTimeoutSeconds float64, obj *unstructured.Unstructu
 
 	return time.Duration(timeout) * time.Second
 }
+
+// DefaultFailureLogLines is the number of container log lines included in await errors if the
+// `pulumi.com/failureLogLines` annotation is unset or invalid.
+const DefaultFailureLogLines = 10
+
+// FailureLogLines returns the number of log lines of each failed container to include in await errors, as specified
+// by the `pulumi.com/failureLogLines` annotation. A value of 0 disables log collection.
+func FailureLogLines(obj *unstructured.Unstructured) int64 {
+	if s := GetAnnotationValue(obj, AnnotationFailureLogLines); s != "" {
+		if val, err := strconv.ParseInt(s, 10, 64); err == nil && val >= 0 {
+			return val
+		}
+	}
+	return DefaultFailureLogLines
+}
+
+// DefaultRemoveFinalizersAfterSeconds is the grace period used if the `pulumi.com/removeFinalizers` annotation is set,
+// but `pulumi.com/removeFinalizersAfterSeconds` is unset or invalid.
+const DefaultRemoveFinalizersAfterSeconds = 60
+
+// RemoveFinalizers returns the finalizers that should be stripped from the object if its deletion is still blocked
+// after the returned grace period. The finalizers are given as a comma-separated list in the
+// `pulumi.com/removeFinalizers` annotation, where "*" matches every finalizer; the grace period is given by the
+// `pulumi.com/removeFinalizersAfterSeconds` annotation. Returns nil if the annotation is unset.
+func RemoveFinalizers(obj *unstructured.Unstructured) ([]string, time.Duration) {
+	var finalizers []string
+	for _, f := range strings.Split(GetAnnotationValue(obj, AnnotationRemoveFinalizers), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			finalizers = append(finalizers, f)
+		}
+	}
+	if len(finalizers) == 0 {
+		return nil, 0
+	}
+
+	seconds := DefaultRemoveFinalizersAfterSeconds
+	if s := GetAnnotationValue(obj, AnnotationRemoveFinalizersAfterSeconds); s != "" {
+		if val, err := strconv.Atoi(s); err == nil && val >= 0 {
+			seconds = val
+		}
+	}
+
+	return finalizers, time.Duration(seconds) * time.Second
+}
+
+// Values of the `pulumi.com/deletionPolicy` annotation.
+const (
+	DeletionPolicyDelete = "delete"
+	DeletionPolicyRetain = "retain"
+)
+
+// RetainOnDelete returns true if the `pulumi.com/deletionPolicy` annotation is "retain". The object is then removed
+// from the stack on deletion, but left in the cluster.
+func RetainOnDelete(obj *unstructured.Unstructured) bool {
+	return GetAnnotationValue(obj, AnnotationDeletionPolicy) == DeletionPolicyRetain
+}
+
+// DeleteOptions holds the options of the delete request for an object. Unset options use the version-specific
+// defaults of the provider.
+type DeleteOptions struct {
+	PropagationPolicy  *metav1.DeletionPropagation
+	GracePeriodSeconds *int64
+}
+
+// ParsePropagationPolicy parses a deletion propagation policy, i.e., "Foreground", "Background" or "Orphan".
+func ParsePropagationPolicy(s string) (metav1.DeletionPropagation, error) {
+	for _, policy := range []metav1.DeletionPropagation{
+		metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan,
+	} {
+		if strings.EqualFold(s, string(policy)) {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("invalid propagation policy %q: expected Foreground, Background or Orphan", s)
+}
+
+// ParseGracePeriodSeconds parses a deletion grace period, a non-negative number of seconds.
+func ParseGracePeriodSeconds(s string) (int64, error) {
+	val, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || val < 0 {
+		return 0, fmt.Errorf("invalid grace period %q: expected a non-negative number of seconds", s)
+	}
+	return val, nil
+}
+
+// GetDeleteOptions returns the delete options of the object. The `pulumi.com/deletePropagationPolicy` and
+// `pulumi.com/deleteGracePeriodSeconds` annotations override the provider-level defaults. Returns an error if one of
+// these annotations, or the `pulumi.com/deletionPolicy` annotation, is invalid.
+func GetDeleteOptions(obj *unstructured.Unstructured, defaults DeleteOptions) (DeleteOptions, error) {
+	opts := defaults
+
+	switch policy := GetAnnotationValue(obj, AnnotationDeletionPolicy); policy {
+	case "", DeletionPolicyDelete, DeletionPolicyRetain:
+	default:
+		return opts, fmt.Errorf("invalid %s annotation %q: expected %q or %q",
+			AnnotationDeletionPolicy, policy, DeletionPolicyDelete, DeletionPolicyRetain)
+	}
+
+	if s := GetAnnotationValue(obj, AnnotationDeletePropagationPolicy); s != "" {
+		policy, err := ParsePropagationPolicy(s)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s annotation: %v", AnnotationDeletePropagationPolicy, err)
+		}
+		opts.PropagationPolicy = &policy
+	}
+	if s := GetAnnotationValue(obj, AnnotationDeleteGracePeriodSeconds); s != "" {
+		seconds, err := ParseGracePeriodSeconds(s)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s annotation: %v", AnnotationDeleteGracePeriodSeconds, err)
+		}
+		opts.GracePeriodSeconds = &seconds
+	}
+
+	return opts, nil
+}
+
+// WaitFor describes the readiness predicate specified by the `pulumi.com/waitFor` annotation. Exactly one of
+// ConditionType or JSONPath is set.
+type WaitFor struct {
+	// ConditionType is the type of the `.status.conditions` entry to wait for, e.g., "Ready".
+	ConditionType string
+	// ConditionStatus is the expected status of the condition. Defaults to "True".
+	ConditionStatus string
+
+	// JSONPath is a JSONPath expression, e.g., "{.status.phase}", evaluated against the live object.
+	JSONPath string
+	// Value is the expected result of the JSONPath expression. If empty, any non-empty result is accepted.
+	Value string
+}
+
+func (w WaitFor) String() string {
+	if w.JSONPath != "" {
+		if w.Value == "" {
+			return w.JSONPath
+		}
+		return fmt.Sprintf("%s=%s", w.JSONPath, w.Value)
+	}
+	return fmt.Sprintf("condition %s=%s", w.ConditionType, w.ConditionStatus)
+}
+
+// HasWaitFor returns true if the `pulumi.com/waitFor` annotation is set on the object.
+func HasWaitFor(obj *unstructured.Unstructured) bool {
+	if obj == nil {
+		return false
+	}
+	return strings.TrimSpace(GetAnnotationValue(obj, AnnotationWaitFor)) != ""
+}
+
+// GetWaitFor parses the `pulumi.com/waitFor` annotation, returning nil if the annotation is unset. The annotation
+// takes one of the following forms:
+//
+//	condition=Ready                      `.status.conditions` entry of type "Ready" has status "True"
+//	condition=Ready=False                `.status.conditions` entry of type "Ready" has status "False"
+//	jsonpath={.status.phase}=Running     JSONPath expression evaluates to "Running"
+//	jsonpath={.status.loadBalancer}      JSONPath expression evaluates to a non-empty value
+func GetWaitFor(obj *unstructured.Unstructured) (*WaitFor, error) {
+	value := strings.TrimSpace(GetAnnotationValue(obj, AnnotationWaitFor))
+	if value == "" {
+		return nil, nil
+	}
+
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid %s annotation %q: %s", AnnotationWaitFor, value, reason)
+	}
+
+	switch {
+	case strings.HasPrefix(value, "condition="):
+		parts := strings.SplitN(strings.TrimPrefix(value, "condition="), "=", 2)
+		if parts[0] == "" {
+			return nil, invalid("condition type is empty")
+		}
+		w := &WaitFor{ConditionType: parts[0], ConditionStatus: "True"}
+		if len(parts) == 2 {
+			if parts[1] == "" {
+				return nil, invalid("condition status is empty")
+			}
+			w.ConditionStatus = parts[1]
+		}
+		return w, nil
+	case strings.HasPrefix(value, "jsonpath="):
+		expr := strings.TrimPrefix(value, "jsonpath=")
+		if !strings.HasPrefix(expr, "{") {
+			return nil, invalid("JSONPath expression must be enclosed in braces, e.g., {.status.phase}")
+		}
+
+		// Find the brace that closes the expression; the expression itself may contain '=' (e.g., in filters).
+		depth := 0
+		end := -1
+		for i, r := range expr {
+			if r == '{' {
+				depth++
+			} else if r == '}' {
+				depth--
+				if depth == 0 {
+					end = i
+					break
+				}
+			}
+		}
+		if end == -1 {
+			return nil, invalid("unterminated JSONPath expression")
+		}
+
+		w := &WaitFor{JSONPath: expr[:end+1]}
+		if rest := expr[end+1:]; rest != "" {
+			if !strings.HasPrefix(rest, "=") || len(rest) == 1 {
+				return nil, invalid("expected '=<value>' after JSONPath expression")
+			}
+			w.Value = rest[1:]
+		}
+		return w, nil
+	default:
+		return nil, invalid("expected a value of the form 'condition=<type>' or 'jsonpath={<expression>}=<value>'")
+	}
+}
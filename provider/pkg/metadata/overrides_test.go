@@ -19,6 +19,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -52,6 +55,233 @@ func TestSkipAwaitLogic(t *testing.T) {
 	}
 }
 
+func TestRollbackOnFailure(t *testing.T) {
+	resource := &unstructured.Unstructured{}
+
+	annotatedResourceTrue := &unstructured.Unstructured{}
+	annotatedResourceTrue.SetAnnotations(map[string]string{AnnotationRollbackOnFailure: AnnotationTrue})
+
+	annotatedResourceFalse := &unstructured.Unstructured{}
+	annotatedResourceFalse.SetAnnotations(map[string]string{AnnotationRollbackOnFailure: AnnotationFalse})
+
+	type args struct {
+		obj             *unstructured.Unstructured
+		providerDefault bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{name: "Rollback annotation unset, provider default false", args: args{resource, false}, want: false},
+		{name: "Rollback annotation unset, provider default true", args: args{resource, true}, want: true},
+		{name: "Rollback annotation set true", args: args{annotatedResourceTrue, false}, want: true},
+		{name: "Rollback annotation set false", args: args{annotatedResourceFalse, true}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RollbackOnFailure(tt.args.obj, tt.args.providerDefault); got != tt.want {
+				t.Errorf("RollbackOnFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForDefaultServiceAccount(t *testing.T) {
+	namespace := &unstructured.Unstructured{}
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+
+	annotatedNamespaceTrue := namespace.DeepCopy()
+	annotatedNamespaceTrue.SetAnnotations(map[string]string{AnnotationWaitForDefaultServiceAccount: AnnotationTrue})
+
+	annotatedNamespaceFalse := namespace.DeepCopy()
+	annotatedNamespaceFalse.SetAnnotations(map[string]string{AnnotationWaitForDefaultServiceAccount: AnnotationFalse})
+
+	type args struct {
+		obj *unstructured.Unstructured
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{name: "Wait annotation unset", args: args{namespace}, want: false},
+		{name: "Wait annotation set true", args: args{annotatedNamespaceTrue}, want: true},
+		{name: "Wait annotation set false", args: args{annotatedNamespaceFalse}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WaitForDefaultServiceAccount(tt.args.obj); got != tt.want {
+				t.Errorf("WaitForDefaultServiceAccount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchForce(t *testing.T) {
+	resource := &unstructured.Unstructured{}
+
+	annotatedResourceTrue := &unstructured.Unstructured{}
+	annotatedResourceTrue.SetAnnotations(map[string]string{AnnotationPatchForce: AnnotationTrue})
+
+	annotatedResourceFalse := &unstructured.Unstructured{}
+	annotatedResourceFalse.SetAnnotations(map[string]string{AnnotationPatchForce: AnnotationFalse})
+
+	// Only the exact value "true" forces the apply.
+	annotatedResourceInvalid := &unstructured.Unstructured{}
+	annotatedResourceInvalid.SetAnnotations(map[string]string{AnnotationPatchForce: "yes"})
+
+	type args struct {
+		obj *unstructured.Unstructured
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{name: "Force annotation unset", args: args{resource}, want: false},
+		{name: "Force annotation set true", args: args{annotatedResourceTrue}, want: true},
+		{name: "Force annotation set false", args: args{annotatedResourceFalse}, want: false},
+		{name: "Force annotation set invalid", args: args{annotatedResourceInvalid}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PatchForce(tt.args.obj); got != tt.want {
+				t.Errorf("PatchForce() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailureLogLines(t *testing.T) {
+	withLines := func(value string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{AnnotationFailureLogLines: value})
+		return obj
+	}
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want int64
+	}{
+		{name: "Annotation unset", obj: &unstructured.Unstructured{}, want: 10},
+		{name: "Annotation set", obj: withLines("50"), want: 50},
+		{name: "Log collection disabled", obj: withLines("0"), want: 0},
+		{name: "Annotation invalid", obj: withLines("-5"), want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FailureLogLines(tt.obj))
+		})
+	}
+}
+
+func TestRemoveFinalizers(t *testing.T) {
+	withAnnotations := func(annotations map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(annotations)
+		return obj
+	}
+
+	tests := []struct {
+		name           string
+		obj            *unstructured.Unstructured
+		wantFinalizers []string
+		wantGrace      time.Duration
+	}{
+		{name: "Annotation unset", obj: &unstructured.Unstructured{}},
+		{name: "Annotation empty", obj: withAnnotations(map[string]string{AnnotationRemoveFinalizers: " , "})},
+		{name: "Default grace period",
+			obj:            withAnnotations(map[string]string{AnnotationRemoveFinalizers: "example.com/cleanup"}),
+			wantFinalizers: []string{"example.com/cleanup"},
+			wantGrace:      60 * time.Second},
+		{name: "Multiple finalizers with grace period",
+			obj: withAnnotations(map[string]string{
+				AnnotationRemoveFinalizers:             "example.com/cleanup, kubernetes.io/pvc-protection",
+				AnnotationRemoveFinalizersAfterSeconds: "15",
+			}),
+			wantFinalizers: []string{"example.com/cleanup", "kubernetes.io/pvc-protection"},
+			wantGrace:      15 * time.Second},
+		{name: "Invalid grace period",
+			obj: withAnnotations(map[string]string{
+				AnnotationRemoveFinalizers:             "*",
+				AnnotationRemoveFinalizersAfterSeconds: "-1",
+			}),
+			wantFinalizers: []string{"*"},
+			wantGrace:      60 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finalizers, grace := RemoveFinalizers(tt.obj)
+			assert.Equal(t, tt.wantFinalizers, finalizers)
+			assert.Equal(t, tt.wantGrace, grace)
+		})
+	}
+}
+
+func TestRetainOnDelete(t *testing.T) {
+	retained := &unstructured.Unstructured{}
+	retained.SetAnnotations(map[string]string{AnnotationDeletionPolicy: DeletionPolicyRetain})
+
+	deleted := &unstructured.Unstructured{}
+	deleted.SetAnnotations(map[string]string{AnnotationDeletionPolicy: DeletionPolicyDelete})
+
+	assert.False(t, RetainOnDelete(&unstructured.Unstructured{}))
+	assert.True(t, RetainOnDelete(retained))
+	assert.False(t, RetainOnDelete(deleted))
+}
+
+func TestGetDeleteOptions(t *testing.T) {
+	withAnnotations := func(annotations map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(annotations)
+		return obj
+	}
+	foreground := metav1.DeletePropagationForeground
+	orphan := metav1.DeletePropagationOrphan
+	zero := int64(0)
+	thirty := int64(30)
+	defaults := DeleteOptions{PropagationPolicy: &foreground, GracePeriodSeconds: &thirty}
+
+	tests := []struct {
+		name      string
+		obj       *unstructured.Unstructured
+		defaults  DeleteOptions
+		want      DeleteOptions
+		expectErr bool
+	}{
+		{name: "Annotations unset", obj: &unstructured.Unstructured{}},
+		{name: "Provider defaults", obj: &unstructured.Unstructured{}, defaults: defaults, want: defaults},
+		{name: "Annotations override provider defaults",
+			obj: withAnnotations(map[string]string{
+				AnnotationDeletePropagationPolicy:  "orphan",
+				AnnotationDeleteGracePeriodSeconds: "0",
+			}),
+			defaults: defaults,
+			want:     DeleteOptions{PropagationPolicy: &orphan, GracePeriodSeconds: &zero}},
+		{name: "Retain policy", obj: withAnnotations(map[string]string{AnnotationDeletionPolicy: "retain"})},
+		{name: "Invalid deletion policy",
+			obj: withAnnotations(map[string]string{AnnotationDeletionPolicy: "keep"}), expectErr: true},
+		{name: "Invalid propagation policy",
+			obj: withAnnotations(map[string]string{AnnotationDeletePropagationPolicy: "Cascade"}), expectErr: true},
+		{name: "Negative grace period",
+			obj: withAnnotations(map[string]string{AnnotationDeleteGracePeriodSeconds: "-1"}), expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDeleteOptions(tt.obj, tt.defaults)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestTimeoutSeconds(t *testing.T) {
 	resource := &unstructured.Unstructured{}
 
@@ -61,8 +291,27 @@ func TestTimeoutSeconds(t *testing.T) {
 	annotatedResourceInvalid := &unstructured.Unstructured{}
 	annotatedResourceInvalid.SetAnnotations(map[string]string{AnnotationTimeoutSeconds: "foo"})
 
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+
+	annotatedDeployment := deployment.DeepCopy()
+	annotatedDeployment.SetAnnotations(map[string]string{AnnotationTimeoutSeconds: "15"})
+
+	job := &unstructured.Unstructured{}
+	job.SetAPIVersion("batch/v1")
+	job.SetKind("Job")
+
+	defaults := &DefaultTimeouts{
+		Seconds:    map[string]int{"apps/v1/Deployment": 1200, "Deployment": 900, "Job": 3600},
+		Multiplier: 1,
+	}
+	scaledDefaults := &DefaultTimeouts{Seconds: map[string]int{"Job": 3600}, Multiplier: 2}
+	tinyDefaults := &DefaultTimeouts{Seconds: map[string]int{}, Multiplier: 0.001}
+
 	type args struct {
 		customTimeout  float64
+		defaults       *DefaultTimeouts
 		obj            *unstructured.Unstructured
 		defaultSeconds int
 	}
@@ -75,12 +324,99 @@ func TestTimeoutSeconds(t *testing.T) {
 		{"Timeout annotation set", args{customTimeout: 0, obj: annotatedResource15, defaultSeconds: 300}, 15 * time.Second},
 		{"Timeout annotation invalid", args{customTimeout: 0, obj: annotatedResourceInvalid, defaultSeconds: 300}, 5 * time.Minute},
 		{"Timeout from customResource", args{customTimeout: 600, obj: annotatedResource15, defaultSeconds: 300}, 10 * time.Minute},
+		{"Default timeout for GVK", args{defaults: defaults, obj: deployment, defaultSeconds: 600}, 20 * time.Minute},
+		{"Default timeout for kind", args{defaults: defaults, obj: job, defaultSeconds: 300}, time.Hour},
+		{"Default timeout unset for kind", args{defaults: defaults, obj: resource, defaultSeconds: 300}, 5 * time.Minute},
+		{"Timeout annotation over default timeout",
+			args{defaults: defaults, obj: annotatedDeployment, defaultSeconds: 600}, 15 * time.Second},
+		{"Timeout from customResource over default timeout",
+			args{customTimeout: 60, defaults: defaults, obj: deployment, defaultSeconds: 600}, time.Minute},
+		{"Multiplier scales default timeout", args{defaults: scaledDefaults, obj: job, defaultSeconds: 300}, 2 * time.Hour},
+		{"Multiplier scales built-in default",
+			args{defaults: scaledDefaults, obj: deployment, defaultSeconds: 600}, 20 * time.Minute},
+		{"Multiplier ignored for timeout annotation",
+			args{defaults: scaledDefaults, obj: annotatedDeployment, defaultSeconds: 600}, 15 * time.Second},
+		{"Small multiplier scales to at least one second",
+			args{defaults: tinyDefaults, obj: deployment, defaultSeconds: 600}, time.Second},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := TimeoutDuration(tt.args.customTimeout, tt.args.obj, tt.args.defaultSeconds); got != tt.want {
+			if got := TimeoutDuration(tt.args.customTimeout, tt.args.defaults, tt.args.obj, tt.args.defaultSeconds); got != tt.want {
 				t.Errorf("TimeoutDuration() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestParseDefaultTimeouts(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		want      *DefaultTimeouts
+		expectErr bool
+	}{
+		{name: "Empty", value: "{}", want: &DefaultTimeouts{Seconds: map[string]int{}, Multiplier: 1}},
+		{name: "Kinds and multiplier", value: `{"apps/v1/Deployment": 1200, "Job": 3600, "multiplier": 1.5}`,
+			want: &DefaultTimeouts{Seconds: map[string]int{"apps/v1/Deployment": 1200, "Job": 3600}, Multiplier: 1.5}},
+		{name: "Invalid JSON", value: "Deployment=1200", expectErr: true},
+		{name: "Non-numeric timeout", value: `{"Deployment": "20m"}`, expectErr: true},
+		{name: "Negative timeout", value: `{"Deployment": -1}`, expectErr: true},
+		{name: "Fractional timeout", value: `{"Deployment": 1.5}`, expectErr: true},
+		{name: "Zero multiplier", value: `{"multiplier": 0}`, expectErr: true},
+		{name: "Small multiplier", value: `{"multiplier": 0.001}`,
+			want: &DefaultTimeouts{Seconds: map[string]int{}, Multiplier: 0.001}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDefaultTimeouts(tt.value)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetWaitFor(t *testing.T) {
+	withWaitFor := func(value string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{AnnotationWaitFor: value})
+		return obj
+	}
+
+	tests := []struct {
+		name      string
+		obj       *unstructured.Unstructured
+		want      *WaitFor
+		expectErr bool
+	}{
+		{name: "Annotation unset", obj: &unstructured.Unstructured{}, want: nil},
+		{name: "Condition", obj: withWaitFor("condition=Ready"),
+			want: &WaitFor{ConditionType: "Ready", ConditionStatus: "True"}},
+		{name: "Condition with status", obj: withWaitFor("condition=Synced=False"),
+			want: &WaitFor{ConditionType: "Synced", ConditionStatus: "False"}},
+		{name: "JSONPath with value", obj: withWaitFor("jsonpath={.status.phase}=Running"),
+			want: &WaitFor{JSONPath: "{.status.phase}", Value: "Running"}},
+		{name: "JSONPath with filter", obj: withWaitFor(`jsonpath={.status.conditions[?(@.type=="Ready")].status}=True`),
+			want: &WaitFor{JSONPath: `{.status.conditions[?(@.type=="Ready")].status}`, Value: "True"}},
+		{name: "JSONPath without value", obj: withWaitFor("jsonpath={.status.loadBalancer.ingress}"),
+			want: &WaitFor{JSONPath: "{.status.loadBalancer.ingress}"}},
+		{name: "Empty condition", obj: withWaitFor("condition="), expectErr: true},
+		{name: "Unterminated JSONPath", obj: withWaitFor("jsonpath={.status.phase"), expectErr: true},
+		{name: "JSONPath without braces", obj: withWaitFor("jsonpath=.status.phase=Running"), expectErr: true},
+		{name: "Unknown form", obj: withWaitFor("Ready"), expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetWaitFor(tt.obj)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -257,3 +257,58 @@ func GetResourceSchemasForClient(
 
 	return openapi.NewOpenAPIData(document)
 }
+
+// ListMergeKeys returns the names of the fields that identify the elements of a list of a resource, i.e., its
+// `x-kubernetes-patch-merge-key` or its `x-kubernetes-list-map-keys`. The list is identified by the names of the
+// fields that lead to it, e.g., `spec`, `template`, `spec`, `containers`, `env`; lists along the way are traversed
+// implicitly. Returns nil if the schema of the resource is unknown, or if the list has no merge keys.
+func ListMergeKeys(resources openapi.Resources, gvk schema.GroupVersionKind, fields []string) []string {
+	if resources == nil {
+		return nil
+	}
+	s := resources.LookupResource(gvk)
+	for _, field := range fields {
+		kind, isKind := elementSchema(s).(*proto.Kind)
+		if !isKind {
+			return nil
+		}
+		s = kind.Fields[field]
+	}
+
+	// The merge keys are extensions of the property that holds the list, which may be a reference to the list schema.
+	for s != nil {
+		extensions := s.GetExtensions()
+		if key, ok := extensions["x-kubernetes-patch-merge-key"].(string); ok && key != "" {
+			return []string{key}
+		}
+		if listKeys, ok := extensions["x-kubernetes-list-map-keys"].([]interface{}); ok && len(listKeys) != 0 {
+			keys := make([]string, 0, len(listKeys))
+			for _, key := range listKeys {
+				if key, ok := key.(string); ok {
+					keys = append(keys, key)
+				}
+			}
+			return keys
+		}
+		ref, isRef := s.(proto.Reference)
+		if !isRef {
+			return nil
+		}
+		s = ref.SubSchema()
+	}
+	return nil
+}
+
+// elementSchema resolves the references and lists of a schema to the schema of the elements of the innermost list.
+func elementSchema(s proto.Schema) proto.Schema {
+	for {
+		switch t := s.(type) {
+		case proto.Reference:
+			s = t.SubSchema()
+		case *proto.Array:
+			s = t.SubType
+		default:
+			return s
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/util/proto"
+)
+
+type fakeResources map[schema.GroupVersionKind]proto.Schema
+
+func (r fakeResources) LookupResource(gvk schema.GroupVersionKind) proto.Schema {
+	return r[gvk]
+}
+
+func TestListMergeKeys(t *testing.T) {
+	str := &proto.Primitive{Type: "string"}
+	envVar := &proto.Kind{Fields: map[string]proto.Schema{"name": str, "value": str}}
+	port := &proto.Kind{Fields: map[string]proto.Schema{"containerPort": &proto.Primitive{Type: "integer"}}}
+	container := &proto.Kind{Fields: map[string]proto.Schema{
+		"name": str,
+		"args": &proto.Array{SubType: str},
+		"env": &proto.Array{
+			BaseSchema: proto.BaseSchema{Extensions: map[string]interface{}{"x-kubernetes-patch-merge-key": "name"}},
+			SubType:    envVar,
+		},
+		"ports": &proto.Array{
+			BaseSchema: proto.BaseSchema{Extensions: map[string]interface{}{
+				"x-kubernetes-list-map-keys": []interface{}{"containerPort", "protocol"},
+			}},
+			SubType: port,
+		},
+	}}
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	resources := fakeResources{deployment: &proto.Kind{Fields: map[string]proto.Schema{
+		"spec": &proto.Kind{Fields: map[string]proto.Schema{
+			"containers": &proto.Array{
+				BaseSchema: proto.BaseSchema{Extensions: map[string]interface{}{"x-kubernetes-patch-merge-key": "name"}},
+				SubType:    container,
+			},
+		}},
+	}}}
+
+	assert.Equal(t, []string{"name"}, ListMergeKeys(resources, deployment, []string{"spec", "containers"}))
+	assert.Equal(t, []string{"name"}, ListMergeKeys(resources, deployment, []string{"spec", "containers", "env"}))
+	assert.Equal(t, []string{"containerPort", "protocol"},
+		ListMergeKeys(resources, deployment, []string{"spec", "containers", "ports"}))
+	assert.Nil(t, ListMergeKeys(resources, deployment, []string{"spec", "containers", "args"}))
+	assert.Nil(t, ListMergeKeys(resources, deployment, []string{"spec", "volumes"}))
+	assert.Nil(t, ListMergeKeys(resources, schema.GroupVersionKind{Kind: "Pod"}, []string{"spec", "containers"}))
+	assert.Nil(t, ListMergeKeys(nil, deployment, []string{"spec", "containers"}))
+}
@@ -15,7 +15,15 @@
 package provider
 
 import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func forceNewProperties(gvk schema.GroupVersionKind) []string {
@@ -37,14 +45,14 @@ type properties []string
 
 var forceNew = _groups{
 	"apps": _versions{
-		// NOTE: .spec.selector triggers a replacement in Deployment/Daemonset only AFTER v1beta1.
+		// NOTE: .spec.selector triggers a replacement in Deployment/DaemonSet only AFTER v1beta1.
 		"v1beta1": _kinds{"StatefulSet": statefulSet},
 		"v1beta2": _kinds{
-			"Daemonset":   daemonset,
+			"DaemonSet":   daemonset,
 			"Deployment":  deployment,
 			"StatefulSet": statefulSet},
 		"v1": _kinds{
-			"Daemonset":   daemonset,
+			"DaemonSet":   daemonset,
 			"Deployment":  deployment,
 			"StatefulSet": statefulSet},
 	},
@@ -250,3 +258,163 @@ func labelSelectorForceNewProperties(prefix string) properties {
 		prefix + ".matchLabels",
 	}
 }
+
+// immutableFieldsFromError returns the force-new properties for the fields that the API server refused to update in
+// the given error: the fields that are reported as immutable (e.g., `spec.selector: Invalid value: ...: field is
+// immutable`), and the fields that changed between the old and new inputs under a field whose updates are forbidden
+// (e.g., `spec: Forbidden: updates to statefulset spec for fields other than 'replicas', ... are forbidden`).
+func immutableFieldsFromError(err error, oldInputs, newInputs map[string]interface{}) properties {
+	statusErr, isStatusErr := err.(*errors.StatusError)
+	if !isStatusErr || statusErr.ErrStatus.Details == nil {
+		return nil
+	}
+
+	var props properties
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		switch {
+		case cause.Type == metav1.CauseType(field.ErrorTypeInvalid) &&
+			strings.Contains(cause.Message, "field is immutable"):
+			if prop := fieldPathToProperty(cause.Field); prop != "" {
+				props = append(props, prop)
+			}
+		case cause.Type == metav1.CauseType(field.ErrorTypeForbidden) &&
+			strings.Contains(cause.Message, "updates to") && strings.Contains(cause.Message, "are forbidden"):
+			props = append(props, forbiddenUpdateProperties(cause, oldInputs, newInputs)...)
+		}
+	}
+	return props
+}
+
+// forbiddenUpdateProperties returns the force-new properties for a field whose updates are forbidden: the children
+// of the field that changed between the old and new inputs, other than the children that the message names as
+// updatable (e.g., `'replicas'`). The field itself is returned if its children can't be compared.
+func forbiddenUpdateProperties(cause metav1.StatusCause, oldInputs, newInputs map[string]interface{}) properties {
+	prop := fieldPathToProperty(cause.Field)
+	if prop == "" {
+		return nil
+	}
+	path := strings.Split(strings.TrimPrefix(prop, "."), ".")
+	oldField, _, oldErr := unstructured.NestedFieldNoCopy(oldInputs, path...)
+	newField, _, newErr := unstructured.NestedFieldNoCopy(newInputs, path...)
+	oldMap, isOldMap := oldField.(map[string]interface{})
+	newMap, isNewMap := newField.(map[string]interface{})
+	if strings.Contains(prop, "[*]") || oldErr != nil || newErr != nil || !isOldMap || !isNewMap {
+		return properties{prop}
+	}
+
+	updatable := map[string]bool{}
+	for i, quoted := range strings.Split(cause.Message, "'") {
+		if i%2 == 1 {
+			updatable[quoted] = true
+		}
+	}
+
+	keys := map[string]bool{}
+	for key := range oldMap {
+		keys[key] = true
+	}
+	for key := range newMap {
+		keys[key] = true
+	}
+	var props properties
+	for key := range keys {
+		if !updatable[key] && !reflect.DeepEqual(oldMap[key], newMap[key]) {
+			props = append(props, prop+"."+key)
+		}
+	}
+	sort.Strings(props)
+	return props
+}
+
+// fieldPathToProperty converts the path of a field in an API server error (e.g., `spec.containers[0].image`) to a
+// force-new property (e.g., `.spec.containers[*].image`). A path into a map (e.g., `metadata.labels[app]`) is cut
+// off at the map.
+func fieldPathToProperty(field string) string {
+	var prop strings.Builder
+	for _, element := range strings.Split(field, ".") {
+		name := element
+		var indices []string
+		if i := strings.Index(element, "["); i != -1 {
+			name = element[:i]
+			indices = strings.Split(strings.TrimSuffix(element[i+1:], "]"), "][")
+		}
+		if name == "" || name == "<nil>" {
+			break
+		}
+		prop.WriteString("." + name)
+		for _, index := range indices {
+			if strings.Trim(index, "0123456789") != "" {
+				return prop.String()
+			}
+			prop.WriteString("[*]")
+		}
+	}
+	return prop.String()
+}
+
+// isCustomResourceGroup returns true if the API group can only be served by CustomResourceDefinitions. Groups of
+// CustomResourceDefinitions must contain a dot, and the `k8s.io` groups are reserved for Kubernetes.
+func isCustomResourceGroup(group string) bool {
+	return strings.Contains(group, ".") && !strings.HasSuffix(group, ".k8s.io") && group != "k8s.io"
+}
+
+// crdForceNewProperties returns the force-new properties for the given version of a CustomResourceDefinition: the
+// properties of its schema that are immutable because of an `x-kubernetes-validations` transition rule such as
+// `self == oldSelf`.
+func crdForceNewProperties(crd map[string]interface{}, version string) properties {
+	spec, _ := crd["spec"].(map[string]interface{})
+	versions, _ := spec["versions"].([]interface{})
+	for _, v := range versions {
+		v, _ := v.(map[string]interface{})
+		if v["name"] != version {
+			continue
+		}
+		validation, _ := v["schema"].(map[string]interface{})
+		openAPIV3Schema, _ := validation["openAPIV3Schema"].(map[string]interface{})
+		return immutableSchemaProperties(openAPIV3Schema, "")
+	}
+	return nil
+}
+
+// immutableSchemaProperties returns the paths of the properties of an OpenAPI v3 schema, below the given path, that
+// have an `x-kubernetes-validations` rule that forbids changes.
+func immutableSchemaProperties(schema map[string]interface{}, path string) properties {
+	var props properties
+	if path != "" && hasImmutableRule(schema) {
+		props = append(props, path)
+	}
+
+	if fields, ok := schema["properties"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			if !strings.ContainsAny(name, ".[]") {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if field, ok := fields[name].(map[string]interface{}); ok {
+				props = append(props, immutableSchemaProperties(field, path+"."+name)...)
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		props = append(props, immutableSchemaProperties(items, path+"[*]")...)
+	}
+	return props
+}
+
+// hasImmutableRule returns true if the schema has an `x-kubernetes-validations` transition rule that requires the
+// value to stay the same, i.e., `self == oldSelf`.
+func hasImmutableRule(schema map[string]interface{}) bool {
+	validations, _ := schema["x-kubernetes-validations"].([]interface{})
+	for _, validation := range validations {
+		validation, _ := validation.(map[string]interface{})
+		rule, _ := validation["rule"].(string)
+		switch strings.Join(strings.Fields(rule), "") {
+		case "self==oldSelf", "oldSelf==self":
+			return true
+		}
+	}
+	return false
+}
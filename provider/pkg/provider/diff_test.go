@@ -4,12 +4,15 @@ package provider
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	pulumirpc "github.com/pulumi/pulumi/sdk/v2/proto/go"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 type object = map[string]interface{}
@@ -189,10 +192,229 @@ func TestPatchToDiff(t *testing.T) {
 				Version: tt.version,
 				Kind:    tt.kind,
 			}
-			diff, err := convertPatchToDiff(patch, tt.old, inputs, oldInputs, gvk)
+			diff, err := convertPatchToDiff(patch, tt.old, inputs, oldInputs, forceNewProperties(gvk), nil)
 
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expected, diff)
 		})
 	}
 }
+
+func TestForceNewPropertiesDaemonSet(t *testing.T) {
+	props := forceNewProperties(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"})
+	assert.Contains(t, props, ".spec.selector")
+}
+
+func TestImmutableFieldsFromError(t *testing.T) {
+	gk := schema.GroupKind{Group: "example.com", Kind: "Widget"}
+	err := errors.NewInvalid(gk, "foo", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "selector"), nil, "field is immutable"),
+		field.Invalid(field.NewPath("spec", "containers").Index(0).Child("image"), "", "field is immutable"),
+		field.Invalid(field.NewPath("metadata", "labels").Key("app"), "", "field is immutable"),
+		field.Invalid(field.NewPath("spec", "replicas"), -1, "must be greater than or equal to 0"),
+		field.Invalid(field.NewPath("spec", "ports"), "", "may not be immutable and mutable"),
+		field.Forbidden(field.NewPath("spec", "nodeName"), "may not update the node"),
+		field.Required(field.NewPath("spec", "template"), ""),
+	})
+	assert.Equal(t, properties{
+		".spec.selector",
+		".spec.containers[*].image",
+		".metadata.labels",
+	}, immutableFieldsFromError(err, nil, nil))
+
+	assert.Nil(t, immutableFieldsFromError(errors.NewNotFound(schema.GroupResource{}, "foo"), nil, nil))
+}
+
+func TestImmutableFieldsFromErrorForbiddenUpdate(t *testing.T) {
+	gk := schema.GroupKind{Group: "apps", Kind: "StatefulSet"}
+	err := errors.NewInvalid(gk, "foo", field.ErrorList{
+		field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other than 'replicas', "+
+			"'template', 'updateStrategy' and 'minReadySeconds' are forbidden"),
+	})
+	oldInputs := object{"spec": object{
+		"replicas":             float64(1),
+		"serviceName":          "web",
+		"template":             object{"spec": object{"containers": list{object{"image": "nginx:1.19"}}}},
+		"volumeClaimTemplates": list{object{"metadata": object{"name": "data"}}},
+	}}
+
+	tests := []struct {
+		name      string
+		newInputs object
+		expected  properties
+	}{
+		{"Updatable fields changed", object{"spec": object{
+			"replicas":             float64(3),
+			"serviceName":          "web",
+			"template":             object{"spec": object{"containers": list{object{"image": "nginx:1.20"}}}},
+			"volumeClaimTemplates": list{object{"metadata": object{"name": "data"}}},
+		}}, nil},
+		{"Forbidden fields changed", object{"spec": object{
+			"replicas":             float64(3),
+			"serviceName":          "web-headless",
+			"template":             object{"spec": object{"containers": list{object{"image": "nginx:1.20"}}}},
+			"volumeClaimTemplates": list{object{"metadata": object{"name": "logs"}}},
+			"podManagementPolicy":  "Parallel",
+		}}, properties{".spec.podManagementPolicy", ".spec.serviceName", ".spec.volumeClaimTemplates"}},
+		{"Spec can't be compared", object{}, properties{".spec"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, immutableFieldsFromError(err, oldInputs, tt.newInputs))
+		})
+	}
+}
+
+func TestCRDForceNewProperties(t *testing.T) {
+	immutable := func(rule string) object {
+		return object{"type": "string", "x-kubernetes-validations": list{object{"rule": rule}}}
+	}
+	crd := object{
+		"spec": object{
+			"versions": list{
+				object{"name": "v1alpha1", "schema": object{"openAPIV3Schema": object{
+					"properties": object{"spec": object{"properties": object{"region": immutable("self == oldSelf")}}},
+				}}},
+				object{"name": "v1", "schema": object{"openAPIV3Schema": object{
+					"properties": object{
+						"spec": object{
+							"properties": object{
+								"region": immutable("self == oldSelf"),
+								"zone":   immutable("oldSelf==self"),
+								"size":   immutable("self >= oldSelf"),
+								"disks": object{
+									"type": "array",
+									"items": object{
+										"properties": object{"name": immutable("self == oldSelf")},
+									},
+								},
+							},
+						},
+					},
+				}}},
+			},
+		},
+	}
+	assert.Equal(t, properties{".spec.disks[*].name", ".spec.region", ".spec.zone"}, crdForceNewProperties(crd, "v1"))
+	assert.Nil(t, crdForceNewProperties(crd, "v2"))
+
+	assert.True(t, isCustomResourceGroup("example.com"))
+	assert.False(t, isCustomResourceGroup("apps"))
+	assert.False(t, isCustomResourceGroup("networking.k8s.io"))
+}
+
+func TestPatchToDiffMergeKeys(t *testing.T) {
+	var (
+		A = &pulumirpc.PropertyDiff{Kind: pulumirpc.PropertyDiff_ADD}
+		D = &pulumirpc.PropertyDiff{Kind: pulumirpc.PropertyDiff_DELETE, InputDiff: true}
+		U = &pulumirpc.PropertyDiff{Kind: pulumirpc.PropertyDiff_UPDATE}
+	)
+
+	mergeKeys := func(fields []string) []string {
+		switch strings.Join(fields, ".") {
+		case "spec.containers", "spec.containers.env":
+			return []string{"name"}
+		case "spec.containers.ports":
+			return []string{"containerPort", "protocol"}
+		default:
+			return nil
+		}
+	}
+	pod := func(env list, ports list) object {
+		return object{"spec": object{"containers": list{
+			object{"name": "sidecar", "image": "envoy"},
+			object{"name": "app", "image": "nginx", "env": env, "ports": ports},
+		}}}
+	}
+	env := func(names ...string) list {
+		l := list{}
+		for _, name := range names {
+			l = append(l, object{"name": name, "value": "1"})
+		}
+		return l
+	}
+	port := object{"containerPort": float64(80), "protocol": "TCP"}
+
+	tests := []struct {
+		name     string
+		old      object
+		new      object
+		expected expected
+	}{
+		{
+			name: "Insert element",
+			old:  pod(env("A", "B"), list{port}),
+			new:  pod(env("LOG_LEVEL", "A", "B"), list{port}),
+			expected: expected{
+				"spec.containers[name=app].env[name=LOG_LEVEL]": A,
+			},
+		},
+		{
+			name: "Remove element",
+			old:  pod(env("A", "LOG_LEVEL", "B"), list{port}),
+			new:  pod(env("A", "B"), list{port}),
+			expected: expected{
+				"spec.containers[name=app].env[name=LOG_LEVEL]": D,
+			},
+		},
+		{
+			name: "Update element",
+			old:  pod(env("A", "B"), list{port}),
+			new: pod(list{object{"name": "A", "value": "1"}, object{"name": "B", "value": "2"}},
+				list{port}),
+			expected: expected{
+				"spec.containers[name=app].env[name=B].value": U,
+			},
+		},
+		{
+			name: "Composite keys",
+			old:  pod(env("A"), list{port}),
+			new:  pod(env("A"), list{object{"containerPort": float64(8080), "protocol": "TCP"}, port}),
+			expected: expected{
+				"spec.containers[name=app].ports[containerPort=8080,protocol=TCP]": A,
+			},
+		},
+		{
+			name: "Duplicate keys fall back to indices",
+			old:  pod(env("A", "A"), list{port}),
+			new:  pod(env("B", "A", "A"), list{port}),
+			expected: expected{
+				"spec.containers[name=app].env[0].name": U,
+				"spec.containers[name=app].env[2]":      A,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldJSON, err := json.Marshal(tt.old)
+			assert.NoError(t, err)
+			newJSON, err := json.Marshal(tt.new)
+			assert.NoError(t, err)
+
+			patchBytes, err := jsonpatch.CreateMergePatch(oldJSON, newJSON)
+			assert.NoError(t, err)
+			patch := map[string]interface{}{}
+			err = json.Unmarshal(patchBytes, &patch)
+			assert.NoError(t, err)
+
+			diff, err := convertPatchToDiff(patch, tt.old, tt.new, tt.old, nil, mergeKeys)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, diff)
+		})
+	}
+}
+
+func TestCRDForceNewPropertiesCache(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	// The client set is not configured, so a lookup in the cluster would panic.
+	k := &kubeProvider{crdForceNew: map[schema.GroupVersionKind]properties{gvk: {".spec.region"}}}
+
+	assert.Equal(t, properties{".spec.region"}, k.crdForceNewProperties(gvk))
+	assert.Equal(t, properties{".spec.region"}, k.crdForceNewProperties(gvk))
+	assert.Nil(t, k.crdForceNewProperties(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}))
+
+	// The cache is cleared along with the OpenAPI schema, e.g., when a CustomResourceDefinition is created.
+	k.invalidateResources()
+	assert.Nil(t, k.crdForceNew)
+}
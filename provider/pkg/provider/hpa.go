@@ -0,0 +1,206 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/kinds"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/resource"
+	logger "github.com/pulumi/pulumi/sdk/v2/go/common/util/logging"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// hpaGVK is the version of HorizontalPodAutoscaler used to look up the autoscalers in the cluster. autoscaling/v1
+// is served by every supported version of Kubernetes, and has the same `scaleTargetRef` as later versions.
+var hpaGVK = schema.GroupVersionKind{Group: "autoscaling", Version: "v1", Kind: string(kinds.HorizontalPodAutoscaler)}
+
+// replicasPath is the path of the field that a HorizontalPodAutoscaler manages on its scale target.
+var replicasPath = metadata.FieldPath{"spec", "replicas"}
+
+// isHPAScalable returns true if `spec.replicas` of the object is left to a HorizontalPodAutoscaler that targets it.
+func isHPAScalable(obj *unstructured.Unstructured) bool {
+	switch kinds.Kind(obj.GetKind()) {
+	case kinds.Deployment, kinds.StatefulSet:
+		return true
+	default:
+		return false
+	}
+}
+
+// hpaKey identifies a HorizontalPodAutoscaler.
+func hpaKey(namespace, name string) string {
+	return fqName(canonicalNamespace(namespace), name)
+}
+
+// hpaTargetKey identifies the scale target of a HorizontalPodAutoscaler.
+func hpaTargetKey(namespace, kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s", canonicalNamespace(namespace), kind, name)
+}
+
+// scaleTargetKey returns the key of the scale target of a HorizontalPodAutoscaler, or "" if the target is not set or
+// has computed values.
+func scaleTargetKey(hpa *unstructured.Unstructured) string {
+	kind, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "kind")
+	name, _, _ := unstructured.NestedString(hpa.Object, "spec", "scaleTargetRef", "name")
+	if kind == "" || name == "" {
+		return ""
+	}
+	return hpaTargetKey(hpa.GetNamespace(), kind, name)
+}
+
+// recordHPATarget records the scale target of a HorizontalPodAutoscaler of the stack, so that its target is
+// recognized even if the autoscaler does not exist in the cluster yet. It is called when the autoscaler is checked,
+// created and updated, so that a workload that is checked before its autoscaler is still recognized when it is
+// updated. If the target has computed values, e.g., the name of a workload that is being replaced during a preview,
+// the target of the previous inputs is recorded instead. Targets are recorded by autoscaler, so an autoscaler that is
+// retargeted no longer scales its previous target.
+func (k *kubeProvider) recordHPATarget(hpa, previous *unstructured.Unstructured) {
+	target := scaleTargetKey(hpa)
+	if target == "" && previous != nil && previous.Object != nil {
+		target = scaleTargetKey(previous)
+	}
+	if target == "" {
+		return
+	}
+
+	k.hpaTargetsMutex.Lock()
+	defer k.hpaTargetsMutex.Unlock()
+	if k.hpaTargets == nil {
+		k.hpaTargets = map[string]string{}
+	}
+	k.hpaTargets[hpaKey(hpa.GetNamespace(), hpa.GetName())] = target
+}
+
+// scalingHPA returns the name of the HorizontalPodAutoscaler that scales the object, either one of the stack or one
+// that exists in the cluster. Returns "" if the object is not scaled by an autoscaler.
+func (k *kubeProvider) scalingHPA(obj *unstructured.Unstructured) string {
+	if !isHPAScalable(obj) {
+		return ""
+	}
+
+	target := hpaTargetKey(obj.GetNamespace(), obj.GetKind(), obj.GetName())
+	k.hpaTargetsMutex.Lock()
+	name := hpaScaling(k.hpaTargets, target, nil)
+	k.hpaTargetsMutex.Unlock()
+	if name != "" || k.clusterUnreachable || k.yamlRenderMode {
+		return name
+	}
+
+	// The autoscalers of the stack take precedence over their live versions, which may still scale a previous target.
+	clusterTargets := k.clusterHPATargetsIn(canonicalNamespace(obj.GetNamespace()))
+	k.hpaTargetsMutex.Lock()
+	defer k.hpaTargetsMutex.Unlock()
+	return hpaScaling(clusterTargets, target, k.hpaTargets)
+}
+
+// hpaScaling returns the name of the autoscaler of `targets` (which maps autoscalers to their scale targets) that
+// scales `target`, skipping the autoscalers in `skip`. Returns "" if there is none.
+func hpaScaling(targets map[string]string, target string, skip map[string]string) string {
+	for hpa, hpaTarget := range targets {
+		if _, skipped := skip[hpa]; hpaTarget == target && !skipped {
+			_, name := parseFqName(hpa)
+			return name
+		}
+	}
+	return ""
+}
+
+// clusterHPATargetsIn returns the scale targets of the HorizontalPodAutoscalers in a namespace of the cluster. The
+// autoscalers are listed once per namespace, and the listing is reused by the Diffs and Updates of the rest of the
+// operation; autoscalers of the stack that are created in the meantime are recorded with `recordHPATarget`.
+func (k *kubeProvider) clusterHPATargetsIn(namespace string) map[string]string {
+	k.hpaTargetsMutex.Lock()
+	targets, listed := k.clusterHPATargets[namespace]
+	k.hpaTargetsMutex.Unlock()
+	if listed {
+		return targets
+	}
+
+	targets = map[string]string{}
+	client, err := k.clientSet.ResourceClient(hpaGVK, namespace)
+	if err == nil {
+		var hpas *unstructured.UnstructuredList
+		if hpas, err = client.List(context.TODO(), metav1.ListOptions{}); err == nil {
+			targets = hpaTargetsOf(hpas.Items)
+		}
+	}
+	if err != nil {
+		// The listing is not retried, e.g., if the user is not allowed to list autoscalers.
+		logger.V(9).Infof("failed to look up HorizontalPodAutoscalers in namespace %q: %v", namespace, err)
+	}
+
+	k.hpaTargetsMutex.Lock()
+	defer k.hpaTargetsMutex.Unlock()
+	if k.clusterHPATargets == nil {
+		k.clusterHPATargets = map[string]map[string]string{}
+	}
+	k.clusterHPATargets[namespace] = targets
+	return targets
+}
+
+// hpaTargetsOf maps the HorizontalPodAutoscalers to their scale targets.
+func hpaTargetsOf(hpas []unstructured.Unstructured) map[string]string {
+	targets := map[string]string{}
+	for i := range hpas {
+		if target := scaleTargetKey(&hpas[i]); target != "" {
+			targets[hpaKey(hpas[i].GetNamespace(), hpas[i].GetName())] = target
+		}
+	}
+	return targets
+}
+
+// hpaPreservedFields returns `spec.replicas` and the name of the autoscaler if the object declares its replicas, but
+// is scaled by a HorizontalPodAutoscaler. Updates then preserve the live replica count, rather than scaling the
+// object back to the declared count.
+func (k *kubeProvider) hpaPreservedFields(obj *unstructured.Unstructured) ([]metadata.FieldPath, string) {
+	if _, declared, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "replicas"); !declared {
+		return nil, ""
+	}
+	hpa := k.scalingHPA(obj)
+	if hpa == "" {
+		return nil, ""
+	}
+	return []metadata.FieldPath{replicasPath}, hpa
+}
+
+// hpaReplicasDiffer returns true if the declared replicas of the object differ from its live replicas, i.e., if they
+// would be applied if the object were not scaled by a HorizontalPodAutoscaler.
+func hpaReplicasDiffer(obj, live *unstructured.Unstructured) bool {
+	if live == nil {
+		return true
+	}
+	declared, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "replicas")
+	current, _, _ := unstructured.NestedFieldNoCopy(live.Object, "spec", "replicas")
+	return declared == nil || current == nil || !equalNumbers(declared, current)
+}
+
+// logHPAScaling explains why the declared replicas of an object that is scaled by a HorizontalPodAutoscaler are not
+// applied. Nothing is logged if the declared replicas match the live replicas.
+func (k *kubeProvider) logHPAScaling(
+	ctx context.Context, urn resource.URN, obj, live *unstructured.Unstructured, hpa string,
+) {
+	if !hpaReplicasDiffer(obj, live) {
+		return
+	}
+	_ = k.host.Log(ctx, diag.Info, urn, fmt.Sprintf(
+		"%s %q is scaled by HorizontalPodAutoscaler %q, so its declared `spec.replicas` is only used when it is "+
+			"created", obj.GetKind(), obj.GetName(), hpa))
+}
@@ -0,0 +1,175 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func workload(kind, namespace, name string, replicas interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       map[string]interface{}{},
+	}}
+	if replicas != nil {
+		obj.Object["spec"].(map[string]interface{})["replicas"] = replicas
+	}
+	return obj
+}
+
+func autoscaler(namespace, name string, target interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "autoscaling/v2beta2",
+		"kind":       "HorizontalPodAutoscaler",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec": map[string]interface{}{
+			"scaleTargetRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": target},
+		},
+	}}
+}
+
+func TestHPAPreservedFields(t *testing.T) {
+	// The cluster is unreachable, so only the autoscalers of the stack are considered.
+	k := &kubeProvider{clusterUnreachable: true}
+
+	k.recordHPATarget(autoscaler("default", "web", "web"), nil)
+
+	preserve, hpa := k.hpaPreservedFields(workload("Deployment", "default", "web", float64(3)))
+	assert.Equal(t, []metadata.FieldPath{{"spec", "replicas"}}, preserve)
+	assert.Equal(t, "web", hpa)
+
+	// An unset namespace is the default namespace.
+	_, hpa = k.hpaPreservedFields(workload("Deployment", "", "web", float64(3)))
+	assert.Equal(t, "web", hpa)
+
+	// Replicas that are not declared are left to the autoscaler anyway.
+	preserve, hpa = k.hpaPreservedFields(workload("Deployment", "default", "web", nil))
+	assert.Nil(t, preserve)
+	assert.Empty(t, hpa)
+
+	for _, obj := range []*unstructured.Unstructured{
+		workload("Deployment", "prod", "web", float64(3)),
+		workload("Deployment", "default", "api", float64(3)),
+		workload("StatefulSet", "default", "web", float64(3)),
+		workload("ReplicaSet", "default", "web", float64(3)),
+	} {
+		preserve, hpa = k.hpaPreservedFields(obj)
+		assert.Nil(t, preserve)
+		assert.Empty(t, hpa)
+	}
+}
+
+func TestRecordHPATargetComputed(t *testing.T) {
+	k := &kubeProvider{clusterUnreachable: true}
+
+	// The target name is not known during the first preview.
+	k.recordHPATarget(autoscaler("default", "web", resource.Computed{}), nil)
+	assert.Empty(t, k.hpaTargets)
+
+	// Once the autoscaler exists, the target of its previous inputs is used while the new one is computed.
+	k.recordHPATarget(autoscaler("default", "web", resource.Computed{}), autoscaler("default", "web", "web"))
+	_, hpa := k.hpaPreservedFields(workload("Deployment", "default", "web", float64(3)))
+	assert.Equal(t, "web", hpa)
+}
+
+func TestRecordHPATargetRetarget(t *testing.T) {
+	// The autoscaler still scales the web Deployment in the cluster.
+	k := &kubeProvider{clusterHPATargets: map[string]map[string]string{
+		"default": hpaTargetsOf([]unstructured.Unstructured{*autoscaler("default", "scaler", "web")}),
+	}}
+	web := workload("Deployment", "default", "web", float64(3))
+	api := workload("Deployment", "default", "api", float64(3))
+
+	k.recordHPATarget(autoscaler("default", "scaler", "web"), nil)
+	_, hpa := k.hpaPreservedFields(web)
+	assert.Equal(t, "scaler", hpa)
+
+	// The autoscaler is retargeted, so the declared replicas of its previous target are applied again.
+	k.recordHPATarget(autoscaler("default", "scaler", "api"), autoscaler("default", "scaler", "web"))
+	preserve, hpa := k.hpaPreservedFields(web)
+	assert.Nil(t, preserve)
+	assert.Empty(t, hpa)
+	_, hpa = k.hpaPreservedFields(api)
+	assert.Equal(t, "scaler", hpa)
+	assert.Len(t, k.hpaTargets, 1)
+}
+
+func TestHPACheckedAfterWorkload(t *testing.T) {
+	// No autoscaler exists in the cluster yet. The listing is cached, so the cluster is not listed again (the client
+	// set is not configured, so listing it would panic).
+	k := &kubeProvider{clusterHPATargets: map[string]map[string]string{"default": {}}}
+	web := workload("Deployment", "default", "web", float64(3))
+
+	// The workload is diffed before the autoscaler is checked, so its replicas are not left to the autoscaler yet.
+	preserve, hpa := k.hpaPreservedFields(web)
+	assert.Nil(t, preserve)
+	assert.Empty(t, hpa)
+
+	// The autoscaler is checked (and created) before the workload is updated, so the update leaves the replicas to it.
+	k.recordHPATarget(autoscaler("default", "web-hpa", "web"), nil)
+	preserve, hpa = k.hpaPreservedFields(web)
+	assert.Equal(t, []metadata.FieldPath{{"spec", "replicas"}}, preserve)
+	assert.Equal(t, "web-hpa", hpa)
+}
+
+func TestClusterHPATargets(t *testing.T) {
+	k := &kubeProvider{clusterHPATargets: map[string]map[string]string{
+		"default": hpaTargetsOf([]unstructured.Unstructured{
+			*autoscaler("default", "web-hpa", "web"),
+			*autoscaler("default", "unset", ""),
+		}),
+	}}
+
+	_, hpa := k.hpaPreservedFields(workload("Deployment", "default", "web", float64(3)))
+	assert.Equal(t, "web-hpa", hpa)
+	_, hpa = k.hpaPreservedFields(workload("Deployment", "", "web", float64(3)))
+	assert.Equal(t, "web-hpa", hpa)
+	_, hpa = k.hpaPreservedFields(workload("Deployment", "default", "api", float64(3)))
+	assert.Empty(t, hpa)
+	assert.Len(t, k.clusterHPATargets["default"], 1)
+}
+
+func TestHPAReplicasDiffer(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		live *unstructured.Unstructured
+		want bool
+	}{
+		{"Same replicas", workload("Deployment", "default", "web", float64(3)),
+			workload("Deployment", "default", "web", int64(3)), false},
+		{"Scaled by the autoscaler", workload("Deployment", "default", "web", float64(3)),
+			workload("Deployment", "default", "web", int64(5)), true},
+		{"Computed replicas", workload("Deployment", "default", "web", resource.Computed{}),
+			workload("Deployment", "default", "web", int64(3)), true},
+		{"No live object", workload("Deployment", "default", "web", float64(3)), nil, true},
+		{"No live replicas", workload("Deployment", "default", "web", float64(3)),
+			workload("Deployment", "default", "web", nil), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hpaReplicasDiffer(tt.obj, tt.live); got != tt.want {
+				t.Errorf("hpaReplicasDiffer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/await"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v2/proto/go"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// ------------------------------------------------------------------------------------------------
+
+// Patch resources manage some of the fields of an object that was created by someone else, e.g., a
+// label on the `kube-system` Namespace. The object is identified by the `apiVersion`, `kind` and
+// `metadata` inputs of the Patch, and the fields to manage are the labels and annotations in
+// `metadata`, plus the top-level fields in `fields`.
+//
+// The fields are applied with server-side apply, using a field manager for each Patch resource, so
+// that the server tracks which fields the Patch owns. Fields removed from a Patch are removed from
+// the object when the Patch is updated, and all of its fields are removed when the Patch is deleted
+// (unless `retainOnDelete` is set), while fields set by other managers are left as they are.
+
+// ------------------------------------------------------------------------------------------------
+
+const (
+	patchResourceType = "kubernetes:core:Patch"
+
+	patchFieldsKey         = "fields"
+	patchForceKey          = "force"
+	patchRetainOnDeleteKey = "retainOnDelete"
+
+	// maxFieldManagerLength is the maximum length of a field manager name accepted by the API server.
+	maxFieldManagerLength = 128
+	// patchURNHashLength is the number of hex digits of the URN hash in the field manager of a Patch.
+	patchURNHashLength = 12
+)
+
+// isPatchResource returns true if the URN refers to a Patch resource.
+func isPatchResource(urn resource.URN) bool {
+	return string(urn.Type()) == patchResourceType
+}
+
+// patchFieldManager returns the field manager that owns the fields of a Patch resource. The manager ends
+// with a hash of the full URN, so that Patches with the same name in different stacks or projects don't
+// share fields; the name of the Patch is kept in front of it for readability.
+func (k *kubeProvider) patchFieldManager(urn resource.URN) string {
+	sum := sha256.Sum256([]byte(urn))
+	suffix := "-" + hex.EncodeToString(sum[:])[:patchURNHashLength]
+
+	fieldManager := fmt.Sprintf("%s-patch-%s", k.fieldManager, urn.Name())
+	if len(fieldManager)+len(suffix) > maxFieldManagerLength {
+		fieldManager = fieldManager[:maxFieldManagerLength-len(suffix)]
+	}
+	return fieldManager + suffix
+}
+
+// patchTarget returns the object to apply for the inputs of a Patch resource.
+func patchTarget(inputs resource.PropertyMap) *unstructured.Unstructured {
+	props := propMapToUnstructured(inputs).Object
+
+	obj := map[string]interface{}{}
+	if fields, ok := props[patchFieldsKey].(map[string]interface{}); ok {
+		for key, value := range fields {
+			obj[key] = value
+		}
+	}
+	for _, key := range []string{"apiVersion", "kind", "metadata"} {
+		if value, ok := props[key]; ok {
+			obj[key] = value
+		}
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+// patchIdentity returns the object to apply to release all fields of a Patch resource.
+func patchIdentity(target *unstructured.Unstructured) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(target.GetAPIVersion())
+	obj.SetKind(target.GetKind())
+	obj.SetNamespace(target.GetNamespace())
+	obj.SetName(target.GetName())
+	return obj
+}
+
+func (k *kubeProvider) unmarshalPatchProperties(label string, props *structpb.Struct) (resource.PropertyMap, error) {
+	return plugin.UnmarshalProperties(props, plugin.MarshalOptions{
+		Label:        label,
+		KeepUnknowns: true,
+		SkipNulls:    true,
+		RejectAssets: true,
+		KeepSecrets:  true,
+	})
+}
+
+// checkPatch validates the inputs of a Patch resource.
+func (k *kubeProvider) checkPatch(req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	news, err := k.unmarshalPatchProperties(fmt.Sprintf("%s.Check(%s).news", k.label(), urn), req.GetNews())
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []*pulumirpc.CheckFailure
+	target := patchTarget(news)
+	if !hasComputedValue(target) {
+		if target.GetAPIVersion() == "" {
+			failures = append(failures, &pulumirpc.CheckFailure{
+				Property: "apiVersion", Reason: "the apiVersion of the object to patch is required"})
+		}
+		if target.GetKind() == "" {
+			failures = append(failures, &pulumirpc.CheckFailure{
+				Property: "kind", Reason: "the kind of the object to patch is required"})
+		}
+		if target.GetName() == "" {
+			failures = append(failures, &pulumirpc.CheckFailure{
+				Property: "metadata", Reason: "the name of the object to patch is required"})
+		}
+	}
+
+	return &pulumirpc.CheckResponse{Inputs: req.GetNews(), Failures: failures}, nil
+}
+
+// diffPatch compares the old and new inputs of a Patch resource. Only the managed fields are compared, since
+// other fields of the object are not managed by the Patch. A Patch is replaced if it refers to a different object.
+func (k *kubeProvider) diffPatch(req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Diff(%s)", k.label(), urn)
+	olds, err := k.unmarshalPatchProperties(fmt.Sprintf("%s.olds", label), req.GetOlds())
+	if err != nil {
+		return nil, err
+	}
+	news, err := k.unmarshalPatchProperties(fmt.Sprintf("%s.news", label), req.GetNews())
+	if err != nil {
+		return nil, err
+	}
+
+	diff := olds.Diff(news)
+	if diff == nil {
+		return &pulumirpc.DiffResponse{Changes: pulumirpc.DiffResponse_DIFF_NONE}, nil
+	}
+
+	var changes, replaces []string
+	for _, key := range diff.Keys() {
+		if diff.Changed(key) {
+			changes = append(changes, string(key))
+		}
+	}
+
+	oldTarget, newTarget := patchTarget(olds), patchTarget(news)
+	if !hasComputedValue(newTarget) {
+		if oldTarget.GetAPIVersion() != newTarget.GetAPIVersion() {
+			replaces = append(replaces, "apiVersion")
+		}
+		if oldTarget.GetKind() != newTarget.GetKind() {
+			replaces = append(replaces, "kind")
+		}
+		if oldTarget.GetName() != newTarget.GetName() ||
+			canonicalNamespace(oldTarget.GetNamespace()) != canonicalNamespace(newTarget.GetNamespace()) {
+			replaces = append(replaces, "metadata")
+		}
+	}
+
+	return &pulumirpc.DiffResponse{
+		Changes:  pulumirpc.DiffResponse_DIFF_SOME,
+		Replaces: replaces,
+		Diffs:    changes,
+		// The old Patch must release its fields before the new Patch applies them, since both use the same field
+		// manager.
+		DeleteBeforeReplace: len(replaces) > 0,
+	}, nil
+}
+
+// applyPatch applies the fields of a Patch resource to an existing object.
+func (k *kubeProvider) applyPatch(
+	urn resource.URN, props *structpb.Struct, preview bool,
+) (string, error) {
+	if k.yamlRenderMode {
+		return "", fmt.Errorf("Patch resources are not supported with the `renderYamlToDirectory` provider config")
+	}
+	if !preview && k.clusterUnreachable {
+		return "", fmt.Errorf("configured Kubernetes cluster is unreachable: %s", k.clusterUnreachableReason)
+	}
+
+	inputs, err := k.unmarshalPatchProperties(fmt.Sprintf("%s.Apply(%s).properties", k.label(), urn), props)
+	if err != nil {
+		return "", err
+	}
+	target := patchTarget(inputs)
+	if preview && (hasComputedValue(target) || k.clusterUnreachable) {
+		return fqObjName(target), nil
+	}
+
+	client, err := k.patchClient(target)
+	if err != nil {
+		return "", err
+	}
+
+	// Server-side apply creates objects that do not exist, but a Patch only changes existing objects.
+	if _, err = client.Get(context.TODO(), target.GetName(), metav1.GetOptions{}); err != nil {
+		if errors.IsNotFound(err) {
+			return "", fmt.Errorf("cannot patch %s %s because it does not exist", target.GetKind(), fqObjName(target))
+		}
+		return "", err
+	}
+
+	// The `force` input is passed explicitly, rather than with the patchForce annotation, which would be set on the
+	// patched object.
+	force := inputs[patchForceKey]
+	_, err = await.ServerSideApplyPatch(
+		client, target, k.patchFieldManager(urn), force.IsBool() && force.BoolValue(), preview)
+	if err != nil {
+		return "", err
+	}
+	return fqObjName(target), nil
+}
+
+func (k *kubeProvider) patchClient(target *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	client, err := k.clientSet.ResourceClientForObject(target)
+	if err != nil && meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("cannot patch %s %s because the Kubernetes API server reported that "+
+			"apiVersion %s does not exist", target.GetKind(), fqObjName(target), target.GetAPIVersion())
+	}
+	return client, err
+}
+
+func (k *kubeProvider) createPatch(req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+	id, err := k.applyPatch(resource.URN(req.GetUrn()), req.GetProperties(), req.GetPreview())
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.CreateResponse{Id: id, Properties: req.GetProperties()}, nil
+}
+
+func (k *kubeProvider) updatePatch(req *pulumirpc.UpdateRequest) (*pulumirpc.UpdateResponse, error) {
+	if _, err := k.applyPatch(resource.URN(req.GetUrn()), req.GetNews(), req.GetPreview()); err != nil {
+		return nil, err
+	}
+	return &pulumirpc.UpdateResponse{Properties: req.GetNews()}, nil
+}
+
+// readPatch checks that the object of a Patch resource still exists. The fields of the object are not read, since
+// they may be changed by other managers.
+func (k *kubeProvider) readPatch(ctx context.Context, req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	if k.clusterUnreachable {
+		_ = k.host.Log(ctx, diag.Warning, urn, fmt.Sprintf(
+			"configured Kubernetes cluster is unreachable: %s", k.clusterUnreachableReason))
+		return deleteResponse, nil
+	}
+
+	props, err := k.unmarshalPatchProperties(fmt.Sprintf("%s.Read(%s).properties", k.label(), urn),
+		req.GetProperties())
+	if err != nil {
+		return nil, err
+	}
+	target := patchTarget(props)
+	if target.GetAPIVersion() == "" || target.GetKind() == "" {
+		return nil, fmt.Errorf("cannot read Patch %s: importing Patch resources is not supported", req.GetId())
+	}
+
+	client, err := k.patchClient(target)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = client.Get(context.TODO(), target.GetName(), metav1.GetOptions{}); err != nil {
+		if errors.IsNotFound(err) {
+			return deleteResponse, nil
+		}
+		return nil, err
+	}
+
+	return &pulumirpc.ReadResponse{Id: req.GetId(), Properties: req.GetProperties(), Inputs: req.GetInputs()}, nil
+}
+
+// deletePatch removes the fields of a Patch resource from its object, unless `retainOnDelete` is set.
+func (k *kubeProvider) deletePatch(ctx context.Context, req *pulumirpc.DeleteRequest) (*pbempty.Empty, error) {
+	urn := resource.URN(req.GetUrn())
+	if k.clusterUnreachable {
+		return nil, fmt.Errorf("configured Kubernetes cluster is unreachable: %s\n"+
+			"If the cluster has been deleted, you can edit the pulumi state to remove this resource",
+			k.clusterUnreachableReason)
+	}
+
+	props, err := k.unmarshalPatchProperties(fmt.Sprintf("%s.Delete(%s).properties", k.label(), urn),
+		req.GetProperties())
+	if err != nil {
+		return nil, err
+	}
+	target := patchTarget(props)
+	if retain := props[patchRetainOnDeleteKey]; retain.IsBool() && retain.BoolValue() {
+		_ = k.host.LogStatus(ctx, diag.Info, urn, fmt.Sprintf(
+			"retained the patched fields of %s %s", target.GetKind(), fqObjName(target)))
+		return &pbempty.Empty{}, nil
+	}
+
+	client, err := k.clientSet.ResourceClientForObject(target)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// The apiVersion of the object no longer exists, so neither does the object.
+			return &pbempty.Empty{}, nil
+		}
+		return nil, err
+	}
+	if _, err = client.Get(context.TODO(), target.GetName(), metav1.GetOptions{}); err != nil {
+		if errors.IsNotFound(err) {
+			return &pbempty.Empty{}, nil
+		}
+		return nil, err
+	}
+
+	// Applying only the identity of the object releases all the fields owned by the field manager of the Patch. The
+	// server removes the fields that no other manager owns.
+	_, err = await.ServerSideApplyPatch(client, patchIdentity(target), k.patchFieldManager(urn), false, false)
+	if err != nil {
+		return nil, err
+	}
+	return &pbempty.Empty{}, nil
+}
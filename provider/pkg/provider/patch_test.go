@@ -0,0 +1,132 @@
+// Copyright 2016-2020, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v2/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v2/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v2/proto/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func daemonSetPatch(name string, tolerations ...interface{}) resource.PropertyMap {
+	return resource.NewPropertyMapFromMap(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "DaemonSet",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "kube-system",
+		},
+		"fields": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{"tolerations": tolerations},
+				},
+			},
+		},
+		"retainOnDelete": true,
+	})
+}
+
+func TestPatchTarget(t *testing.T) {
+	target := patchTarget(daemonSetPatch("kube-proxy", map[string]interface{}{"operator": "Exists"}))
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "DaemonSet",
+		"metadata": map[string]interface{}{
+			"name":      "kube-proxy",
+			"namespace": "kube-system",
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"tolerations": []interface{}{map[string]interface{}{"operator": "Exists"}},
+				},
+			},
+		},
+	}, target.Object)
+
+	identity := patchIdentity(target)
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "DaemonSet",
+		"metadata": map[string]interface{}{
+			"name":      "kube-proxy",
+			"namespace": "kube-system",
+		},
+	}, identity.Object)
+}
+
+func TestPatchFieldManager(t *testing.T) {
+	k := &kubeProvider{fieldManager: "pulumi-kubernetes"}
+
+	urn := resource.NewURN("dev", "proj", "", patchResourceType, "kube-proxy-tolerations")
+	assert.True(t, isPatchResource(urn))
+	assert.Regexp(t, "^pulumi-kubernetes-patch-kube-proxy-tolerations-[0-9a-f]{12}$", k.patchFieldManager(urn))
+	assert.Equal(t, k.patchFieldManager(urn), k.patchFieldManager(urn))
+
+	// Patches with the same name in other stacks or projects must not share the field manager.
+	otherStack := resource.NewURN("prod", "proj", "", patchResourceType, "kube-proxy-tolerations")
+	assert.NotEqual(t, k.patchFieldManager(urn), k.patchFieldManager(otherStack))
+	otherProject := resource.NewURN("dev", "other", "", patchResourceType, "kube-proxy-tolerations")
+	assert.NotEqual(t, k.patchFieldManager(urn), k.patchFieldManager(otherProject))
+
+	long := resource.NewURN("dev", "proj", "", patchResourceType, tokens.QName(strings.Repeat("x", 200)))
+	assert.Len(t, k.patchFieldManager(long), maxFieldManagerLength)
+	otherLong := resource.NewURN("prod", "proj", "", patchResourceType, tokens.QName(strings.Repeat("x", 200)))
+	assert.NotEqual(t, k.patchFieldManager(long), k.patchFieldManager(otherLong))
+
+	assert.False(t, isPatchResource(resource.NewURN("dev", "proj", "", "kubernetes:apps/v1:DaemonSet", "foo")))
+}
+
+func TestDiffPatch(t *testing.T) {
+	k := &kubeProvider{}
+	urn := resource.NewURN("dev", "proj", "", patchResourceType, "kube-proxy-tolerations")
+	toleration := map[string]interface{}{"operator": "Exists"}
+
+	diff := func(olds, news resource.PropertyMap) *pulumirpc.DiffResponse {
+		oldStruct, err := plugin.MarshalProperties(olds, plugin.MarshalOptions{})
+		assert.NoError(t, err)
+		newStruct, err := plugin.MarshalProperties(news, plugin.MarshalOptions{})
+		assert.NoError(t, err)
+		resp, err := k.diffPatch(&pulumirpc.DiffRequest{Urn: string(urn), Olds: oldStruct, News: newStruct})
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := diff(daemonSetPatch("kube-proxy", toleration), daemonSetPatch("kube-proxy", toleration))
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_NONE, resp.Changes)
+
+	resp = diff(daemonSetPatch("kube-proxy", toleration), daemonSetPatch("kube-proxy"))
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, resp.Changes)
+	assert.Equal(t, []string{"fields"}, resp.Diffs)
+	assert.Empty(t, resp.Replaces)
+	assert.False(t, resp.DeleteBeforeReplace)
+
+	forced := daemonSetPatch("kube-proxy", toleration)
+	forced["force"] = resource.NewBoolProperty(true)
+	resp = diff(daemonSetPatch("kube-proxy", toleration), forced)
+	assert.Equal(t, []string{"force"}, resp.Diffs)
+	assert.Empty(t, resp.Replaces)
+
+	resp = diff(daemonSetPatch("kube-proxy", toleration), daemonSetPatch("aws-node", toleration))
+	assert.Equal(t, []string{"metadata"}, resp.Diffs)
+	assert.Equal(t, []string{"metadata"}, resp.Replaces)
+	assert.True(t, resp.DeleteBeforeReplace)
+}
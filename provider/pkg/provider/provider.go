@@ -57,6 +57,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientapi "k8s.io/client-go/tools/clientcmd/api"
@@ -114,11 +115,22 @@ type kubeProvider struct {
 
 	enableDryRun                bool
 	enableSecrets               bool
+	rollbackOnFailure           bool
 	suppressDeprecationWarnings bool
 
 	yamlRenderMode bool
 	yamlDirectory  string
 
+	awaitRecordingDirectory string
+	streamEvents            bool
+	defaultTimeouts         *metadata.DefaultTimeouts
+
+	serverSideApply bool
+	fieldManager    string
+
+	deleteOptions metadata.DeleteOptions
+	ignoreFields  metadata.IgnoreFieldsRules
+
 	clusterUnreachable       bool   // Kubernetes cluster is unreachable.
 	clusterUnreachableReason string // Detailed error message if cluster is unreachable.
 
@@ -126,10 +138,16 @@ type kubeProvider struct {
 
 	clientSet  *clients.DynamicClientSet
 	logClient  *clients.LogClient
+	watches    *clients.WatchMultiplexer
 	k8sVersion cluster.ServerVersion
 
 	resources      k8sopenapi.Resources
+	crdForceNew    map[schema.GroupVersionKind]properties // Force-new properties of custom resources, by GVK.
 	resourcesMutex sync.RWMutex
+
+	hpaTargets        map[string]string            // Scale targets of the stack's autoscalers, by namespace/name.
+	clusterHPATargets map[string]map[string]string // Scale targets of the cluster's autoscalers, by namespace.
+	hpaTargetsMutex   sync.Mutex
 }
 
 var _ pulumirpc.ResourceProviderServer = (*kubeProvider)(nil)
@@ -146,6 +164,7 @@ func makeKubeProvider(
 		providerPackage:             name,
 		enableDryRun:                false,
 		enableSecrets:               false,
+		rollbackOnFailure:           false,
 		suppressDeprecationWarnings: false,
 	}, nil
 }
@@ -175,6 +194,7 @@ func (k *kubeProvider) invalidateResources() {
 	defer k.resourcesMutex.Unlock()
 
 	k.resources = nil
+	k.crdForceNew = nil
 }
 
 // Construct creates a new instance of the provided component resource and returns its state.
@@ -403,6 +423,22 @@ func (k *kubeProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequ
 		k.enableDryRun = true
 	}
 
+	rollbackOnFailure := func() bool {
+		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
+		if enabled, exists := vars["kubernetes:config:rollbackOnFailure"]; exists {
+			return enabled == trueStr
+		}
+		// If the provider flag is not set, fall back to the ENV var.
+		if enabled, exists := os.LookupEnv("PULUMI_K8S_ROLLBACK_ON_FAILURE"); exists {
+			return enabled == trueStr
+		}
+		// Default to false.
+		return false
+	}
+	if rollbackOnFailure() {
+		k.rollbackOnFailure = true
+	}
+
 	suppressDeprecationWarnings := func() bool {
 		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
 		if enabled, exists := vars["kubernetes:config:suppressDeprecationWarnings"]; exists {
@@ -429,6 +465,140 @@ func (k *kubeProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequ
 	k.yamlDirectory = renderYamlToDirectory()
 	k.yamlRenderMode = len(k.yamlDirectory) > 0
 
+	recordAwaitEventsToDirectory := func() string {
+		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
+		if directory, exists := vars["kubernetes:config:recordAwaitEventsToDirectory"]; exists {
+			return directory
+		}
+		// If the provider flag is not set, fall back to the ENV var.
+		if directory, exists := os.LookupEnv("PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY"); exists {
+			return directory
+		}
+		return ""
+	}
+	k.awaitRecordingDirectory = recordAwaitEventsToDirectory()
+
+	streamEvents := func() bool {
+		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
+		if enabled, exists := vars["kubernetes:config:streamEvents"]; exists {
+			return enabled == trueStr
+		}
+		// If the provider flag is not set, fall back to the ENV var.
+		if enabled, exists := os.LookupEnv("PULUMI_K8S_STREAM_EVENTS"); exists {
+			return enabled == trueStr
+		}
+		// Default to false.
+		return false
+	}
+	if streamEvents() {
+		k.streamEvents = true
+	}
+
+	defaultTimeouts := func() string {
+		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
+		if timeouts, exists := vars["kubernetes:config:defaultTimeouts"]; exists {
+			return timeouts
+		}
+		// If the provider flag is not set, fall back to the ENV var.
+		if timeouts, exists := os.LookupEnv("PULUMI_K8S_DEFAULT_TIMEOUTS"); exists {
+			return timeouts
+		}
+		return ""
+	}
+	if timeouts := defaultTimeouts(); timeouts != "" {
+		parsed, err := metadata.ParseDefaultTimeouts(timeouts)
+		if err != nil {
+			return nil, err
+		}
+		k.defaultTimeouts = parsed
+	}
+
+	enableServerSideApply := func() bool {
+		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
+		if enabled, exists := vars["kubernetes:config:enableServerSideApply"]; exists {
+			return enabled == trueStr
+		}
+		// If the provider flag is not set, fall back to the ENV var.
+		if enabled, exists := os.LookupEnv("PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY"); exists {
+			return enabled == trueStr
+		}
+		// Default to false.
+		return false
+	}
+	if enableServerSideApply() {
+		k.serverSideApply = true
+	}
+
+	fieldManager := func() string {
+		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
+		if manager, exists := vars["kubernetes:config:fieldManager"]; exists && manager != "" {
+			return manager
+		}
+		// If the provider flag is not set, fall back to the ENV var.
+		if manager, exists := os.LookupEnv("PULUMI_K8S_FIELD_MANAGER"); exists && manager != "" {
+			return manager
+		}
+		return await.DefaultFieldManager
+	}
+	k.fieldManager = fieldManager()
+
+	deletePropagationPolicy := func() string {
+		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
+		if policy, exists := vars["kubernetes:config:deletePropagationPolicy"]; exists && policy != "" {
+			return policy
+		}
+		// If the provider flag is not set, fall back to the ENV var.
+		if policy, exists := os.LookupEnv("PULUMI_K8S_DELETE_PROPAGATION_POLICY"); exists {
+			return policy
+		}
+		return ""
+	}
+	if policy := deletePropagationPolicy(); policy != "" {
+		parsed, err := metadata.ParsePropagationPolicy(policy)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "invalid deletePropagationPolicy")
+		}
+		k.deleteOptions.PropagationPolicy = &parsed
+	}
+
+	deleteGracePeriodSeconds := func() string {
+		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
+		if seconds, exists := vars["kubernetes:config:deleteGracePeriodSeconds"]; exists && seconds != "" {
+			return seconds
+		}
+		// If the provider flag is not set, fall back to the ENV var.
+		if seconds, exists := os.LookupEnv("PULUMI_K8S_DELETE_GRACE_PERIOD_SECONDS"); exists {
+			return seconds
+		}
+		return ""
+	}
+	if seconds := deleteGracePeriodSeconds(); seconds != "" {
+		parsed, err := metadata.ParseGracePeriodSeconds(seconds)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "invalid deleteGracePeriodSeconds")
+		}
+		k.deleteOptions.GracePeriodSeconds = &parsed
+	}
+
+	ignoreFields := func() string {
+		// If the provider flag is set, use that value to determine behavior. This will override the ENV var.
+		if rules, exists := vars["kubernetes:config:ignoreFields"]; exists {
+			return rules
+		}
+		// If the provider flag is not set, fall back to the ENV var.
+		if rules, exists := os.LookupEnv("PULUMI_K8S_IGNORE_FIELDS"); exists {
+			return rules
+		}
+		return ""
+	}
+	if rules := ignoreFields(); rules != "" {
+		parsed, err := metadata.ParseIgnoreFieldsRules(rules)
+		if err != nil {
+			return nil, err
+		}
+		k.ignoreFields = parsed
+	}
+
 	// Rather than erroring out on an invalid k8s config, mark the cluster as unreachable and conditionally bail out on
 	// operations that require a valid cluster. This will allow us to perform invoke operations using the default
 	// provider.
@@ -512,6 +682,7 @@ func (k *kubeProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequ
 			return nil, err
 		}
 		k.clientSet = cs
+		k.watches = clients.NewWatchMultiplexer(cs)
 
 		lc, err := clients.NewLogClient(k.config)
 		if err != nil {
@@ -1006,6 +1177,10 @@ func (k *kubeProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (
 	label := fmt.Sprintf("%s.Check(%s)", k.label(), urn)
 	logger.V(9).Infof("%s executing", label)
 
+	if isPatchResource(urn) {
+		return k.checkPatch(req)
+	}
+
 	// Obtain old resource inputs. This is the old version of the resource(s) supplied by the user as
 	// an update.
 	oldResInputs := req.GetOlds()
@@ -1119,6 +1294,10 @@ func (k *kubeProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (
 		}
 	}
 
+	if newInputs.GetKind() == string(kinds.HorizontalPodAutoscaler) {
+		k.recordHPATarget(newInputs, oldInputs)
+	}
+
 	// HACK: Do not validate against OpenAPI spec if there is a computed value. The OpenAPI spec
 	// does not know how to deal with the placeholder values for computed values.
 	if !hasComputedValue(newInputs) && !k.clusterUnreachable {
@@ -1157,6 +1336,21 @@ func (k *kubeProvider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (
 		}
 	}
 
+	// Report invalid delete options now, rather than when the resource is deleted.
+	if _, err := metadata.GetDeleteOptions(newInputs, k.deleteOptions); err != nil {
+		failures = append(failures, &pulumirpc.CheckFailure{Reason: err.Error()})
+	}
+	if _, err := metadata.IgnoredFields(newInputs, k.ignoreFields); err != nil {
+		failures = append(failures, &pulumirpc.CheckFailure{Reason: err.Error()})
+	}
+
+	// With server-side apply, report fields that are owned by other field managers before the apply fails.
+	if k.serverSideApply && !hasComputedValue(newInputs) && !k.clusterUnreachable && !k.yamlRenderMode {
+		if failure := k.checkApplyConflicts(newInputs); failure != nil {
+			failures = append(failures, failure)
+		}
+	}
+
 	checkedInputs := resource.NewPropertyMapFromMap(newInputs.Object)
 	annotateSecrets(checkedInputs, news)
 
@@ -1202,6 +1396,10 @@ func (k *kubeProvider) Diff(
 	label := fmt.Sprintf("%s.Diff(%s)", k.label(), urn)
 	logger.V(9).Infof("%s executing", label)
 
+	if isPatchResource(urn) {
+		return k.diffPatch(req)
+	}
+
 	// Get old state. This is an object of the form {inputs: {...}, live: {...}} where `inputs` is the
 	// previous resource inputs supplied by the user, and `live` is the computed state of that inputs
 	// we received back from the API server.
@@ -1211,7 +1409,7 @@ func (k *kubeProvider) Diff(
 	if err != nil {
 		return nil, err
 	}
-	oldInputs, _ := parseCheckpointObject(oldState)
+	oldInputs, oldLive := parseCheckpointObject(oldState)
 
 	// Get new resource inputs. The user is submitting these as an update.
 	newResInputs, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
@@ -1257,10 +1455,20 @@ func (k *kubeProvider) Diff(
 		oldInputs.SetGroupVersionKind(gvk)
 	}
 
+	// Leave the replicas of a workload that is scaled by a HorizontalPodAutoscaler to the autoscaler.
+	preserve, hpa := k.hpaPreservedFields(newInputs)
+	if hpa != "" {
+		k.logHPAScaling(ctx, urn, newInputs, oldLive, hpa)
+	}
+
 	var patch []byte
 	var isClientSidePatch bool
 	var patchBase *unstructured.Unstructured
 
+	// Fields that require a replacement when they change: the provider's own rules, the transition rules of the
+	// schema of a CustomResourceDefinition, and the fields that the API server reports as immutable.
+	forceNewProps := append(forceNewProperties(gvk), k.crdForceNewProperties(gvk)...)
+
 	// Try to compute a server-side patch. Returns true iff the operation succeeded.
 	tryServerSidePatch := func() bool {
 		// If the resource's GVK changed, so compute patch using inputs.
@@ -1278,7 +1486,7 @@ func (k *kubeProvider) Diff(
 			return false
 		}
 
-		patch, patchBase, err = k.serverSidePatch(oldInputs, newInputs)
+		patch, patchBase, err = k.serverSidePatch(oldInputs, newInputs, preserve)
 		if k.isDryRunDisabledError(err) {
 			return false
 		}
@@ -1286,6 +1494,7 @@ func (k *kubeProvider) Diff(
 			// If the resource field is immutable.
 			if se.Status().Code == http.StatusUnprocessableEntity ||
 				strings.Contains(se.ErrStatus.Message, "field is immutable") {
+				forceNewProps = append(forceNewProps, immutableFieldsFromError(se, oldInputs.Object, newInputs.Object)...)
 				return false
 			}
 		}
@@ -1295,7 +1504,7 @@ func (k *kubeProvider) Diff(
 	}
 	if !tryServerSidePatch() {
 		isClientSidePatch = true
-		patch, err = k.inputPatch(oldInputs, newInputs)
+		patch, err = k.inputPatch(oldInputs, k.withoutIgnoredChanges(newInputs, oldInputs, preserve, false))
 		patchBase = oldInputs
 	}
 
@@ -1331,7 +1540,9 @@ func (k *kubeProvider) Diff(
 			changes = append(changes, k)
 		}
 
-		if detailedDiff, err = convertPatchToDiff(patchObj, patchBase.Object, newInputs.Object, oldInputs.Object, gvk); err != nil {
+		detailedDiff, err = convertPatchToDiff(
+			patchObj, patchBase.Object, newInputs.Object, oldInputs.Object, forceNewProps, k.mergeKeys(gvk))
+		if err != nil {
 			return nil, pkgerrors.Wrapf(
 				err, "Failed to check for changes in resource %s/%s because of an error "+
 					"converting JSON patch describing resource changes to a diff",
@@ -1398,6 +1609,10 @@ func (k *kubeProvider) Create(
 	label := fmt.Sprintf("%s.Create(%s)", k.label(), urn)
 	logger.V(9).Infof("%s executing", label)
 
+	if isPatchResource(urn) {
+		return k.createPatch(req)
+	}
+
 	// Except in the case of yamlRender mode, Create requires a connection to a k8s cluster, so bail out
 	// immediately if it is unreachable.
 	if !req.GetPreview() && k.clusterUnreachable && !k.yamlRenderMode {
@@ -1418,6 +1633,12 @@ func (k *kubeProvider) Create(
 
 	newInputs := propMapToUnstructured(newResInputs)
 
+	// Record the scale target of an autoscaler, so that workloads that were checked before it leave their replicas to
+	// it when they are updated.
+	if newInputs.GetKind() == string(kinds.HorizontalPodAutoscaler) {
+		k.recordHPATarget(newInputs, nil)
+	}
+
 	// If this is a preview and the input values contain unknowns, return them as-is. This is compatible with
 	// prior behavior implemented by the Pulumi engine. Similarly, if the server does not support server-side
 	// dry run, return the inputs as-is.
@@ -1430,7 +1651,7 @@ func (k *kubeProvider) Create(
 		}, nil
 	}
 
-	annotatedInputs, err := withLastAppliedConfig(newInputs)
+	annotatedInputs, err := k.annotateLastAppliedConfig(newInputs)
 	if err != nil {
 		return nil, pkgerrors.Wrapf(
 			err, "Failed to create resource %s/%s because of an error generating the %s value in "+
@@ -1477,13 +1698,21 @@ func (k *kubeProvider) Create(
 	}
 	config := await.CreateConfig{
 		ProviderConfig: await.ProviderConfig{
-			Context:           k.canceler.context,
-			Host:              k.host,
-			URN:               urn,
-			InitialAPIVersion: initialAPIVersion,
-			ClientSet:         k.clientSet,
-			DedupLogger:       logging.NewLogger(k.canceler.context, k.host, urn),
-			Resources:         resources,
+			Context:            k.canceler.context,
+			Host:               k.host,
+			URN:                urn,
+			InitialAPIVersion:  initialAPIVersion,
+			ClientSet:          k.clientSet,
+			LogClient:          k.logClient,
+			DedupLogger:        logging.NewLogger(k.canceler.context, k.host, urn),
+			Resources:          resources,
+			RecordingDirectory: k.awaitRecordingDirectory,
+			StreamEvents:       k.streamEvents,
+			DefaultTimeouts:    k.defaultTimeouts,
+			ServerSideApply:    k.serverSideApply,
+			FieldManager:       k.fieldManager,
+			IgnoreFields:       k.ignoreFields,
+			Watches:            k.watches,
 		},
 		Inputs:  annotatedInputs,
 		Timeout: req.Timeout,
@@ -1578,6 +1807,10 @@ func (k *kubeProvider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*p
 	label := fmt.Sprintf("%s.Read(%s)", k.label(), urn)
 	logger.V(9).Infof("%s executing", label)
 
+	if isPatchResource(urn) {
+		return k.readPatch(ctx, req)
+	}
+
 	// If the cluster is unreachable, consider the resource deleted and inform the user.
 	if k.clusterUnreachable {
 		_ = k.host.Log(ctx, diag.Warning, urn, fmt.Sprintf(
@@ -1637,13 +1870,21 @@ func (k *kubeProvider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*p
 	}
 	config := await.ReadConfig{
 		ProviderConfig: await.ProviderConfig{
-			Context:           k.canceler.context,
-			Host:              k.host,
-			URN:               urn,
-			InitialAPIVersion: initialAPIVersion,
-			ClientSet:         k.clientSet,
-			DedupLogger:       logging.NewLogger(k.canceler.context, k.host, urn),
-			Resources:         resources,
+			Context:            k.canceler.context,
+			Host:               k.host,
+			URN:                urn,
+			InitialAPIVersion:  initialAPIVersion,
+			ClientSet:          k.clientSet,
+			LogClient:          k.logClient,
+			DedupLogger:        logging.NewLogger(k.canceler.context, k.host, urn),
+			Resources:          resources,
+			RecordingDirectory: k.awaitRecordingDirectory,
+			StreamEvents:       k.streamEvents,
+			DefaultTimeouts:    k.defaultTimeouts,
+			ServerSideApply:    k.serverSideApply,
+			FieldManager:       k.fieldManager,
+			IgnoreFields:       k.ignoreFields,
+			Watches:            k.watches,
 		},
 		Inputs: oldInputs,
 		Name:   name,
@@ -1789,6 +2030,10 @@ func (k *kubeProvider) Update(
 	label := fmt.Sprintf("%s.Update(%s)", k.label(), urn)
 	logger.V(9).Infof("%s executing", label)
 
+	if isPatchResource(urn) {
+		return k.updatePatch(req)
+	}
+
 	// Except in the case of yamlRender mode, Update requires a connection to a k8s cluster, so bail out
 	// immediately if it is unreachable.
 	if !req.GetPreview() && k.clusterUnreachable && !k.yamlRenderMode {
@@ -1818,6 +2063,12 @@ func (k *kubeProvider) Update(
 	}
 	newInputs := propMapToUnstructured(newResInputs)
 
+	// Record the scale target of an autoscaler, so that workloads that were checked before it leave their replicas to
+	// it when they are updated.
+	if newInputs.GetKind() == string(kinds.HorizontalPodAutoscaler) {
+		k.recordHPATarget(newInputs, oldInputs)
+	}
+
 	// If this is a preview and the input values contain unknowns, return them as-is. This is compatible with
 	// prior behavior implemented by the Pulumi engine. Similarly, if the server does not support server-side
 	// dry run, return the inputs as-is.
@@ -1828,13 +2079,21 @@ func (k *kubeProvider) Update(
 		return &pulumirpc.UpdateResponse{Properties: req.News}, nil
 	}
 
-	annotatedInputs, err := withLastAppliedConfig(newInputs)
+	annotatedInputs, err := k.annotateLastAppliedConfig(newInputs)
 	if err != nil {
 		return nil, pkgerrors.Wrapf(
 			err, "Failed to update resource %s/%s because of an error generating the %s value in "+
 				"`.metadata.annotations`",
 			newInputs.GetNamespace(), newInputs.GetName(), lastAppliedConfigKey)
 	}
+	// The previous inputs are re-applied if the update is rolled back, so they need the annotation too; otherwise, a
+	// rollback would remove it from the live object.
+	annotatedPrevious, err := k.annotateLastAppliedConfig(oldInputs)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(
+			err, "Failed to update resource %s/%s because of an error generating the %s value of its previous "+
+				"inputs", newInputs.GetNamespace(), newInputs.GetName(), lastAppliedConfigKey)
+	}
 
 	initialAPIVersion, err := initialAPIVersion(oldState, oldInputs)
 	if err != nil {
@@ -1874,20 +2133,32 @@ func (k *kubeProvider) Update(
 	if err != nil {
 		return nil, pkgerrors.Wrapf(err, "Failed to fetch OpenAPI schema from the API server")
 	}
+	// Leave the replicas of a workload that is scaled by a HorizontalPodAutoscaler to the autoscaler.
+	preserve, _ := k.hpaPreservedFields(newInputs)
 	config := await.UpdateConfig{
 		ProviderConfig: await.ProviderConfig{
-			Context:           k.canceler.context,
-			Host:              k.host,
-			URN:               urn,
-			InitialAPIVersion: initialAPIVersion,
-			ClientSet:         k.clientSet,
-			DedupLogger:       logging.NewLogger(k.canceler.context, k.host, urn),
-			Resources:         resources,
+			Context:            k.canceler.context,
+			Host:               k.host,
+			URN:                urn,
+			InitialAPIVersion:  initialAPIVersion,
+			ClientSet:          k.clientSet,
+			LogClient:          k.logClient,
+			DedupLogger:        logging.NewLogger(k.canceler.context, k.host, urn),
+			Resources:          resources,
+			RecordingDirectory: k.awaitRecordingDirectory,
+			StreamEvents:       k.streamEvents,
+			DefaultTimeouts:    k.defaultTimeouts,
+			ServerSideApply:    k.serverSideApply,
+			FieldManager:       k.fieldManager,
+			IgnoreFields:       k.ignoreFields,
+			Watches:            k.watches,
 		},
-		Previous: oldInputs,
-		Inputs:   annotatedInputs,
-		Timeout:  req.Timeout,
-		DryRun:   req.GetPreview(),
+		Previous:          annotatedPrevious,
+		Inputs:            annotatedInputs,
+		Timeout:           req.Timeout,
+		DryRun:            req.GetPreview(),
+		RollbackOnFailure: k.rollbackOnFailure,
+		PreserveFields:    preserve,
 	}
 	// Apply update.
 	initialized, awaitErr := await.Update(config)
@@ -1907,6 +2178,12 @@ func (k *kubeProvider) Update(
 					"Verify that any required CRDs have been created", fqObjName(newInputs))
 		}
 
+		if rollbackErr, isRollbackErr := awaitErr.(await.RollbackError); isRollbackErr && rollbackErr.RolledBack() {
+			// The update failed and the previous inputs were re-applied. Checkpoint the previous inputs
+			// so that the next update retries the change.
+			return nil, k.rollbackError(label, oldState, oldInputs, awaitErr, initialAPIVersion)
+		}
+
 		var getErr error
 		initialized, getErr = k.readLiveObject(newInputs)
 		if getErr != nil {
@@ -1943,9 +2220,42 @@ func (k *kubeProvider) Update(
 			nil)
 	}
 
+	// Invalidate the cached schemas if this was a CRD, so that subsequent diffs of its custom resources use the
+	// updated schema and transition rules.
+	if clients.IsCRD(newInputs) && !req.GetPreview() {
+		k.invalidateResources()
+	}
+
 	return &pulumirpc.UpdateResponse{Properties: inputsAndComputed}, nil
 }
 
+// rollbackError returns a partial error for an update that failed and was rolled back to `oldInputs`. The
+// checkpoint records the previous inputs and the live state of the rolled-back object.
+func (k *kubeProvider) rollbackError(
+	label string, oldState resource.PropertyMap, oldInputs *unstructured.Unstructured, awaitErr error,
+	initialAPIVersion string,
+) error {
+	live := awaitErr.(await.PartialError).Object()
+
+	oldResInputs := resource.PropertyMap{}
+	if inputs, hasInputs := oldState["__inputs"]; hasInputs && inputs.IsObject() {
+		oldResInputs = inputs.ObjectValue()
+	}
+
+	inputsAndComputed, err := plugin.MarshalProperties(
+		checkpointObject(oldInputs, live, oldResInputs, initialAPIVersion), plugin.MarshalOptions{
+			Label:        fmt.Sprintf("%s.inputsAndComputed", label),
+			KeepUnknowns: true,
+			SkipNulls:    true,
+			KeepSecrets:  k.enableSecrets,
+		})
+	if err != nil {
+		return err
+	}
+
+	return partialError(fqObjName(live), awaitErr, inputsAndComputed, nil)
+}
+
 // Delete tears down an existing resource with the given ID.  If it fails, the resource is assumed
 // to still exist.
 func (k *kubeProvider) Delete(
@@ -1956,14 +2266,10 @@ func (k *kubeProvider) Delete(
 	label := fmt.Sprintf("%s.Delete(%s)", k.label(), urn)
 	logger.V(9).Infof("%s executing", label)
 
-	if k.clusterUnreachable {
-		return nil, fmt.Errorf("configured Kubernetes cluster is unreachable: %s\n"+
-			"If the cluster has been deleted, you can edit the pulumi state to remove this resource",
-			k.clusterUnreachableReason)
+	if isPatchResource(urn) {
+		return k.deletePatch(ctx, req)
 	}
 
-	// TODO(hausdorff): Propagate other options, like grace period through flags.
-
 	// Obtain new properties, create a Kubernetes `unstructured.Unstructured`.
 	oldState, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
 		Label: fmt.Sprintf("%s.olds", label), KeepUnknowns: true, SkipNulls: true, KeepSecrets: true,
@@ -1974,6 +2280,20 @@ func (k *kubeProvider) Delete(
 	_, current := parseCheckpointObject(oldState)
 	_, name := parseFqName(req.GetId())
 
+	// With the `retain` deletion policy, the resource is removed from the stack without touching the cluster, so
+	// this also succeeds if the cluster is unreachable.
+	if metadata.RetainOnDelete(current) {
+		_ = k.host.LogStatus(ctx, diag.Info, urn, fmt.Sprintf("retained %s in the cluster (%s: %s)",
+			fqObjName(current), metadata.AnnotationDeletionPolicy, metadata.DeletionPolicyRetain))
+		return &pbempty.Empty{}, nil
+	}
+
+	if k.clusterUnreachable {
+		return nil, fmt.Errorf("configured Kubernetes cluster is unreachable: %s\n"+
+			"If the cluster has been deleted, you can edit the pulumi state to remove this resource",
+			k.clusterUnreachableReason)
+	}
+
 	initialAPIVersion, err := initialAPIVersion(oldState, &unstructured.Unstructured{})
 	if err != nil {
 		return nil, err
@@ -2000,17 +2320,26 @@ func (k *kubeProvider) Delete(
 
 	config := await.DeleteConfig{
 		ProviderConfig: await.ProviderConfig{
-			Context:           k.canceler.context, // TODO: should this just be ctx from the args?
-			Host:              k.host,
-			URN:               urn,
-			InitialAPIVersion: initialAPIVersion,
-			ClientSet:         k.clientSet,
-			DedupLogger:       logging.NewLogger(k.canceler.context, k.host, urn),
-			Resources:         resources,
+			Context:            k.canceler.context, // TODO: should this just be ctx from the args?
+			Host:               k.host,
+			URN:                urn,
+			InitialAPIVersion:  initialAPIVersion,
+			ClientSet:          k.clientSet,
+			LogClient:          k.logClient,
+			DedupLogger:        logging.NewLogger(k.canceler.context, k.host, urn),
+			Resources:          resources,
+			RecordingDirectory: k.awaitRecordingDirectory,
+			StreamEvents:       k.streamEvents,
+			DefaultTimeouts:    k.defaultTimeouts,
+			ServerSideApply:    k.serverSideApply,
+			FieldManager:       k.fieldManager,
+			IgnoreFields:       k.ignoreFields,
+			Watches:            k.watches,
 		},
-		Inputs:  current,
-		Name:    name,
-		Timeout: req.Timeout,
+		Inputs:        current,
+		Name:          name,
+		Timeout:       req.Timeout,
+		DeleteOptions: k.deleteOptions,
 	}
 
 	awaitErr := await.Deletion(config)
@@ -2109,7 +2438,7 @@ func (k *kubeProvider) readLiveObject(obj *unstructured.Unstructured) (*unstruct
 }
 
 func (k *kubeProvider) serverSidePatch(
-	oldInputs, newInputs *unstructured.Unstructured,
+	oldInputs, newInputs *unstructured.Unstructured, preserve []metadata.FieldPath,
 ) ([]byte, *unstructured.Unstructured, error) {
 
 	client, err := k.clientSet.ResourceClient(oldInputs.GroupVersionKind(), oldInputs.GetNamespace())
@@ -2121,31 +2450,17 @@ func (k *kubeProvider) serverSidePatch(
 	if err != nil {
 		return nil, nil, err
 	}
-	liveInputs := parseLiveInputs(liveObject, oldInputs)
 
-	resources, err := k.getResources()
-	if err != nil {
-		return nil, nil, err
-	}
-	patch, patchType, _, err := openapi.PatchForResourceUpdate(resources, liveInputs, newInputs, liveObject)
-	if err != nil {
-		return nil, nil, err
-	}
+	// Ignored fields are left out of the applied object, or keep their live values, so changes to them are not
+	// part of the patch.
+	newInputs = k.withoutIgnoredChanges(newInputs, liveObject, preserve, k.serverSideApply)
 
-	// If the new resource does not exist, we need to dry-run a Create rather than a Patch.
 	var newObject *unstructured.Unstructured
-	_, err = client.Get(context.TODO(), newInputs.GetName(), metav1.GetOptions{})
-	switch {
-	case err == nil:
-		newObject, err = client.Patch(context.TODO(), newInputs.GetName(), patchType, patch, metav1.PatchOptions{
-			DryRun: []string{metav1.DryRunAll},
-		})
-	case errors.IsNotFound(err):
-		newObject, err = client.Create(context.TODO(), newInputs, metav1.CreateOptions{
-			DryRun: []string{metav1.DryRunAll},
-		})
-	default:
-		return nil, nil, err
+	if k.serverSideApply {
+		// Server-side apply creates the object if it does not exist, so no patch needs to be computed.
+		newObject, err = await.ServerSideApply(client, newInputs, k.fieldManager, true)
+	} else {
+		newObject, err = k.dryRunPatch(client, oldInputs, newInputs, liveObject)
 	}
 	if err != nil {
 		return nil, nil, err
@@ -2160,7 +2475,7 @@ func (k *kubeProvider) serverSidePatch(
 		return nil, nil, err
 	}
 
-	patch, err = jsonpatch.CreateMergePatch(liveJSON, newJSON)
+	patch, err := jsonpatch.CreateMergePatch(liveJSON, newJSON)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -2168,6 +2483,79 @@ func (k *kubeProvider) serverSidePatch(
 	return patch, liveObject, nil
 }
 
+// withoutIgnoredChanges returns a copy of the inputs in which the fields that are ignored with the
+// `pulumi.com/ignoreFields` annotation or the `ignoreFields` provider config, and the fields in `preserve`, are set to
+// their values in `source`, or are removed if `remove` is set (as for a server-side apply). The inputs are left
+// unchanged. Invalid ignore rules are reported by `Check`, and are skipped here.
+func (k *kubeProvider) withoutIgnoredChanges(
+	inputs, source *unstructured.Unstructured, preserve []metadata.FieldPath, remove bool,
+) *unstructured.Unstructured {
+	ignored, err := metadata.IgnoredFields(inputs, k.ignoreFields)
+	if err != nil {
+		ignored = nil
+	}
+	if ignored = append(ignored, preserve...); len(ignored) == 0 {
+		return inputs
+	}
+
+	inputs = &unstructured.Unstructured{Object: copyInputs(inputs.Object).(map[string]interface{})}
+	if remove {
+		metadata.RemoveIgnoredFields(inputs, source, ignored)
+	} else {
+		metadata.PreserveIgnoredFields(inputs, source, ignored)
+	}
+	return inputs
+}
+
+// checkApplyConflicts dry-runs a server-side apply of the inputs, and returns a check failure if other field managers
+// own some of the applied fields. Other errors are reported when the resource is created or updated.
+func (k *kubeProvider) checkApplyConflicts(inputs *unstructured.Unstructured) *pulumirpc.CheckFailure {
+	client, err := k.clientSet.ResourceClientForObject(inputs)
+	if err != nil {
+		return nil
+	}
+
+	_, err = await.ServerSideApply(client, inputs, k.fieldManager, true)
+	if conflictErr, isConflict := err.(*await.FieldManagerConflictError); isConflict {
+		return &pulumirpc.CheckFailure{Reason: conflictErr.Error()}
+	}
+	if err != nil {
+		logger.V(9).Infof("server-side apply dry run of %s failed: %v", fqObjName(inputs), err)
+	}
+	return nil
+}
+
+// dryRunPatch dry-runs a client-side patch from the live inputs to the new inputs.
+func (k *kubeProvider) dryRunPatch(
+	client dynamic.ResourceInterface, oldInputs, newInputs, liveObject *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	liveInputs := parseLiveInputs(liveObject, oldInputs)
+
+	resources, err := k.getResources()
+	if err != nil {
+		return nil, err
+	}
+	patch, patchType, _, err := openapi.PatchForResourceUpdate(resources, liveInputs, newInputs, liveObject)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the new resource does not exist, we need to dry-run a Create rather than a Patch.
+	_, err = client.Get(context.TODO(), newInputs.GetName(), metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return client.Patch(context.TODO(), newInputs.GetName(), patchType, patch, metav1.PatchOptions{
+			DryRun: []string{metav1.DryRunAll},
+		})
+	case errors.IsNotFound(err):
+		return client.Create(context.TODO(), newInputs, metav1.CreateOptions{
+			DryRun: []string{metav1.DryRunAll},
+		})
+	default:
+		return nil, err
+	}
+}
+
 // inputPatch calculates a patch on the client-side by comparing old inputs to the current inputs.
 func (k *kubeProvider) inputPatch(
 	oldInputs, newInputs *unstructured.Unstructured,
@@ -2267,6 +2655,15 @@ func initialAPIVersion(state resource.PropertyMap, oldConfig *unstructured.Unstr
 	return oldConfig.GetAPIVersion(), nil
 }
 
+// annotateLastAppliedConfig returns the inputs to submit to the API server. The last applied configuration is recorded
+// for client-side patches; with server-side apply, the API server tracks the fields applied by each field manager.
+func (k *kubeProvider) annotateLastAppliedConfig(config *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if k.serverSideApply {
+		return config, nil
+	}
+	return withLastAppliedConfig(config)
+}
+
 func withLastAppliedConfig(config *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 	// Serialize the inputs and add the last-applied-configuration annotation.
 	marshaled, err := config.MarshalJSON()
@@ -2433,17 +2830,91 @@ func parseLiveInputs(live, oldInputs *unstructured.Unstructured) *unstructured.U
 	return inputs
 }
 
-// convertPatchToDiff converts the given JSON merge patch to a Pulumi detailed diff.
+// crdForceNewProperties returns the properties of a custom resource that can't be changed because of a transition
+// rule in the schema of its CustomResourceDefinition. Returns nil if the resource is not a custom resource, or if
+// its CustomResourceDefinition can't be read from the cluster. Like the OpenAPI schema, the properties are cached
+// per GVK until `invalidateResources` is called; failed lookups are retried.
+func (k *kubeProvider) crdForceNewProperties(gvk schema.GroupVersionKind) properties {
+	if !isCustomResourceGroup(gvk.Group) || k.clusterUnreachable || k.yamlRenderMode {
+		return nil
+	}
+
+	k.resourcesMutex.RLock()
+	props, cached := k.crdForceNew[gvk]
+	k.resourcesMutex.RUnlock()
+	if cached {
+		return props
+	}
+
+	props, found := k.lookupCRDForceNewProperties(gvk)
+	if !found {
+		return nil
+	}
+
+	k.resourcesMutex.Lock()
+	defer k.resourcesMutex.Unlock()
+	if k.crdForceNew == nil {
+		k.crdForceNew = map[schema.GroupVersionKind]properties{}
+	}
+	k.crdForceNew[gvk] = props
+	return props
+}
+
+// lookupCRDForceNewProperties reads the CustomResourceDefinition of a custom resource from the cluster, and returns
+// its force-new properties. Returns false if the CustomResourceDefinition can't be read.
+func (k *kubeProvider) lookupCRDForceNewProperties(gvk schema.GroupVersionKind) (properties, bool) {
+	mapping, err := k.clientSet.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		logger.V(9).Infof("failed to look up the CustomResourceDefinition of %s: %v", gvk, err)
+		return nil, false
+	}
+	client, err := k.clientSet.ResourceClient(schema.GroupVersionKind{
+		Group:   "apiextensions.k8s.io",
+		Version: "v1",
+		Kind:    string(kinds.CustomResourceDefinition),
+	}, "")
+	if err != nil {
+		logger.V(9).Infof("failed to look up the CustomResourceDefinition of %s: %v", gvk, err)
+		return nil, false
+	}
+	crd, err := client.Get(context.TODO(), mapping.Resource.Resource+"."+gvk.Group, metav1.GetOptions{})
+	if err != nil {
+		logger.V(9).Infof("failed to look up the CustomResourceDefinition of %s: %v", gvk, err)
+		return nil, false
+	}
+	return crdForceNewProperties(crd.Object, gvk.Version), true
+}
+
+// mergeKeys returns a function that looks up the merge keys of the lists of the given GVK in the OpenAPI schema of the
+// cluster, or nil if the schema is not available.
+func (k *kubeProvider) mergeKeys(gvk schema.GroupVersionKind) mergeKeysFunc {
+	if k.clusterUnreachable || k.yamlRenderMode {
+		return nil
+	}
+	resources, err := k.getResources()
+	if err != nil {
+		logger.V(9).Infof("failed to fetch the OpenAPI schema for the diff of %s: %v", gvk, err)
+		return nil
+	}
+	return func(fields []string) []string {
+		return openapi.ListMergeKeys(resources, gvk, fields)
+	}
+}
+
+// convertPatchToDiff converts the given JSON merge patch to a Pulumi detailed diff. Changes to the forceNew properties
+// require a replacement. The elements of lists that have merge keys, as returned by mergeKeys, are identified by
+// their keys rather than by their indices; mergeKeys may be nil.
 func convertPatchToDiff(
-	patch, oldLiveState, newInputs, oldInputs map[string]interface{}, gvk schema.GroupVersionKind,
+	patch, oldLiveState, newInputs, oldInputs map[string]interface{}, forceNew properties, mergeKeys mergeKeysFunc,
 ) (map[string]*pulumirpc.PropertyDiff, error) {
 
 	contract.Require(len(patch) != 0, "len(patch) != 0")
 	contract.Require(oldLiveState != nil, "oldLiveState != nil")
 
 	pc := &patchConverter{
-		forceNew: forceNewProperties(gvk),
-		diff:     map[string]*pulumirpc.PropertyDiff{},
+		forceNew:  forceNew,
+		mergeKeys: mergeKeys,
+		diff:      map[string]*pulumirpc.PropertyDiff{},
 	}
 	err := pc.addPatchMapToDiff(nil, patch, oldLiveState, newInputs, oldInputs, false)
 	return pc.diff, err
@@ -2461,7 +2932,7 @@ func makePatchSlice(path []interface{}, v interface{}) interface{} {
 		return map[string]interface{}{
 			p: makePatchSlice(path[1:], v),
 		}
-	case int:
+	case int, listElement:
 		return []interface{}{makePatchSlice(path[1:], v)}
 	default:
 		contract.Failf("unexpected element type in path: %T", p)
@@ -2493,10 +2964,21 @@ func equalNumbers(a, b interface{}) bool {
 	return aOk && bOk && aVal == bVal
 }
 
+// mergeKeysFunc returns the names of the fields that identify the elements of the list at the given field path (see
+// openapi.ListMergeKeys), or nil if the elements of the list are identified by their indices.
+type mergeKeysFunc func(fields []string) []string
+
+// listElement is the element of a diff path that identifies an element of a list by its merge keys, e.g.,
+// `[name=app]` in `spec.template.spec.containers[name=app]`.
+type listElement struct {
+	key string
+}
+
 // patchConverter carries context for convertPatchToDiff.
 type patchConverter struct {
-	forceNew []string
-	diff     map[string]*pulumirpc.PropertyDiff
+	forceNew  []string
+	mergeKeys mergeKeysFunc
+	diff      map[string]*pulumirpc.PropertyDiff
 }
 
 // addPatchValueToDiff adds the given patched value to the detailed diff. Either the patched value or the old value
@@ -2595,6 +3077,8 @@ func (pc *patchConverter) addPatchValueToDiff(
 			}
 		case int:
 			pathStr = fmt.Sprintf("%s[%d]", pathStr, v)
+		case listElement:
+			pathStr = fmt.Sprintf("%s[%s]", pathStr, v.key)
 		}
 	}
 
@@ -2640,6 +3124,12 @@ func (pc *patchConverter) addPatchArrayToDiff(
 	path []interface{}, a, old, newInput, oldInput []interface{}, inArray bool,
 ) error {
 
+	if keys := pc.listMergeKeys(path); len(keys) != 0 {
+		if keyed, err := pc.addKeyedArrayToDiff(path, keys, a, old, newInput, oldInput); keyed || err != nil {
+			return err
+		}
+	}
+
 	at := func(arr []interface{}, i int) interface{} {
 		if i < len(arr) {
 			return arr[i]
@@ -2673,6 +3163,133 @@ func (pc *patchConverter) addPatchArrayToDiff(
 	return nil
 }
 
+// listMergeKeys returns the merge keys of the list at the given path, or nil if it has none.
+func (pc *patchConverter) listMergeKeys(path []interface{}) []string {
+	if pc.mergeKeys == nil {
+		return nil
+	}
+	var fields []string
+	for _, p := range path {
+		if field, isField := p.(string); isField {
+			fields = append(fields, field)
+		}
+	}
+	return pc.mergeKeys(fields)
+}
+
+// addKeyedArrayToDiff adds the diffs in the given patched array to the detailed diff, matching its elements to the old
+// elements by their merge keys, so that inserting or removing an element does not show as a change to every later
+// element. Returns false if the elements can't be matched by their keys (e.g., an element lacks a key, or two
+// elements have the same key), in which case nothing is added to the diff.
+func (pc *patchConverter) addKeyedArrayToDiff(
+	path []interface{}, keys []string, a, old, newInput, oldInput []interface{},
+) (bool, error) {
+
+	newKeys, ok := listElementKeys(a, keys)
+	if !ok {
+		return false, nil
+	}
+	oldKeys, ok := listElementKeys(old, keys)
+	if !ok {
+		return false, nil
+	}
+	oldIndices := make(map[string]int, len(oldKeys))
+	for i, key := range oldKeys {
+		oldIndices[key] = i
+	}
+	newIndices := make(map[string]int, len(newKeys))
+	for i, key := range newKeys {
+		newIndices[key] = i
+	}
+
+	for i, key := range newKeys {
+		var oldElem interface{}
+		if j, exists := oldIndices[key]; exists {
+			oldElem = old[j]
+		}
+		err := pc.addPatchValueToDiff(append(path, listElement{key: key}), a[i], oldElem,
+			findListElement(newInput, keys, a[i]), findListElement(oldInput, keys, oldElem), true)
+		if err != nil {
+			return true, err
+		}
+	}
+	for j, key := range oldKeys {
+		if _, exists := newIndices[key]; exists {
+			continue
+		}
+		err := pc.addPatchValueToDiff(append(path, listElement{key: key}), nil, old[j],
+			findListElement(newInput, keys, old[j]), findListElement(oldInput, keys, old[j]), true)
+		if err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// listElementKeys returns the keys of the elements of a list, e.g., `name=app`, or false if an element lacks one of
+// the key fields, or if two elements have the same key.
+func listElementKeys(list []interface{}, keys []string) ([]string, bool) {
+	elemKeys := make([]string, len(list))
+	seen := make(map[string]bool, len(list))
+	for i, elem := range list {
+		m, isMap := elem.(map[string]interface{})
+		if !isMap {
+			return nil, false
+		}
+		parts := make([]string, len(keys))
+		for j, key := range keys {
+			value, exists := m[key]
+			if !exists || value == nil {
+				return nil, false
+			}
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				return nil, false
+			}
+			parts[j] = fmt.Sprintf("%s=%v", key, value)
+		}
+		elemKeys[i] = strings.Join(parts, ",")
+		if seen[elemKeys[i]] {
+			return nil, false
+		}
+		seen[elemKeys[i]] = true
+	}
+	return elemKeys, true
+}
+
+// findListElement returns the element of the list whose key fields match those of elem, or nil if there is none. Key
+// fields that are not set in an element of the list (e.g., a `protocol` that is defaulted by the API server) match
+// any value.
+func findListElement(list []interface{}, keys []string, elem interface{}) interface{} {
+	elemMap, isMap := elem.(map[string]interface{})
+	if !isMap {
+		return nil
+	}
+	for _, candidate := range list {
+		candidateMap, isMap := candidate.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		matches, matchedAny := true, false
+		for _, key := range keys {
+			value, exists := candidateMap[key]
+			elemValue, elemExists := elemMap[key]
+			if !exists || !elemExists || value == nil || elemValue == nil {
+				continue
+			}
+			if !reflect.DeepEqual(value, elemValue) && !equalNumbers(value, elemValue) {
+				matches = false
+				break
+			}
+			matchedAny = true
+		}
+		if matches && matchedAny {
+			return candidate
+		}
+	}
+	return nil
+}
+
 // annotateSecrets copies the "secretness" from the ins to the outs. If there are values with the same keys for the
 // outs and the ins, if they are both objects, they are transformed recursively. Otherwise, if the value in the ins
 // contains a secret, the entire out value is marked as a secret.  This is very close to how we project secrets
@@ -17,6 +17,7 @@ package provider
 import (
 	"testing"
 
+	"github.com/pulumi/pulumi-kubernetes/provider/v2/pkg/metadata"
 	"github.com/pulumi/pulumi/sdk/v2/go/common/resource"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -119,3 +120,64 @@ func Test_equalNumbers(t *testing.T) {
 		})
 	}
 }
+
+func TestAnnotateLastAppliedConfig(t *testing.T) {
+	inputs := &unstructured.Unstructured{}
+	inputs.SetAPIVersion("v1")
+	inputs.SetKind("ConfigMap")
+	inputs.SetName("foo")
+
+	k := &kubeProvider{}
+	annotated, err := k.annotateLastAppliedConfig(inputs.DeepCopy())
+	assert.NoError(t, err)
+	assert.Contains(t, annotated.GetAnnotations(), lastAppliedConfigKey)
+
+	k.serverSideApply = true
+	annotated, err = k.annotateLastAppliedConfig(inputs.DeepCopy())
+	assert.NoError(t, err)
+	assert.Equal(t, inputs, annotated)
+}
+
+func TestWithoutIgnoredChanges(t *testing.T) {
+	inputs := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":        "web",
+				"annotations": map[string]interface{}{metadata.AnnotationIgnoreFields: "spec.paused"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": float64(3),
+				"paused":   true,
+				"template": resource.Computed{Element: resource.NewStringProperty("")},
+			},
+		}}
+	}
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": int64(5), "paused": false},
+	}}
+	preserve := []metadata.FieldPath{{"spec", "replicas"}}
+	k := &kubeProvider{}
+
+	// The inputs are copied, even if they have computed values, and are left unchanged.
+	news := inputs()
+	preserved := k.withoutIgnoredChanges(news, live, preserve, false)
+	assert.Equal(t, inputs(), news)
+	assert.Equal(t, map[string]interface{}{
+		"replicas": int64(5),
+		"paused":   false,
+		"template": resource.Computed{Element: resource.NewStringProperty("")},
+	}, preserved.Object["spec"])
+
+	// Server-side apply leaves the ignored fields to the managers that set them.
+	removed := k.withoutIgnoredChanges(news, live, preserve, true)
+	assert.Equal(t, inputs(), news)
+	assert.Equal(t, map[string]interface{}{
+		"template": resource.Computed{Element: resource.NewStringProperty("")},
+	}, removed.Object["spec"])
+
+	// Without ignored fields, the inputs are used as they are.
+	news.SetAnnotations(nil)
+	assert.Same(t, news, k.withoutIgnoredChanges(news, live, nil, true))
+}
@@ -44,6 +44,33 @@ func hasComputedValue(obj *unstructured.Unstructured) bool {
 	return false
 }
 
+// copyInputs returns a deep copy of the maps and lists of a value of the inputs. Other values, including computed
+// values, are shared with the original; unlike `runtime.DeepCopyJSONValue`, it doesn't panic on them.
+func copyInputs(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(value))
+		for key, elem := range value {
+			copied[key] = copyInputs(elem)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, elem := range value {
+			copied[i] = copyInputs(elem)
+		}
+		return copied
+	case []map[string]interface{}:
+		copied := make([]map[string]interface{}, len(value))
+		for i, elem := range value {
+			copied[i] = copyInputs(elem).(map[string]interface{})
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
 // --------------------------------------------------------------------------
 // Names and namespaces.
 // --------------------------------------------------------------------------
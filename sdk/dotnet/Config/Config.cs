@@ -18,6 +18,36 @@ namespace Pulumi.Kubernetes
         /// </summary>
         public static string? Context { get; set; } = __config.Get("context");
 
+        /// <summary>
+        /// A JSON object that overrides the default await timeouts, in seconds, for the kinds of resources it lists, e.g.,
+        /// `{"apps/v1/Deployment": 1200, "Job": 3600, "multiplier": 2}`. Keys are kinds, optionally qualified with their
+        /// apiVersion, which take precedence. The optional `multiplier` entry scales every default timeout. Timeouts specified for
+        /// a resource with `customTimeouts` or the `pulumi.com/timeoutSeconds` annotation are not affected.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `defaultTimeouts` parameter.
+        /// 2. The `PULUMI_K8S_DEFAULT_TIMEOUTS` environment variable.
+        /// </summary>
+        public static string? DefaultTimeouts { get; set; } = __config.Get("defaultTimeouts");
+
+        /// <summary>
+        /// If present, the grace period in seconds of the delete requests for resources. The default is the grace period of the resource, e.g., `terminationGracePeriodSeconds` for Pods. This can be overridden for a resource with the `pulumi.com/deleteGracePeriodSeconds` annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `deleteGracePeriodSeconds` parameter.
+        /// 2. The `PULUMI_K8S_DELETE_GRACE_PERIOD_SECONDS` environment variable.
+        /// </summary>
+        public static int? DeleteGracePeriodSeconds { get; set; } = __config.GetInt32("deleteGracePeriodSeconds");
+
+        /// <summary>
+        /// If present, the propagation policy of the delete requests for resources: `Foreground`, `Background` or `Orphan`. The default depends on the version of the cluster, and is `Background` for current versions. This can be overridden for a resource with the `pulumi.com/deletePropagationPolicy` annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `deletePropagationPolicy` parameter.
+        /// 2. The `PULUMI_K8S_DELETE_PROPAGATION_POLICY` environment variable.
+        /// </summary>
+        public static string? DeletePropagationPolicy { get; set; } = __config.Get("deletePropagationPolicy");
+
         /// <summary>
         /// BETA FEATURE - If present and set to true, enable server-side diff calculations.
         /// This feature is in developer preview, and is disabled by default.
@@ -28,6 +58,37 @@ namespace Pulumi.Kubernetes
         /// </summary>
         public static bool? EnableDryRun { get; set; } = __config.GetBoolean("enableDryRun");
 
+        /// <summary>
+        /// If present and set to true, create and update resources with server-side apply, rather than with patches computed by
+        /// the provider. Fields set by other controllers are left as they are, and the
+        /// `kubectl.kubernetes.io/last-applied-configuration` annotation is not written. Applies that set fields owned by other
+        /// field managers fail, unless the resource has the `pulumi.com/patchForce` annotation. Creating an object that
+        /// already exists also fails, unless it has the annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `enableServerSideApply` parameter.
+        /// 2. The `PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY` environment variable.
+        /// </summary>
+        public static bool? EnableServerSideApply { get; set; } = __config.GetBoolean("enableServerSideApply");
+
+        /// <summary>
+        /// The name of the field manager used for server-side apply. Defaults to `pulumi-kubernetes`.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `fieldManager` parameter.
+        /// 2. The `PULUMI_K8S_FIELD_MANAGER` environment variable.
+        /// </summary>
+        public static string? FieldManager { get; set; } = __config.Get("fieldManager");
+
+        /// <summary>
+        /// If present, the fields of resources that are managed by someone else, e.g., `spec.replicas` of Deployments that are scaled by a HorizontalPodAutoscaler. This is a JSON object that maps a GVK (e.g., `apps/v1/Deployment`) or a kind (e.g., `MutatingWebhookConfiguration`) to a list of field paths (e.g., `["webhooks[*].clientConfig.caBundle"]`). Changes to these fields are not shown in diffs, and updates preserve their live values, or leave them out of the applied object with server-side apply. Fields can also be ignored for a resource with the `pulumi.com/ignoreFields` annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `ignoreFields` parameter.
+        /// 2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.
+        /// </summary>
+        public static string? IgnoreFields { get; set; } = __config.Get("ignoreFields");
+
         /// <summary>
         /// The contents of a kubeconfig file or the path to a kubeconfig file. If this is set, this config will be used instead of $KUBECONFIG.
         /// </summary>
@@ -43,6 +104,16 @@ namespace Pulumi.Kubernetes
         /// </summary>
         public static string? Namespace { get; set; } = __config.Get("namespace");
 
+        /// <summary>
+        /// If present, the watch events received while awaiting resources are recorded as JSON files in this
+        /// directory, so that they can be replayed in tests of the await logic.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `recordAwaitEventsToDirectory` parameter.
+        /// 2. The `PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY` environment variable.
+        /// </summary>
+        public static string? RecordAwaitEventsToDirectory { get; set; } = __config.Get("recordAwaitEventsToDirectory");
+
         /// <summary>
         /// BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
         /// be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
@@ -55,6 +126,28 @@ namespace Pulumi.Kubernetes
         /// </summary>
         public static string? RenderYamlToDirectory { get; set; } = __config.Get("renderYamlToDirectory");
 
+        /// <summary>
+        /// If present and set to true, roll back Deployments whose update fails to become ready by re-applying
+        /// their previous configuration. The update is still reported as failed.
+        /// 
+        /// This config can be overridden for a resource with the `pulumi.com/rollbackOnFailure` annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `rollbackOnFailure` parameter.
+        /// 2. The `PULUMI_K8S_ROLLBACK_ON_FAILURE` environment variable.
+        /// </summary>
+        public static bool? RollbackOnFailure { get; set; } = __config.GetBoolean("rollbackOnFailure");
+
+        /// <summary>
+        /// If present and set to true, Warning Events reported for resources while they are awaited (and for the Pods and
+        /// ReplicaSets they own) are shown as status messages as they arrive, e.g., a Pod that cannot be scheduled.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `streamEvents` parameter.
+        /// 2. The `PULUMI_K8S_STREAM_EVENTS` environment variable.
+        /// </summary>
+        public static bool? StreamEvents { get; set; } = __config.GetBoolean("streamEvents");
+
         /// <summary>
         /// If present and set to true, suppress apiVersion deprecation warnings from the CLI.
         /// 
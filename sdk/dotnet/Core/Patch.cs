@@ -0,0 +1,147 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+using System;
+using System.Collections.Generic;
+using System.Collections.Immutable;
+using System.Threading.Tasks;
+using Pulumi.Serialization;
+
+namespace Pulumi.Kubernetes.Core
+{
+    /// <summary>
+    /// Patch applies some of the fields of an existing object, e.g., a label on the `kube-system` Namespace, or an annotation on the Pod template of a DaemonSet that is managed by someone else. The fields are applied with server-side apply, so that fields set by other field managers are left as they are. Deleting the Patch removes its fields from the object, unless `retainOnDelete` is set. Atomic lists, e.g., `tolerations`, are replaced as a whole, so a Patch that sets one must set all of its items.
+    /// </summary>
+    public partial class Patch : KubernetesResource
+    {
+        /// <summary>
+        /// The apiVersion of the object to patch, e.g., `apps/v1`.
+        /// </summary>
+        [Output("apiVersion")]
+        public Output<string> ApiVersion { get; private set; } = null!;
+
+        /// <summary>
+        /// The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+        /// </summary>
+        [Output("fields")]
+        public Output<ImmutableDictionary<string, object>> Fields { get; private set; } = null!;
+
+        /// <summary>
+        /// If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+        /// </summary>
+        [Output("force")]
+        public Output<bool?> Force { get; private set; } = null!;
+
+        /// <summary>
+        /// The kind of the object to patch, e.g., `DaemonSet`.
+        /// </summary>
+        [Output("kind")]
+        public Output<string> Kind { get; private set; } = null!;
+
+        /// <summary>
+        /// The name and namespace of the object to patch, and the labels and annotations to set on it.
+        /// </summary>
+        [Output("metadata")]
+        public Output<Pulumi.Kubernetes.Types.Outputs.Meta.V1.ObjectMeta> Metadata { get; private set; } = null!;
+
+        /// <summary>
+        /// If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+        /// </summary>
+        [Output("retainOnDelete")]
+        public Output<bool?> RetainOnDelete { get; private set; } = null!;
+
+
+        /// <summary>
+        /// Create a Patch resource with the given unique name, arguments, and options.
+        /// </summary>
+        ///
+        /// <param name="name">The unique name of the resource</param>
+        /// <param name="args">The arguments used to populate this resource's properties</param>
+        /// <param name="options">A bag of options that control this resource's behavior</param>
+        public Patch(string name, Pulumi.Kubernetes.Types.Inputs.Core.PatchArgs args, CustomResourceOptions? options = null)
+            : base("kubernetes:core:Patch", name, args, MakeResourceOptions(options, ""))
+        {
+        }
+
+        private Patch(string name, Input<string> id, CustomResourceOptions? options = null)
+            : base("kubernetes:core:Patch", name, null, MakeResourceOptions(options, id))
+        {
+        }
+
+        private static CustomResourceOptions MakeResourceOptions(CustomResourceOptions? options, Input<string>? id)
+        {
+            var defaultOptions = new CustomResourceOptions
+            {
+                Version = Utilities.Version,
+            };
+            var merged = CustomResourceOptions.Merge(defaultOptions, options);
+            // Override the ID if one was specified for consistency with other language SDKs.
+            merged.Id = id ?? merged.Id;
+            return merged;
+        }
+        /// <summary>
+        /// Get an existing Patch resource's state with the given name, ID, and optional extra
+        /// properties used to qualify the lookup.
+        /// </summary>
+        ///
+        /// <param name="name">The unique name of the resulting resource.</param>
+        /// <param name="id">The unique provider ID of the resource to lookup.</param>
+        /// <param name="options">A bag of options that control this resource's behavior</param>
+        public static Patch Get(string name, Input<string> id, CustomResourceOptions? options = null)
+        {
+            return new Patch(name, id, options);
+        }
+    }
+}
+namespace Pulumi.Kubernetes.Types.Inputs.Core
+{
+
+    public class PatchArgs : Pulumi.ResourceArgs
+    {
+        /// <summary>
+        /// The apiVersion of the object to patch, e.g., `apps/v1`.
+        /// </summary>
+        [Input("apiVersion", required: true)]
+        public Input<string> ApiVersion { get; set; } = null!;
+
+        [Input("fields")]
+        private InputMap<object>? _fields;
+
+        /// <summary>
+        /// The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+        /// </summary>
+        public InputMap<object> Fields
+        {
+            get => _fields ?? (_fields = new InputMap<object>());
+            set => _fields = value;
+        }
+
+        /// <summary>
+        /// If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+        /// </summary>
+        [Input("force")]
+        public Input<bool>? Force { get; set; }
+
+        /// <summary>
+        /// The kind of the object to patch, e.g., `DaemonSet`.
+        /// </summary>
+        [Input("kind", required: true)]
+        public Input<string> Kind { get; set; } = null!;
+
+        /// <summary>
+        /// The name and namespace of the object to patch, and the labels and annotations to set on it.
+        /// </summary>
+        [Input("metadata", required: true)]
+        public Input<Pulumi.Kubernetes.Types.Inputs.Meta.V1.ObjectMetaArgs> Metadata { get; set; } = null!;
+
+        /// <summary>
+        /// If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+        /// </summary>
+        [Input("retainOnDelete")]
+        public Input<bool>? RetainOnDelete { get; set; }
+
+        public PatchArgs()
+        {
+        }
+    }
+}
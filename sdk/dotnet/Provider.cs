@@ -57,6 +57,39 @@ namespace Pulumi.Kubernetes
         [Input("context")]
         public Input<string>? Context { get; set; }
 
+        /// <summary>
+        /// A JSON object that overrides the default await timeouts, in seconds, for the kinds of resources it lists, e.g.,
+        /// `{"apps/v1/Deployment": 1200, "Job": 3600, "multiplier": 2}`. Keys are kinds, optionally qualified with their
+        /// apiVersion, which take precedence. The optional `multiplier` entry scales every default timeout. Timeouts specified for
+        /// a resource with `customTimeouts` or the `pulumi.com/timeoutSeconds` annotation are not affected.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `defaultTimeouts` parameter.
+        /// 2. The `PULUMI_K8S_DEFAULT_TIMEOUTS` environment variable.
+        /// </summary>
+        [Input("defaultTimeouts")]
+        public Input<string>? DefaultTimeouts { get; set; }
+
+        /// <summary>
+        /// If present, the grace period in seconds of the delete requests for resources. The default is the grace period of the resource, e.g., `terminationGracePeriodSeconds` for Pods. This can be overridden for a resource with the `pulumi.com/deleteGracePeriodSeconds` annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `deleteGracePeriodSeconds` parameter.
+        /// 2. The `PULUMI_K8S_DELETE_GRACE_PERIOD_SECONDS` environment variable.
+        /// </summary>
+        [Input("deleteGracePeriodSeconds", json: true)]
+        public Input<int>? DeleteGracePeriodSeconds { get; set; }
+
+        /// <summary>
+        /// If present, the propagation policy of the delete requests for resources: `Foreground`, `Background` or `Orphan`. The default depends on the version of the cluster, and is `Background` for current versions. This can be overridden for a resource with the `pulumi.com/deletePropagationPolicy` annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `deletePropagationPolicy` parameter.
+        /// 2. The `PULUMI_K8S_DELETE_PROPAGATION_POLICY` environment variable.
+        /// </summary>
+        [Input("deletePropagationPolicy")]
+        public Input<string>? DeletePropagationPolicy { get; set; }
+
         /// <summary>
         /// BETA FEATURE - If present and set to true, enable server-side diff calculations.
         /// This feature is in developer preview, and is disabled by default.
@@ -68,6 +101,40 @@ namespace Pulumi.Kubernetes
         [Input("enableDryRun", json: true)]
         public Input<bool>? EnableDryRun { get; set; }
 
+        /// <summary>
+        /// If present and set to true, create and update resources with server-side apply, rather than with patches computed by
+        /// the provider. Fields set by other controllers are left as they are, and the
+        /// `kubectl.kubernetes.io/last-applied-configuration` annotation is not written. Applies that set fields owned by other
+        /// field managers fail, unless the resource has the `pulumi.com/patchForce` annotation. Creating an object that
+        /// already exists also fails, unless it has the annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `enableServerSideApply` parameter.
+        /// 2. The `PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY` environment variable.
+        /// </summary>
+        [Input("enableServerSideApply", json: true)]
+        public Input<bool>? EnableServerSideApply { get; set; }
+
+        /// <summary>
+        /// The name of the field manager used for server-side apply. Defaults to `pulumi-kubernetes`.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `fieldManager` parameter.
+        /// 2. The `PULUMI_K8S_FIELD_MANAGER` environment variable.
+        /// </summary>
+        [Input("fieldManager")]
+        public Input<string>? FieldManager { get; set; }
+
+        /// <summary>
+        /// If present, the fields of resources that are managed by someone else, e.g., `spec.replicas` of Deployments that are scaled by a HorizontalPodAutoscaler. This is a JSON object that maps a GVK (e.g., `apps/v1/Deployment`) or a kind (e.g., `MutatingWebhookConfiguration`) to a list of field paths (e.g., `["webhooks[*].clientConfig.caBundle"]`). Changes to these fields are not shown in diffs, and updates preserve their live values, or leave them out of the applied object with server-side apply. Fields can also be ignored for a resource with the `pulumi.com/ignoreFields` annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `ignoreFields` parameter.
+        /// 2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.
+        /// </summary>
+        [Input("ignoreFields")]
+        public Input<string>? IgnoreFields { get; set; }
+
         /// <summary>
         /// The contents of a kubeconfig file or the path to a kubeconfig file. If this is set, this config will be used instead of $KUBECONFIG.
         /// </summary>
@@ -85,6 +152,17 @@ namespace Pulumi.Kubernetes
         [Input("namespace")]
         public Input<string>? Namespace { get; set; }
 
+        /// <summary>
+        /// If present, the watch events received while awaiting resources are recorded as JSON files in this
+        /// directory, so that they can be replayed in tests of the await logic.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `recordAwaitEventsToDirectory` parameter.
+        /// 2. The `PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY` environment variable.
+        /// </summary>
+        [Input("recordAwaitEventsToDirectory")]
+        public Input<string>? RecordAwaitEventsToDirectory { get; set; }
+
         /// <summary>
         /// BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
         /// be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
@@ -98,6 +176,30 @@ namespace Pulumi.Kubernetes
         [Input("renderYamlToDirectory")]
         public Input<string>? RenderYamlToDirectory { get; set; }
 
+        /// <summary>
+        /// If present and set to true, roll back Deployments whose update fails to become ready by re-applying
+        /// their previous configuration. The update is still reported as failed.
+        /// 
+        /// This config can be overridden for a resource with the `pulumi.com/rollbackOnFailure` annotation.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `rollbackOnFailure` parameter.
+        /// 2. The `PULUMI_K8S_ROLLBACK_ON_FAILURE` environment variable.
+        /// </summary>
+        [Input("rollbackOnFailure", json: true)]
+        public Input<bool>? RollbackOnFailure { get; set; }
+
+        /// <summary>
+        /// If present and set to true, Warning Events reported for resources while they are awaited (and for the Pods and
+        /// ReplicaSets they own) are shown as status messages as they arrive, e.g., a Pod that cannot be scheduled.
+        /// 
+        /// This config can be specified in the following ways, using this precedence:
+        /// 1. This `streamEvents` parameter.
+        /// 2. The `PULUMI_K8S_STREAM_EVENTS` environment variable.
+        /// </summary>
+        [Input("streamEvents", json: true)]
+        public Input<bool>? StreamEvents { get; set; }
+
         /// <summary>
         /// If present and set to true, suppress apiVersion deprecation warnings from the CLI.
         /// 
@@ -110,8 +212,16 @@ namespace Pulumi.Kubernetes
 
         public ProviderArgs()
         {
+            DefaultTimeouts = Utilities.GetEnv("PULUMI_K8S_DEFAULT_TIMEOUTS");
+            DeletePropagationPolicy = Utilities.GetEnv("PULUMI_K8S_DELETE_PROPAGATION_POLICY");
             EnableDryRun = Utilities.GetEnvBoolean("PULUMI_K8S_ENABLE_DRY_RUN");
+            EnableServerSideApply = Utilities.GetEnvBoolean("PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY");
+            FieldManager = Utilities.GetEnv("PULUMI_K8S_FIELD_MANAGER");
+            IgnoreFields = Utilities.GetEnv("PULUMI_K8S_IGNORE_FIELDS");
             KubeConfig = Utilities.GetEnv("KUBECONFIG");
+            RecordAwaitEventsToDirectory = Utilities.GetEnv("PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY");
+            RollbackOnFailure = Utilities.GetEnvBoolean("PULUMI_K8S_ROLLBACK_ON_FAILURE");
+            StreamEvents = Utilities.GetEnvBoolean("PULUMI_K8S_STREAM_EVENTS");
             SuppressDeprecationWarnings = Utilities.GetEnvBoolean("PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS");
         }
     }
@@ -0,0 +1,176 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package core
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	metav1 "github.com/pulumi/pulumi-kubernetes/sdk/v2/go/kubernetes/meta/v1"
+	"github.com/pulumi/pulumi/sdk/v2/go/pulumi"
+)
+
+// Patch applies some of the fields of an existing object, e.g., a label on the `kube-system` Namespace, or an annotation on the Pod template of a DaemonSet that is managed by someone else. The fields are applied with server-side apply, so that fields set by other field managers are left as they are. Deleting the Patch removes its fields from the object, unless `retainOnDelete` is set. Atomic lists, e.g., `tolerations`, are replaced as a whole, so a Patch that sets one must set all of its items.
+type Patch struct {
+	pulumi.CustomResourceState
+
+	// The apiVersion of the object to patch, e.g., `apps/v1`.
+	ApiVersion pulumi.StringOutput `pulumi:"apiVersion"`
+	// The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+	Fields pulumi.MapOutput `pulumi:"fields"`
+	// If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+	Force pulumi.BoolPtrOutput `pulumi:"force"`
+	// The kind of the object to patch, e.g., `DaemonSet`.
+	Kind pulumi.StringOutput `pulumi:"kind"`
+	// The name and namespace of the object to patch, and the labels and annotations to set on it.
+	Metadata metav1.ObjectMetaOutput `pulumi:"metadata"`
+	// If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+	RetainOnDelete pulumi.BoolPtrOutput `pulumi:"retainOnDelete"`
+}
+
+// NewPatch registers a new resource with the given unique name, arguments, and options.
+func NewPatch(ctx *pulumi.Context,
+	name string, args *PatchArgs, opts ...pulumi.ResourceOption) (*Patch, error) {
+	if args == nil {
+		return nil, errors.New("missing one or more required arguments")
+	}
+
+	if args.ApiVersion == nil {
+		return nil, errors.New("invalid value for required argument 'ApiVersion'")
+	}
+	if args.Kind == nil {
+		return nil, errors.New("invalid value for required argument 'Kind'")
+	}
+	if args.Metadata == nil {
+		return nil, errors.New("invalid value for required argument 'Metadata'")
+	}
+	var resource Patch
+	err := ctx.RegisterResource("kubernetes:core:Patch", name, args, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// GetPatch gets an existing Patch resource's state with the given name, ID, and optional
+// state properties that are used to uniquely qualify the lookup (nil if not required).
+func GetPatch(ctx *pulumi.Context,
+	name string, id pulumi.IDInput, state *PatchState, opts ...pulumi.ResourceOption) (*Patch, error) {
+	var resource Patch
+	err := ctx.ReadResource("kubernetes:core:Patch", name, id, state, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// Input properties used for looking up and filtering Patch resources.
+type patchState struct {
+	// The apiVersion of the object to patch, e.g., `apps/v1`.
+	ApiVersion *string `pulumi:"apiVersion"`
+	// The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+	Fields map[string]interface{} `pulumi:"fields"`
+	// If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+	Force *bool `pulumi:"force"`
+	// The kind of the object to patch, e.g., `DaemonSet`.
+	Kind *string `pulumi:"kind"`
+	// The name and namespace of the object to patch, and the labels and annotations to set on it.
+	Metadata *metav1.ObjectMeta `pulumi:"metadata"`
+	// If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+	RetainOnDelete *bool `pulumi:"retainOnDelete"`
+}
+
+type PatchState struct {
+	// The apiVersion of the object to patch, e.g., `apps/v1`.
+	ApiVersion pulumi.StringPtrInput
+	// The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+	Fields pulumi.MapInput
+	// If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+	Force pulumi.BoolPtrInput
+	// The kind of the object to patch, e.g., `DaemonSet`.
+	Kind pulumi.StringPtrInput
+	// The name and namespace of the object to patch, and the labels and annotations to set on it.
+	Metadata metav1.ObjectMetaPtrInput
+	// If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+	RetainOnDelete pulumi.BoolPtrInput
+}
+
+func (PatchState) ElementType() reflect.Type {
+	return reflect.TypeOf((*patchState)(nil)).Elem()
+}
+
+type patchArgs struct {
+	// The apiVersion of the object to patch, e.g., `apps/v1`.
+	ApiVersion string `pulumi:"apiVersion"`
+	// The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+	Fields map[string]interface{} `pulumi:"fields"`
+	// If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+	Force *bool `pulumi:"force"`
+	// The kind of the object to patch, e.g., `DaemonSet`.
+	Kind string `pulumi:"kind"`
+	// The name and namespace of the object to patch, and the labels and annotations to set on it.
+	Metadata metav1.ObjectMeta `pulumi:"metadata"`
+	// If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+	RetainOnDelete *bool `pulumi:"retainOnDelete"`
+}
+
+// The set of arguments for constructing a Patch resource.
+type PatchArgs struct {
+	// The apiVersion of the object to patch, e.g., `apps/v1`.
+	ApiVersion pulumi.StringInput
+	// The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+	Fields pulumi.MapInput
+	// If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+	Force pulumi.BoolPtrInput
+	// The kind of the object to patch, e.g., `DaemonSet`.
+	Kind pulumi.StringInput
+	// The name and namespace of the object to patch, and the labels and annotations to set on it.
+	Metadata metav1.ObjectMetaInput
+	// If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+	RetainOnDelete pulumi.BoolPtrInput
+}
+
+func (PatchArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*patchArgs)(nil)).Elem()
+}
+
+type PatchInput interface {
+	pulumi.Input
+
+	ToPatchOutput() PatchOutput
+	ToPatchOutputWithContext(ctx context.Context) PatchOutput
+}
+
+func (Patch) ElementType() reflect.Type {
+	return reflect.TypeOf((*Patch)(nil)).Elem()
+}
+
+func (i Patch) ToPatchOutput() PatchOutput {
+	return i.ToPatchOutputWithContext(context.Background())
+}
+
+func (i Patch) ToPatchOutputWithContext(ctx context.Context) PatchOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PatchOutput)
+}
+
+type PatchOutput struct {
+	*pulumi.OutputState
+}
+
+func (PatchOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*PatchOutput)(nil)).Elem()
+}
+
+func (o PatchOutput) ToPatchOutput() PatchOutput {
+	return o
+}
+
+func (o PatchOutput) ToPatchOutputWithContext(ctx context.Context) PatchOutput {
+	return o
+}
+
+func init() {
+	pulumi.RegisterOutputType(PatchOutput{})
+}
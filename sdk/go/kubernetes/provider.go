@@ -22,12 +22,36 @@ func NewProvider(ctx *pulumi.Context,
 		args = &ProviderArgs{}
 	}
 
+	if args.DefaultTimeouts == nil {
+		args.DefaultTimeouts = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_DEFAULT_TIMEOUTS").(string))
+	}
+	if args.DeletePropagationPolicy == nil {
+		args.DeletePropagationPolicy = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_DELETE_PROPAGATION_POLICY").(string))
+	}
 	if args.EnableDryRun == nil {
 		args.EnableDryRun = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "PULUMI_K8S_ENABLE_DRY_RUN").(bool))
 	}
+	if args.EnableServerSideApply == nil {
+		args.EnableServerSideApply = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY").(bool))
+	}
+	if args.FieldManager == nil {
+		args.FieldManager = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_FIELD_MANAGER").(string))
+	}
+	if args.IgnoreFields == nil {
+		args.IgnoreFields = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_IGNORE_FIELDS").(string))
+	}
 	if args.Kubeconfig == nil {
 		args.Kubeconfig = pulumi.StringPtr(getEnvOrDefault("", nil, "KUBECONFIG").(string))
 	}
+	if args.RecordAwaitEventsToDirectory == nil {
+		args.RecordAwaitEventsToDirectory = pulumi.StringPtr(getEnvOrDefault("", nil, "PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY").(string))
+	}
+	if args.RollbackOnFailure == nil {
+		args.RollbackOnFailure = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "PULUMI_K8S_ROLLBACK_ON_FAILURE").(bool))
+	}
+	if args.StreamEvents == nil {
+		args.StreamEvents = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "PULUMI_K8S_STREAM_EVENTS").(bool))
+	}
 	if args.SuppressDeprecationWarnings == nil {
 		args.SuppressDeprecationWarnings = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS").(bool))
 	}
@@ -44,6 +68,27 @@ type providerArgs struct {
 	Cluster *string `pulumi:"cluster"`
 	// If present, the name of the kubeconfig context to use.
 	Context *string `pulumi:"context"`
+	// A JSON object that overrides the default await timeouts, in seconds, for the kinds of resources it lists, e.g.,
+	// `{"apps/v1/Deployment": 1200, "Job": 3600, "multiplier": 2}`. Keys are kinds, optionally qualified with their
+	// apiVersion, which take precedence. The optional `multiplier` entry scales every default timeout. Timeouts specified for
+	// a resource with `customTimeouts` or the `pulumi.com/timeoutSeconds` annotation are not affected.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `defaultTimeouts` parameter.
+	// 2. The `PULUMI_K8S_DEFAULT_TIMEOUTS` environment variable.
+	DefaultTimeouts *string `pulumi:"defaultTimeouts"`
+	// If present, the grace period in seconds of the delete requests for resources. The default is the grace period of the resource, e.g., `terminationGracePeriodSeconds` for Pods. This can be overridden for a resource with the `pulumi.com/deleteGracePeriodSeconds` annotation.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `deleteGracePeriodSeconds` parameter.
+	// 2. The `PULUMI_K8S_DELETE_GRACE_PERIOD_SECONDS` environment variable.
+	DeleteGracePeriodSeconds *int `pulumi:"deleteGracePeriodSeconds"`
+	// If present, the propagation policy of the delete requests for resources: `Foreground`, `Background` or `Orphan`. The default depends on the version of the cluster, and is `Background` for current versions. This can be overridden for a resource with the `pulumi.com/deletePropagationPolicy` annotation.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `deletePropagationPolicy` parameter.
+	// 2. The `PULUMI_K8S_DELETE_PROPAGATION_POLICY` environment variable.
+	DeletePropagationPolicy *string `pulumi:"deletePropagationPolicy"`
 	// BETA FEATURE - If present and set to true, enable server-side diff calculations.
 	// This feature is in developer preview, and is disabled by default.
 	//
@@ -51,6 +96,28 @@ type providerArgs struct {
 	// 1. This `enableDryRun` parameter.
 	// 2. The `PULUMI_K8S_ENABLE_DRY_RUN` environment variable.
 	EnableDryRun *bool `pulumi:"enableDryRun"`
+	// If present and set to true, create and update resources with server-side apply, rather than with patches computed by
+	// the provider. Fields set by other controllers are left as they are, and the
+	// `kubectl.kubernetes.io/last-applied-configuration` annotation is not written. Applies that set fields owned by other
+	// field managers fail, unless the resource has the `pulumi.com/patchForce` annotation. Creating an object that
+	// already exists also fails, unless it has the annotation.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `enableServerSideApply` parameter.
+	// 2. The `PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY` environment variable.
+	EnableServerSideApply *bool `pulumi:"enableServerSideApply"`
+	// The name of the field manager used for server-side apply. Defaults to `pulumi-kubernetes`.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `fieldManager` parameter.
+	// 2. The `PULUMI_K8S_FIELD_MANAGER` environment variable.
+	FieldManager *string `pulumi:"fieldManager"`
+	// If present, the fields of resources that are managed by someone else, e.g., `spec.replicas` of Deployments that are scaled by a HorizontalPodAutoscaler. This is a JSON object that maps a GVK (e.g., `apps/v1/Deployment`) or a kind (e.g., `MutatingWebhookConfiguration`) to a list of field paths (e.g., `["webhooks[*].clientConfig.caBundle"]`). Changes to these fields are not shown in diffs, and updates preserve their live values, or leave them out of the applied object with server-side apply. Fields can also be ignored for a resource with the `pulumi.com/ignoreFields` annotation.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `ignoreFields` parameter.
+	// 2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.
+	IgnoreFields *string `pulumi:"ignoreFields"`
 	// The contents of a kubeconfig file or the path to a kubeconfig file. If this is set, this config will be used instead of $KUBECONFIG.
 	Kubeconfig *string `pulumi:"kubeconfig"`
 	// If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
@@ -60,6 +127,13 @@ type providerArgs struct {
 	// 2. This `namespace` parameter.
 	// 3. `namespace` set for the active context in the kubeconfig.
 	Namespace *string `pulumi:"namespace"`
+	// If present, the watch events received while awaiting resources are recorded as JSON files in this
+	// directory, so that they can be replayed in tests of the await logic.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `recordAwaitEventsToDirectory` parameter.
+	// 2. The `PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY` environment variable.
+	RecordAwaitEventsToDirectory *string `pulumi:"recordAwaitEventsToDirectory"`
 	// BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
 	// be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
 	// to the Pulumi program. This feature is in developer preview, and is disabled by default.
@@ -69,6 +143,22 @@ type providerArgs struct {
 	// and may result in an error if they are referenced by other resources. Also note that any secret values
 	// used in these resources will be rendered in plaintext to the resulting YAML.
 	RenderYamlToDirectory *string `pulumi:"renderYamlToDirectory"`
+	// If present and set to true, roll back Deployments whose update fails to become ready by re-applying
+	// their previous configuration. The update is still reported as failed.
+	//
+	// This config can be overridden for a resource with the `pulumi.com/rollbackOnFailure` annotation.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `rollbackOnFailure` parameter.
+	// 2. The `PULUMI_K8S_ROLLBACK_ON_FAILURE` environment variable.
+	RollbackOnFailure *bool `pulumi:"rollbackOnFailure"`
+	// If present and set to true, Warning Events reported for resources while they are awaited (and for the Pods and
+	// ReplicaSets they own) are shown as status messages as they arrive, e.g., a Pod that cannot be scheduled.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `streamEvents` parameter.
+	// 2. The `PULUMI_K8S_STREAM_EVENTS` environment variable.
+	StreamEvents *bool `pulumi:"streamEvents"`
 	// If present and set to true, suppress apiVersion deprecation warnings from the CLI.
 	//
 	// This config can be specified in the following ways, using this precedence:
@@ -83,6 +173,27 @@ type ProviderArgs struct {
 	Cluster pulumi.StringPtrInput
 	// If present, the name of the kubeconfig context to use.
 	Context pulumi.StringPtrInput
+	// A JSON object that overrides the default await timeouts, in seconds, for the kinds of resources it lists, e.g.,
+	// `{"apps/v1/Deployment": 1200, "Job": 3600, "multiplier": 2}`. Keys are kinds, optionally qualified with their
+	// apiVersion, which take precedence. The optional `multiplier` entry scales every default timeout. Timeouts specified for
+	// a resource with `customTimeouts` or the `pulumi.com/timeoutSeconds` annotation are not affected.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `defaultTimeouts` parameter.
+	// 2. The `PULUMI_K8S_DEFAULT_TIMEOUTS` environment variable.
+	DefaultTimeouts pulumi.StringPtrInput
+	// If present, the grace period in seconds of the delete requests for resources. The default is the grace period of the resource, e.g., `terminationGracePeriodSeconds` for Pods. This can be overridden for a resource with the `pulumi.com/deleteGracePeriodSeconds` annotation.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `deleteGracePeriodSeconds` parameter.
+	// 2. The `PULUMI_K8S_DELETE_GRACE_PERIOD_SECONDS` environment variable.
+	DeleteGracePeriodSeconds pulumi.IntPtrInput
+	// If present, the propagation policy of the delete requests for resources: `Foreground`, `Background` or `Orphan`. The default depends on the version of the cluster, and is `Background` for current versions. This can be overridden for a resource with the `pulumi.com/deletePropagationPolicy` annotation.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `deletePropagationPolicy` parameter.
+	// 2. The `PULUMI_K8S_DELETE_PROPAGATION_POLICY` environment variable.
+	DeletePropagationPolicy pulumi.StringPtrInput
 	// BETA FEATURE - If present and set to true, enable server-side diff calculations.
 	// This feature is in developer preview, and is disabled by default.
 	//
@@ -90,6 +201,28 @@ type ProviderArgs struct {
 	// 1. This `enableDryRun` parameter.
 	// 2. The `PULUMI_K8S_ENABLE_DRY_RUN` environment variable.
 	EnableDryRun pulumi.BoolPtrInput
+	// If present and set to true, create and update resources with server-side apply, rather than with patches computed by
+	// the provider. Fields set by other controllers are left as they are, and the
+	// `kubectl.kubernetes.io/last-applied-configuration` annotation is not written. Applies that set fields owned by other
+	// field managers fail, unless the resource has the `pulumi.com/patchForce` annotation. Creating an object that
+	// already exists also fails, unless it has the annotation.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `enableServerSideApply` parameter.
+	// 2. The `PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY` environment variable.
+	EnableServerSideApply pulumi.BoolPtrInput
+	// The name of the field manager used for server-side apply. Defaults to `pulumi-kubernetes`.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `fieldManager` parameter.
+	// 2. The `PULUMI_K8S_FIELD_MANAGER` environment variable.
+	FieldManager pulumi.StringPtrInput
+	// If present, the fields of resources that are managed by someone else, e.g., `spec.replicas` of Deployments that are scaled by a HorizontalPodAutoscaler. This is a JSON object that maps a GVK (e.g., `apps/v1/Deployment`) or a kind (e.g., `MutatingWebhookConfiguration`) to a list of field paths (e.g., `["webhooks[*].clientConfig.caBundle"]`). Changes to these fields are not shown in diffs, and updates preserve their live values, or leave them out of the applied object with server-side apply. Fields can also be ignored for a resource with the `pulumi.com/ignoreFields` annotation.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `ignoreFields` parameter.
+	// 2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.
+	IgnoreFields pulumi.StringPtrInput
 	// The contents of a kubeconfig file or the path to a kubeconfig file. If this is set, this config will be used instead of $KUBECONFIG.
 	Kubeconfig pulumi.StringPtrInput
 	// If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
@@ -99,6 +232,13 @@ type ProviderArgs struct {
 	// 2. This `namespace` parameter.
 	// 3. `namespace` set for the active context in the kubeconfig.
 	Namespace pulumi.StringPtrInput
+	// If present, the watch events received while awaiting resources are recorded as JSON files in this
+	// directory, so that they can be replayed in tests of the await logic.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `recordAwaitEventsToDirectory` parameter.
+	// 2. The `PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY` environment variable.
+	RecordAwaitEventsToDirectory pulumi.StringPtrInput
 	// BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
 	// be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
 	// to the Pulumi program. This feature is in developer preview, and is disabled by default.
@@ -108,6 +248,22 @@ type ProviderArgs struct {
 	// and may result in an error if they are referenced by other resources. Also note that any secret values
 	// used in these resources will be rendered in plaintext to the resulting YAML.
 	RenderYamlToDirectory pulumi.StringPtrInput
+	// If present and set to true, roll back Deployments whose update fails to become ready by re-applying
+	// their previous configuration. The update is still reported as failed.
+	//
+	// This config can be overridden for a resource with the `pulumi.com/rollbackOnFailure` annotation.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `rollbackOnFailure` parameter.
+	// 2. The `PULUMI_K8S_ROLLBACK_ON_FAILURE` environment variable.
+	RollbackOnFailure pulumi.BoolPtrInput
+	// If present and set to true, Warning Events reported for resources while they are awaited (and for the Pods and
+	// ReplicaSets they own) are shown as status messages as they arrive, e.g., a Pod that cannot be scheduled.
+	//
+	// This config can be specified in the following ways, using this precedence:
+	// 1. This `streamEvents` parameter.
+	// 2. The `PULUMI_K8S_STREAM_EVENTS` environment variable.
+	StreamEvents pulumi.BoolPtrInput
 	// If present and set to true, suppress apiVersion deprecation warnings from the CLI.
 	//
 	// This config can be specified in the following ways, using this precedence:
@@ -1,9 +1,31 @@
 // *** WARNING: this file was generated by pulumigen. ***
 // *** Do not edit by hand unless you're certain you know what you are doing! ***
 
+import * as pulumi from "@pulumi/pulumi";
+import * as utilities from "../utilities";
+
+// Export members:
+export * from "./patch";
+
 // Export sub-modules:
 import * as v1 from "./v1";
 
 export {
     v1,
 };
+
+// Import resources to register:
+import { Patch } from "./patch";
+
+const _module = {
+    version: utilities.getVersion(),
+    construct: (name: string, type: string, urn: string): pulumi.Resource => {
+        switch (type) {
+            case "kubernetes:core:Patch":
+                return new Patch(name, <any>undefined, { urn })
+            default:
+                throw new Error(`unknown resource type ${type}`);
+        }
+    },
+};
+pulumi.runtime.registerResourceModule("kubernetes", "core", _module)
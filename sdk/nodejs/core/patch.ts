@@ -0,0 +1,135 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import * as pulumi from "@pulumi/pulumi";
+import { input as inputs, output as outputs } from "../types";
+import * as utilities from "../utilities";
+
+/**
+ * Patch applies some of the fields of an existing object, e.g., a label on the `kube-system` Namespace, or an annotation on the Pod template of a DaemonSet that is managed by someone else. The fields are applied with server-side apply, so that fields set by other field managers are left as they are. Deleting the Patch removes its fields from the object, unless `retainOnDelete` is set. Atomic lists, e.g., `tolerations`, are replaced as a whole, so a Patch that sets one must set all of its items.
+ */
+export class Patch extends pulumi.CustomResource {
+    /**
+     * Get an existing Patch resource's state with the given name, ID, and optional extra
+     * properties used to qualify the lookup.
+     *
+     * @param name The _unique_ name of the resulting resource.
+     * @param id The _unique_ provider ID of the resource to lookup.
+     * @param opts Optional settings to control the behavior of the CustomResource.
+     */
+    public static get(name: string, id: pulumi.Input<pulumi.ID>, opts?: pulumi.CustomResourceOptions): Patch {
+        return new Patch(name, undefined as any, { ...opts, id: id });
+    }
+
+    /** @internal */
+    public static readonly __pulumiType = 'kubernetes:core:Patch';
+
+    /**
+     * Returns true if the given object is an instance of Patch.  This is designed to work even
+     * when multiple copies of the Pulumi SDK have been loaded into the same process.
+     */
+    public static isInstance(obj: any): obj is Patch {
+        if (obj === undefined || obj === null) {
+            return false;
+        }
+        return obj['__pulumiType'] === Patch.__pulumiType;
+    }
+
+    /**
+     * The apiVersion of the object to patch, e.g., `apps/v1`.
+     */
+    public readonly apiVersion!: pulumi.Output<string>;
+    /**
+     * The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+     */
+    public readonly fields!: pulumi.Output<{[key: string]: any}>;
+    /**
+     * If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+     */
+    public readonly force!: pulumi.Output<boolean>;
+    /**
+     * The kind of the object to patch, e.g., `DaemonSet`.
+     */
+    public readonly kind!: pulumi.Output<string>;
+    /**
+     * The name and namespace of the object to patch, and the labels and annotations to set on it.
+     */
+    public readonly metadata!: pulumi.Output<outputs.meta.v1.ObjectMeta>;
+    /**
+     * If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+     */
+    public readonly retainOnDelete!: pulumi.Output<boolean>;
+
+    /**
+     * Create a Patch resource with the given unique name, arguments, and options.
+     *
+     * @param name The _unique_ name of the resource.
+     * @param args The arguments to use to populate this resource's properties.
+     * @param opts A bag of options that control this resource's behavior.
+     */
+    constructor(name: string, args?: PatchArgs, opts?: pulumi.CustomResourceOptions) {
+        let inputs: pulumi.Inputs = {};
+        if (!(opts && opts.id)) {
+            if ((!args || args.apiVersion === undefined) && !(opts && opts.urn)) {
+                throw new Error("Missing required property 'apiVersion'");
+            }
+            if ((!args || args.kind === undefined) && !(opts && opts.urn)) {
+                throw new Error("Missing required property 'kind'");
+            }
+            if ((!args || args.metadata === undefined) && !(opts && opts.urn)) {
+                throw new Error("Missing required property 'metadata'");
+            }
+            inputs["apiVersion"] = args ? args.apiVersion : undefined;
+            inputs["fields"] = args ? args.fields : undefined;
+            inputs["force"] = args ? args.force : undefined;
+            inputs["kind"] = args ? args.kind : undefined;
+            inputs["metadata"] = args ? args.metadata : undefined;
+            inputs["retainOnDelete"] = args ? args.retainOnDelete : undefined;
+        } else {
+            inputs["apiVersion"] = undefined /*out*/;
+            inputs["fields"] = undefined /*out*/;
+            inputs["force"] = undefined /*out*/;
+            inputs["kind"] = undefined /*out*/;
+            inputs["metadata"] = undefined /*out*/;
+            inputs["retainOnDelete"] = undefined /*out*/;
+        }
+        if (!opts) {
+            opts = {}
+        }
+
+        if (!opts.version) {
+            opts.version = utilities.getVersion();
+        }
+        super(Patch.__pulumiType, name, inputs, opts);
+    }
+}
+
+/**
+ * The set of arguments for constructing a Patch resource.
+ */
+export interface PatchArgs {
+    /**
+     * The apiVersion of the object to patch, e.g., `apps/v1`.
+     */
+    readonly apiVersion: pulumi.Input<string>;
+    /**
+     * The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+     */
+    readonly fields?: pulumi.Input<{[key: string]: any}>;
+    /**
+     * If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+     */
+    readonly force?: pulumi.Input<boolean>;
+    /**
+     * The kind of the object to patch, e.g., `DaemonSet`.
+     */
+    readonly kind: pulumi.Input<string>;
+    /**
+     * The name and namespace of the object to patch, and the labels and annotations to set on it.
+     */
+    readonly metadata: pulumi.Input<inputs.meta.v1.ObjectMeta>;
+    /**
+     * If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+     */
+    readonly retainOnDelete?: pulumi.Input<boolean>;
+}
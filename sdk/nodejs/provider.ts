@@ -35,10 +35,19 @@ export class Provider extends pulumi.ProviderResource {
         {
             inputs["cluster"] = args ? args.cluster : undefined;
             inputs["context"] = args ? args.context : undefined;
+            inputs["defaultTimeouts"] = ((args ? args.defaultTimeouts : undefined) || utilities.getEnv("PULUMI_K8S_DEFAULT_TIMEOUTS")) ?? utilities.getEnv("PULUMI_K8S_DEFAULT_TIMEOUTS");
+            inputs["deleteGracePeriodSeconds"] = pulumi.output(args ? args.deleteGracePeriodSeconds : undefined).apply(JSON.stringify);
+            inputs["deletePropagationPolicy"] = ((args ? args.deletePropagationPolicy : undefined) || utilities.getEnv("PULUMI_K8S_DELETE_PROPAGATION_POLICY")) ?? utilities.getEnv("PULUMI_K8S_DELETE_PROPAGATION_POLICY");
             inputs["enableDryRun"] = pulumi.output(((args ? args.enableDryRun : undefined) || <any>utilities.getEnvBoolean("PULUMI_K8S_ENABLE_DRY_RUN")) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_ENABLE_DRY_RUN")).apply(JSON.stringify);
+            inputs["enableServerSideApply"] = pulumi.output(((args ? args.enableServerSideApply : undefined) || <any>utilities.getEnvBoolean("PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY")) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY")).apply(JSON.stringify);
+            inputs["fieldManager"] = ((args ? args.fieldManager : undefined) || utilities.getEnv("PULUMI_K8S_FIELD_MANAGER")) ?? utilities.getEnv("PULUMI_K8S_FIELD_MANAGER");
+            inputs["ignoreFields"] = ((args ? args.ignoreFields : undefined) || utilities.getEnv("PULUMI_K8S_IGNORE_FIELDS")) ?? utilities.getEnv("PULUMI_K8S_IGNORE_FIELDS");
             inputs["kubeconfig"] = ((args ? args.kubeconfig : undefined) || utilities.getEnv("KUBECONFIG")) ?? utilities.getEnv("KUBECONFIG");
             inputs["namespace"] = args ? args.namespace : undefined;
+            inputs["recordAwaitEventsToDirectory"] = ((args ? args.recordAwaitEventsToDirectory : undefined) || utilities.getEnv("PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY")) ?? utilities.getEnv("PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY");
             inputs["renderYamlToDirectory"] = args ? args.renderYamlToDirectory : undefined;
+            inputs["rollbackOnFailure"] = pulumi.output(((args ? args.rollbackOnFailure : undefined) || <any>utilities.getEnvBoolean("PULUMI_K8S_ROLLBACK_ON_FAILURE")) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_ROLLBACK_ON_FAILURE")).apply(JSON.stringify);
+            inputs["streamEvents"] = pulumi.output(((args ? args.streamEvents : undefined) || <any>utilities.getEnvBoolean("PULUMI_K8S_STREAM_EVENTS")) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_STREAM_EVENTS")).apply(JSON.stringify);
             inputs["suppressDeprecationWarnings"] = pulumi.output(((args ? args.suppressDeprecationWarnings : undefined) || <any>utilities.getEnvBoolean("PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS")) ?? <any>utilities.getEnvBoolean("PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS")).apply(JSON.stringify);
         }
         if (!opts) {
@@ -64,6 +73,33 @@ export interface ProviderArgs {
      * If present, the name of the kubeconfig context to use.
      */
     readonly context?: pulumi.Input<string>;
+    /**
+     * A JSON object that overrides the default await timeouts, in seconds, for the kinds of resources it lists, e.g.,
+     * `{"apps/v1/Deployment": 1200, "Job": 3600, "multiplier": 2}`. Keys are kinds, optionally qualified with their
+     * apiVersion, which take precedence. The optional `multiplier` entry scales every default timeout. Timeouts specified for
+     * a resource with `customTimeouts` or the `pulumi.com/timeoutSeconds` annotation are not affected.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `defaultTimeouts` parameter.
+     * 2. The `PULUMI_K8S_DEFAULT_TIMEOUTS` environment variable.
+     */
+    readonly defaultTimeouts?: pulumi.Input<string>;
+    /**
+     * If present, the grace period in seconds of the delete requests for resources. The default is the grace period of the resource, e.g., `terminationGracePeriodSeconds` for Pods. This can be overridden for a resource with the `pulumi.com/deleteGracePeriodSeconds` annotation.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `deleteGracePeriodSeconds` parameter.
+     * 2. The `PULUMI_K8S_DELETE_GRACE_PERIOD_SECONDS` environment variable.
+     */
+    readonly deleteGracePeriodSeconds?: pulumi.Input<number>;
+    /**
+     * If present, the propagation policy of the delete requests for resources: `Foreground`, `Background` or `Orphan`. The default depends on the version of the cluster, and is `Background` for current versions. This can be overridden for a resource with the `pulumi.com/deletePropagationPolicy` annotation.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `deletePropagationPolicy` parameter.
+     * 2. The `PULUMI_K8S_DELETE_PROPAGATION_POLICY` environment variable.
+     */
+    readonly deletePropagationPolicy?: pulumi.Input<string>;
     /**
      * BETA FEATURE - If present and set to true, enable server-side diff calculations.
      * This feature is in developer preview, and is disabled by default.
@@ -73,6 +109,34 @@ export interface ProviderArgs {
      * 2. The `PULUMI_K8S_ENABLE_DRY_RUN` environment variable.
      */
     readonly enableDryRun?: pulumi.Input<boolean>;
+    /**
+     * If present and set to true, create and update resources with server-side apply, rather than with patches computed by
+     * the provider. Fields set by other controllers are left as they are, and the
+     * `kubectl.kubernetes.io/last-applied-configuration` annotation is not written. Applies that set fields owned by other
+     * field managers fail, unless the resource has the `pulumi.com/patchForce` annotation. Creating an object that
+     * already exists also fails, unless it has the annotation.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `enableServerSideApply` parameter.
+     * 2. The `PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY` environment variable.
+     */
+    readonly enableServerSideApply?: pulumi.Input<boolean>;
+    /**
+     * The name of the field manager used for server-side apply. Defaults to `pulumi-kubernetes`.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `fieldManager` parameter.
+     * 2. The `PULUMI_K8S_FIELD_MANAGER` environment variable.
+     */
+    readonly fieldManager?: pulumi.Input<string>;
+    /**
+     * If present, the fields of resources that are managed by someone else, e.g., `spec.replicas` of Deployments that are scaled by a HorizontalPodAutoscaler. This is a JSON object that maps a GVK (e.g., `apps/v1/Deployment`) or a kind (e.g., `MutatingWebhookConfiguration`) to a list of field paths (e.g., `["webhooks[*].clientConfig.caBundle"]`). Changes to these fields are not shown in diffs, and updates preserve their live values, or leave them out of the applied object with server-side apply. Fields can also be ignored for a resource with the `pulumi.com/ignoreFields` annotation.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `ignoreFields` parameter.
+     * 2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.
+     */
+    readonly ignoreFields?: pulumi.Input<string>;
     /**
      * The contents of a kubeconfig file or the path to a kubeconfig file. If this is set, this config will be used instead of $KUBECONFIG.
      */
@@ -86,6 +150,15 @@ export interface ProviderArgs {
      * 3. `namespace` set for the active context in the kubeconfig.
      */
     readonly namespace?: pulumi.Input<string>;
+    /**
+     * If present, the watch events received while awaiting resources are recorded as JSON files in this
+     * directory, so that they can be replayed in tests of the await logic.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `recordAwaitEventsToDirectory` parameter.
+     * 2. The `PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY` environment variable.
+     */
+    readonly recordAwaitEventsToDirectory?: pulumi.Input<string>;
     /**
      * BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
      * be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
@@ -97,6 +170,26 @@ export interface ProviderArgs {
      * used in these resources will be rendered in plaintext to the resulting YAML.
      */
     readonly renderYamlToDirectory?: pulumi.Input<string>;
+    /**
+     * If present and set to true, roll back Deployments whose update fails to become ready by re-applying
+     * their previous configuration. The update is still reported as failed.
+     *
+     * This config can be overridden for a resource with the `pulumi.com/rollbackOnFailure` annotation.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `rollbackOnFailure` parameter.
+     * 2. The `PULUMI_K8S_ROLLBACK_ON_FAILURE` environment variable.
+     */
+    readonly rollbackOnFailure?: pulumi.Input<boolean>;
+    /**
+     * If present and set to true, Warning Events reported for resources while they are awaited (and for the Pods and
+     * ReplicaSets they own) are shown as status messages as they arrive, e.g., a Pod that cannot be scheduled.
+     *
+     * This config can be specified in the following ways, using this precedence:
+     * 1. This `streamEvents` parameter.
+     * 2. The `PULUMI_K8S_STREAM_EVENTS` environment variable.
+     */
+    readonly streamEvents?: pulumi.Input<boolean>;
     /**
      * If present and set to true, suppress apiVersion deprecation warnings from the CLI.
      *
@@ -102,7 +102,10 @@ SNAKE_TO_CAMEL_CASE_TABLE = {
     "default_mode": "defaultMode",
     "default_request": "defaultRequest",
     "default_runtime_class_name": "defaultRuntimeClassName",
+    "default_timeouts": "defaultTimeouts",
+    "delete_grace_period_seconds": "deleteGracePeriodSeconds",
     "delete_options": "deleteOptions",
+    "delete_propagation_policy": "deletePropagationPolicy",
     "deletion_grace_period_seconds": "deletionGracePeriodSeconds",
     "deletion_timestamp": "deletionTimestamp",
     "deprecated_count": "deprecatedCount",
@@ -127,6 +130,7 @@ SNAKE_TO_CAMEL_CASE_TABLE = {
     "dry_run": "dryRun",
     "empty_dir": "emptyDir",
     "enable_dry_run": "enableDryRun",
+    "enable_server_side_apply": "enableServerSideApply",
     "enable_service_links": "enableServiceLinks",
     "endpoints_namespace": "endpointsNamespace",
     "env_from": "envFrom",
@@ -150,6 +154,7 @@ SNAKE_TO_CAMEL_CASE_TABLE = {
     "failed_jobs_history_limit": "failedJobsHistoryLimit",
     "failure_policy": "failurePolicy",
     "failure_threshold": "failureThreshold",
+    "field_manager": "fieldManager",
     "field_path": "fieldPath",
     "field_ref": "fieldRef",
     "fields_type": "fieldsType",
@@ -190,6 +195,7 @@ SNAKE_TO_CAMEL_CASE_TABLE = {
     "host_ports": "hostPorts",
     "http_get": "httpGet",
     "http_headers": "httpHeaders",
+    "ignore_fields": "ignoreFields",
     "image_id": "imageID",
     "image_pull_policy": "imagePullPolicy",
     "image_pull_secrets": "imagePullSecrets",
@@ -336,6 +342,7 @@ SNAKE_TO_CAMEL_CASE_TABLE = {
     "readiness_probe": "readinessProbe",
     "ready_replicas": "readyReplicas",
     "reclaim_policy": "reclaimPolicy",
+    "record_await_events_to_directory": "recordAwaitEventsToDirectory",
     "reinvocation_policy": "reinvocationPolicy",
     "remaining_item_count": "remainingItemCount",
     "render_yaml_to_directory": "renderYamlToDirectory",
@@ -353,9 +360,11 @@ SNAKE_TO_CAMEL_CASE_TABLE = {
     "resource_version": "resourceVersion",
     "restart_count": "restartCount",
     "restart_policy": "restartPolicy",
+    "retain_on_delete": "retainOnDelete",
     "retry_after_seconds": "retryAfterSeconds",
     "revision_history_limit": "revisionHistoryLimit",
     "role_ref": "roleRef",
+    "rollback_on_failure": "rollbackOnFailure",
     "rollback_to": "rollbackTo",
     "rolling_update": "rollingUpdate",
     "run_as_group": "runAsGroup",
@@ -418,6 +427,7 @@ SNAKE_TO_CAMEL_CASE_TABLE = {
     "storage_pool": "storagePool",
     "storage_version_hash": "storageVersionHash",
     "stored_versions": "storedVersions",
+    "stream_events": "streamEvents",
     "string_data": "stringData",
     "sub_path": "subPath",
     "sub_path_expr": "subPathExpr",
@@ -580,7 +590,10 @@ CAMEL_TO_SNAKE_CASE_TABLE = {
     "defaultMode": "default_mode",
     "defaultRequest": "default_request",
     "defaultRuntimeClassName": "default_runtime_class_name",
+    "defaultTimeouts": "default_timeouts",
+    "deleteGracePeriodSeconds": "delete_grace_period_seconds",
     "deleteOptions": "delete_options",
+    "deletePropagationPolicy": "delete_propagation_policy",
     "deletionGracePeriodSeconds": "deletion_grace_period_seconds",
     "deletionTimestamp": "deletion_timestamp",
     "deprecatedCount": "deprecated_count",
@@ -605,6 +618,7 @@ CAMEL_TO_SNAKE_CASE_TABLE = {
     "dryRun": "dry_run",
     "emptyDir": "empty_dir",
     "enableDryRun": "enable_dry_run",
+    "enableServerSideApply": "enable_server_side_apply",
     "enableServiceLinks": "enable_service_links",
     "endpointsNamespace": "endpoints_namespace",
     "envFrom": "env_from",
@@ -628,6 +642,7 @@ CAMEL_TO_SNAKE_CASE_TABLE = {
     "failedJobsHistoryLimit": "failed_jobs_history_limit",
     "failurePolicy": "failure_policy",
     "failureThreshold": "failure_threshold",
+    "fieldManager": "field_manager",
     "fieldPath": "field_path",
     "fieldRef": "field_ref",
     "fieldsType": "fields_type",
@@ -668,6 +683,7 @@ CAMEL_TO_SNAKE_CASE_TABLE = {
     "hostPorts": "host_ports",
     "httpGet": "http_get",
     "httpHeaders": "http_headers",
+    "ignoreFields": "ignore_fields",
     "imageID": "image_id",
     "imagePullPolicy": "image_pull_policy",
     "imagePullSecrets": "image_pull_secrets",
@@ -814,6 +830,7 @@ CAMEL_TO_SNAKE_CASE_TABLE = {
     "readinessProbe": "readiness_probe",
     "readyReplicas": "ready_replicas",
     "reclaimPolicy": "reclaim_policy",
+    "recordAwaitEventsToDirectory": "record_await_events_to_directory",
     "reinvocationPolicy": "reinvocation_policy",
     "remainingItemCount": "remaining_item_count",
     "renderYamlToDirectory": "render_yaml_to_directory",
@@ -831,9 +848,11 @@ CAMEL_TO_SNAKE_CASE_TABLE = {
     "resourceVersion": "resource_version",
     "restartCount": "restart_count",
     "restartPolicy": "restart_policy",
+    "retainOnDelete": "retain_on_delete",
     "retryAfterSeconds": "retry_after_seconds",
     "revisionHistoryLimit": "revision_history_limit",
     "roleRef": "role_ref",
+    "rollbackOnFailure": "rollback_on_failure",
     "rollbackTo": "rollback_to",
     "rollingUpdate": "rolling_update",
     "runAsGroup": "run_as_group",
@@ -896,6 +915,7 @@ CAMEL_TO_SNAKE_CASE_TABLE = {
     "storagePool": "storage_pool",
     "storageVersionHash": "storage_version_hash",
     "storedVersions": "stored_versions",
+    "streamEvents": "stream_events",
     "stringData": "string_data",
     "subPath": "sub_path",
     "subPathExpr": "sub_path_expr",
@@ -0,0 +1,146 @@
+# coding=utf-8
+# *** WARNING: this file was generated by pulumigen. ***
+# *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import warnings
+import pulumi
+import pulumi.runtime
+from typing import Any, Mapping, Optional, Sequence, Union
+from .. import _utilities, _tables
+from .. import meta as _meta
+
+__all__ = ['Patch']
+
+
+class Patch(pulumi.CustomResource):
+    def __init__(__self__,
+                 resource_name: str,
+                 opts: Optional[pulumi.ResourceOptions] = None,
+                 api_version: Optional[pulumi.Input[str]] = None,
+                 fields: Optional[pulumi.Input[Mapping[str, Any]]] = None,
+                 force: Optional[pulumi.Input[bool]] = None,
+                 kind: Optional[pulumi.Input[str]] = None,
+                 metadata: Optional[pulumi.Input[pulumi.InputType['_meta.v1.ObjectMetaArgs']]] = None,
+                 retain_on_delete: Optional[pulumi.Input[bool]] = None,
+                 __props__=None,
+                 __name__=None,
+                 __opts__=None):
+        """
+        Patch applies some of the fields of an existing object, e.g., a label on the `kube-system` Namespace, or an annotation on the Pod template of a DaemonSet that is managed by someone else. The fields are applied with server-side apply, so that fields set by other field managers are left as they are. Deleting the Patch removes its fields from the object, unless `retainOnDelete` is set. Atomic lists, e.g., `tolerations`, are replaced as a whole, so a Patch that sets one must set all of its items.
+
+        :param str resource_name: The name of the resource.
+        :param pulumi.ResourceOptions opts: Options for the resource.
+        :param pulumi.Input[str] api_version: The apiVersion of the object to patch, e.g., `apps/v1`.
+        :param pulumi.Input[Mapping[str, Any]] fields: The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+        :param pulumi.Input[bool] force: If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+        :param pulumi.Input[str] kind: The kind of the object to patch, e.g., `DaemonSet`.
+        :param pulumi.Input[pulumi.InputType['_meta.v1.ObjectMetaArgs']] metadata: The name and namespace of the object to patch, and the labels and annotations to set on it.
+        :param pulumi.Input[bool] retain_on_delete: If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+        """
+        if __name__ is not None:
+            warnings.warn("explicit use of __name__ is deprecated", DeprecationWarning)
+            resource_name = __name__
+        if __opts__ is not None:
+            warnings.warn("explicit use of __opts__ is deprecated, use 'opts' instead", DeprecationWarning)
+            opts = __opts__
+        if opts is None:
+            opts = pulumi.ResourceOptions()
+        if not isinstance(opts, pulumi.ResourceOptions):
+            raise TypeError('Expected resource options to be a ResourceOptions instance')
+        if opts.version is None:
+            opts.version = _utilities.get_version()
+        if opts.id is None:
+            if __props__ is not None:
+                raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
+            __props__ = dict()
+
+            if api_version is None and not opts.urn:
+                raise TypeError("Missing required property 'api_version'")
+            __props__['api_version'] = api_version
+            __props__['fields'] = fields
+            __props__['force'] = force
+            if kind is None and not opts.urn:
+                raise TypeError("Missing required property 'kind'")
+            __props__['kind'] = kind
+            if metadata is None and not opts.urn:
+                raise TypeError("Missing required property 'metadata'")
+            __props__['metadata'] = metadata
+            __props__['retain_on_delete'] = retain_on_delete
+        super(Patch, __self__).__init__(
+            'kubernetes:core:Patch',
+            resource_name,
+            __props__,
+            opts)
+
+    @staticmethod
+    def get(resource_name: str,
+            id: pulumi.Input[str],
+            opts: Optional[pulumi.ResourceOptions] = None) -> 'Patch':
+        """
+        Get an existing Patch resource's state with the given name, id, and optional extra
+        properties used to qualify the lookup.
+
+        :param str resource_name: The unique name of the resulting resource.
+        :param pulumi.Input[str] id: The unique provider ID of the resource to lookup.
+        :param pulumi.ResourceOptions opts: Options for the resource.
+        """
+        opts = pulumi.ResourceOptions.merge(opts, pulumi.ResourceOptions(id=id))
+
+        __props__ = dict()
+
+        return Patch(resource_name, opts=opts, __props__=__props__)
+
+    @property
+    @pulumi.getter(name="apiVersion")
+    def api_version(self) -> pulumi.Output[str]:
+        """
+        The apiVersion of the object to patch, e.g., `apps/v1`.
+        """
+        return pulumi.get(self, "api_version")
+
+    @property
+    @pulumi.getter
+    def fields(self) -> pulumi.Output[Optional[Mapping[str, Any]]]:
+        """
+        The top-level fields to set on the object, other than `apiVersion`, `kind` and `metadata`, e.g., `{spec: {template: {metadata: {annotations: {...}}}}}`. Fields that are removed from the Patch are removed from the object.
+        """
+        return pulumi.get(self, "fields")
+
+    @property
+    @pulumi.getter
+    def force(self) -> pulumi.Output[Optional[bool]]:
+        """
+        If true, the Patch takes ownership of the fields that it sets, even if other field managers own them, e.g., a toleration on a DaemonSet that is managed by someone else. By default, applying a field that another field manager owns fails with a conflict. The `pulumi.com/patchForce` annotation is not used for Patches, since its annotations are set on the patched object.
+        """
+        return pulumi.get(self, "force")
+
+    @property
+    @pulumi.getter
+    def kind(self) -> pulumi.Output[str]:
+        """
+        The kind of the object to patch, e.g., `DaemonSet`.
+        """
+        return pulumi.get(self, "kind")
+
+    @property
+    @pulumi.getter
+    def metadata(self) -> pulumi.Output['_meta.v1.outputs.ObjectMeta']:
+        """
+        The name and namespace of the object to patch, and the labels and annotations to set on it.
+        """
+        return pulumi.get(self, "metadata")
+
+    @property
+    @pulumi.getter(name="retainOnDelete")
+    def retain_on_delete(self) -> pulumi.Output[Optional[bool]]:
+        """
+        If true, the patched fields are left in place when the Patch is deleted. By default, they are removed from the object, unless another field manager also sets them.
+        """
+        return pulumi.get(self, "retain_on_delete")
+
+    def translate_output_property(self, prop):
+        return _tables.CAMEL_TO_SNAKE_CASE_TABLE.get(prop) or prop
+
+    def translate_input_property(self, prop):
+        return _tables.SNAKE_TO_CAMEL_CASE_TABLE.get(prop) or prop
+
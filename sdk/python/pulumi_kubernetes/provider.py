@@ -17,10 +17,19 @@ class Provider(pulumi.ProviderResource):
                  opts: Optional[pulumi.ResourceOptions] = None,
                  cluster: Optional[pulumi.Input[str]] = None,
                  context: Optional[pulumi.Input[str]] = None,
+                 default_timeouts: Optional[pulumi.Input[str]] = None,
+                 delete_grace_period_seconds: Optional[pulumi.Input[int]] = None,
+                 delete_propagation_policy: Optional[pulumi.Input[str]] = None,
                  enable_dry_run: Optional[pulumi.Input[bool]] = None,
+                 enable_server_side_apply: Optional[pulumi.Input[bool]] = None,
+                 field_manager: Optional[pulumi.Input[str]] = None,
+                 ignore_fields: Optional[pulumi.Input[str]] = None,
                  kubeconfig: Optional[pulumi.Input[str]] = None,
                  namespace: Optional[pulumi.Input[str]] = None,
+                 record_await_events_to_directory: Optional[pulumi.Input[str]] = None,
                  render_yaml_to_directory: Optional[pulumi.Input[str]] = None,
+                 rollback_on_failure: Optional[pulumi.Input[bool]] = None,
+                 stream_events: Optional[pulumi.Input[bool]] = None,
                  suppress_deprecation_warnings: Optional[pulumi.Input[bool]] = None,
                  __props__=None,
                  __name__=None,
@@ -32,12 +41,49 @@ class Provider(pulumi.ProviderResource):
         :param pulumi.ResourceOptions opts: Options for the resource.
         :param pulumi.Input[str] cluster: If present, the name of the kubeconfig cluster to use.
         :param pulumi.Input[str] context: If present, the name of the kubeconfig context to use.
+        :param pulumi.Input[str] default_timeouts: A JSON object that overrides the default await timeouts, in seconds, for the kinds of resources it lists, e.g.,
+               `{"apps/v1/Deployment": 1200, "Job": 3600, "multiplier": 2}`. Keys are kinds, optionally qualified with their
+               apiVersion, which take precedence. The optional `multiplier` entry scales every default timeout. Timeouts specified for
+               a resource with `customTimeouts` or the `pulumi.com/timeoutSeconds` annotation are not affected.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `defaultTimeouts` parameter.
+               2. The `PULUMI_K8S_DEFAULT_TIMEOUTS` environment variable.
+        :param pulumi.Input[int] delete_grace_period_seconds: If present, the grace period in seconds of the delete requests for resources. The default is the grace period of the resource, e.g., `terminationGracePeriodSeconds` for Pods. This can be overridden for a resource with the `pulumi.com/deleteGracePeriodSeconds` annotation.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `deleteGracePeriodSeconds` parameter.
+               2. The `PULUMI_K8S_DELETE_GRACE_PERIOD_SECONDS` environment variable.
+        :param pulumi.Input[str] delete_propagation_policy: If present, the propagation policy of the delete requests for resources: `Foreground`, `Background` or `Orphan`. The default depends on the version of the cluster, and is `Background` for current versions. This can be overridden for a resource with the `pulumi.com/deletePropagationPolicy` annotation.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `deletePropagationPolicy` parameter.
+               2. The `PULUMI_K8S_DELETE_PROPAGATION_POLICY` environment variable.
         :param pulumi.Input[bool] enable_dry_run: BETA FEATURE - If present and set to true, enable server-side diff calculations.
                This feature is in developer preview, and is disabled by default.
                
                This config can be specified in the following ways, using this precedence:
                1. This `enableDryRun` parameter.
                2. The `PULUMI_K8S_ENABLE_DRY_RUN` environment variable.
+        :param pulumi.Input[bool] enable_server_side_apply: If present and set to true, create and update resources with server-side apply, rather than with patches computed by
+               the provider. Fields set by other controllers are left as they are, and the
+               `kubectl.kubernetes.io/last-applied-configuration` annotation is not written. Applies that set fields owned by other
+               field managers fail, unless the resource has the `pulumi.com/patchForce` annotation. Creating an object that
+               already exists also fails, unless it has the annotation.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `enableServerSideApply` parameter.
+               2. The `PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY` environment variable.
+        :param pulumi.Input[str] field_manager: The name of the field manager used for server-side apply. Defaults to `pulumi-kubernetes`.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `fieldManager` parameter.
+               2. The `PULUMI_K8S_FIELD_MANAGER` environment variable.
+        :param pulumi.Input[str] ignore_fields: If present, the fields of resources that are managed by someone else, e.g., `spec.replicas` of Deployments that are scaled by a HorizontalPodAutoscaler. This is a JSON object that maps a GVK (e.g., `apps/v1/Deployment`) or a kind (e.g., `MutatingWebhookConfiguration`) to a list of field paths (e.g., `["webhooks[*].clientConfig.caBundle"]`). Changes to these fields are not shown in diffs, and updates preserve their live values, or leave them out of the applied object with server-side apply. Fields can also be ignored for a resource with the `pulumi.com/ignoreFields` annotation.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `ignoreFields` parameter.
+               2. The `PULUMI_K8S_IGNORE_FIELDS` environment variable.
         :param pulumi.Input[str] kubeconfig: The contents of a kubeconfig file or the path to a kubeconfig file. If this is set, this config will be used instead of $KUBECONFIG.
         :param pulumi.Input[str] namespace: If present, the default namespace to use. This flag is ignored for cluster-scoped resources.
                
@@ -45,6 +91,12 @@ class Provider(pulumi.ProviderResource):
                1. `.metadata.namespace` set on the resource.
                2. This `namespace` parameter.
                3. `namespace` set for the active context in the kubeconfig.
+        :param pulumi.Input[str] record_await_events_to_directory: If present, the watch events received while awaiting resources are recorded as JSON files in this
+               directory, so that they can be replayed in tests of the await logic.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `recordAwaitEventsToDirectory` parameter.
+               2. The `PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY` environment variable.
         :param pulumi.Input[str] render_yaml_to_directory: BETA FEATURE - If present, render resource manifests to this directory. In this mode, resources will not
                be created on a Kubernetes cluster, but the rendered manifests will be kept in sync with changes
                to the Pulumi program. This feature is in developer preview, and is disabled by default.
@@ -53,6 +105,20 @@ class Provider(pulumi.ProviderResource):
                since the resources are not created on a Kubernetes cluster. These Output values will remain undefined,
                and may result in an error if they are referenced by other resources. Also note that any secret values
                used in these resources will be rendered in plaintext to the resulting YAML.
+        :param pulumi.Input[bool] rollback_on_failure: If present and set to true, roll back Deployments whose update fails to become ready by re-applying
+               their previous configuration. The update is still reported as failed.
+               
+               This config can be overridden for a resource with the `pulumi.com/rollbackOnFailure` annotation.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `rollbackOnFailure` parameter.
+               2. The `PULUMI_K8S_ROLLBACK_ON_FAILURE` environment variable.
+        :param pulumi.Input[bool] stream_events: If present and set to true, Warning Events reported for resources while they are awaited (and for the Pods and
+               ReplicaSets they own) are shown as status messages as they arrive, e.g., a Pod that cannot be scheduled.
+               
+               This config can be specified in the following ways, using this precedence:
+               1. This `streamEvents` parameter.
+               2. The `PULUMI_K8S_STREAM_EVENTS` environment variable.
         :param pulumi.Input[bool] suppress_deprecation_warnings: If present and set to true, suppress apiVersion deprecation warnings from the CLI.
                
                This config can be specified in the following ways, using this precedence:
@@ -78,14 +144,39 @@ class Provider(pulumi.ProviderResource):
 
             __props__['cluster'] = cluster
             __props__['context'] = context
+            if default_timeouts is None:
+                default_timeouts = _utilities.get_env('PULUMI_K8S_DEFAULT_TIMEOUTS')
+            __props__['default_timeouts'] = default_timeouts
+            __props__['delete_grace_period_seconds'] = pulumi.Output.from_input(delete_grace_period_seconds).apply(pulumi.runtime.to_json) if delete_grace_period_seconds is not None else None
+            if delete_propagation_policy is None:
+                delete_propagation_policy = _utilities.get_env('PULUMI_K8S_DELETE_PROPAGATION_POLICY')
+            __props__['delete_propagation_policy'] = delete_propagation_policy
             if enable_dry_run is None:
                 enable_dry_run = _utilities.get_env_bool('PULUMI_K8S_ENABLE_DRY_RUN')
             __props__['enable_dry_run'] = pulumi.Output.from_input(enable_dry_run).apply(pulumi.runtime.to_json) if enable_dry_run is not None else None
+            if enable_server_side_apply is None:
+                enable_server_side_apply = _utilities.get_env_bool('PULUMI_K8S_ENABLE_SERVER_SIDE_APPLY')
+            __props__['enable_server_side_apply'] = pulumi.Output.from_input(enable_server_side_apply).apply(pulumi.runtime.to_json) if enable_server_side_apply is not None else None
+            if field_manager is None:
+                field_manager = _utilities.get_env('PULUMI_K8S_FIELD_MANAGER')
+            __props__['field_manager'] = field_manager
+            if ignore_fields is None:
+                ignore_fields = _utilities.get_env('PULUMI_K8S_IGNORE_FIELDS')
+            __props__['ignore_fields'] = ignore_fields
             if kubeconfig is None:
                 kubeconfig = _utilities.get_env('KUBECONFIG')
             __props__['kubeconfig'] = kubeconfig
             __props__['namespace'] = namespace
+            if record_await_events_to_directory is None:
+                record_await_events_to_directory = _utilities.get_env('PULUMI_K8S_RECORD_AWAIT_EVENTS_TO_DIRECTORY')
+            __props__['record_await_events_to_directory'] = record_await_events_to_directory
             __props__['render_yaml_to_directory'] = render_yaml_to_directory
+            if rollback_on_failure is None:
+                rollback_on_failure = _utilities.get_env_bool('PULUMI_K8S_ROLLBACK_ON_FAILURE')
+            __props__['rollback_on_failure'] = pulumi.Output.from_input(rollback_on_failure).apply(pulumi.runtime.to_json) if rollback_on_failure is not None else None
+            if stream_events is None:
+                stream_events = _utilities.get_env_bool('PULUMI_K8S_STREAM_EVENTS')
+            __props__['stream_events'] = pulumi.Output.from_input(stream_events).apply(pulumi.runtime.to_json) if stream_events is not None else None
             if suppress_deprecation_warnings is None:
                 suppress_deprecation_warnings = _utilities.get_env_bool('PULUMI_K8S_SUPPRESS_DEPRECATION_WARNINGS')
             __props__['suppress_deprecation_warnings'] = pulumi.Output.from_input(suppress_deprecation_warnings).apply(pulumi.runtime.to_json) if suppress_deprecation_warnings is not None else None